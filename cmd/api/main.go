@@ -1,16 +1,39 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"image"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
 	"github.com/xcurvnubaim/njajal-gin-golang/internal/configs"
 	"github.com/xcurvnubaim/njajal-gin-golang/internal/database"
 	"github.com/xcurvnubaim/njajal-gin-golang/internal/middleware"
 	"github.com/xcurvnubaim/njajal-gin-golang/internal/modules/auth"
 	"github.com/xcurvnubaim/njajal-gin-golang/internal/modules/shortlink"
+	"github.com/xcurvnubaim/njajal-gin-golang/internal/modules/webhook"
+	"github.com/xcurvnubaim/njajal-gin-golang/internal/pkg/geoip"
+	"github.com/xcurvnubaim/njajal-gin-golang/internal/pkg/qr"
+	"github.com/xcurvnubaim/njajal-gin-golang/internal/pkg/shortcode"
+	CustomValidator "github.com/xcurvnubaim/njajal-gin-golang/internal/pkg/validator"
 )
 
+// shutdownTimeout bounds how long in-flight requests may take to finish on
+// SIGINT or SIGTERM.
+const shutdownTimeout = 30 * time.Second
+
 func main() {
 	// Setup configuration
 	configs.Setup(".env")
@@ -21,8 +44,18 @@ func main() {
 		fmt.Println("Production mode")
 	}
 
+	// Setup custom validations
+	if err := setupAliasValidation(); err != nil {
+		panic(err)
+	}
+
 	// Start the server
 	r := gin.Default()
+	// Rate limits, geo rules and click hashing use the client IP, which
+	// must not come from a header any client can set.
+	if err := r.SetTrustedProxies(trustedProxies()); err != nil {
+		panic(err)
+	}
 	r.Use(middleware.CORSMiddleware())
 	// Setup Database
 	db, err := database.Setup()
@@ -34,17 +67,167 @@ func main() {
 	var authService auth.IAuthUseCase = auth.NewAuthUseCase(authRepository)
 	auth.NewAuthHandler(r, authService, "/api/v1/auth")
 
-	var shortlinkRepository shortlink.IRepository = shortlink.NewRepository(db)
-	var shortlinkService shortlink.IUseCase = shortlink.NewuseCase(shortlinkRepository)
+	var linkCache shortlink.LinkCache = shortlink.NewLRULinkCache(shortlink.DefaultLinkCacheSize, shortlink.DefaultLinkCacheTTL, shortlink.DefaultLinkCacheNegativeTTL)
+	var clickCounter shortlink.ClickCounter = shortlink.NewMemoryClickCounter()
+	if configs.Config.CACHE_DRIVER == shortlink.CacheDriverRedis {
+		redisClient, err := database.SetupRedis()
+		if err != nil {
+			panic(err)
+		}
+		linkCache = shortlink.NewRedisLinkCache(redisClient, shortlink.DefaultLinkCacheTTL, shortlink.DefaultLinkCacheNegativeTTL)
+		clickCounter = shortlink.NewRedisClickCounter(redisClient)
+	}
+
+	var webhookRepository webhook.IRepository = webhook.NewRepository(db)
+	webhookEmitter := webhook.NewEmitter(webhookRepository, webhook.DefaultEmitterBufferSize)
+	webhookEmitter.Start()
+	webhookWorker := webhook.NewDeliveryWorker(webhookRepository, webhook.DeliveryWorkerOptions{})
+	webhookWorker.Start()
+
+	var shortlinkRepository shortlink.IRepository = shortlink.NewCachedRepository(shortlink.NewRepository(db), linkCache)
+	clickRecorder := shortlink.NewClickRecorder(shortlinkRepository, 10000, 500, 5*time.Second)
+	clickRecorder.Start()
+	expirySweeper := shortlink.NewExpirySweeper(shortlinkRepository, webhookEmitter, time.Minute)
+	expirySweeper.Start()
+	clickCountFlusher := shortlink.NewClickCountFlusher(clickCounter, shortlinkRepository, 30*time.Second)
+	clickCountFlusher.Start()
+	healthChecker := shortlink.NewHealthChecker(shortlinkRepository, shortlink.HealthCheckerOptions{})
+	healthChecker.Start()
+	shortCodeLength, err := parseOptionalInt("SHORT_CODE_LENGTH", configs.Config.SHORT_CODE_LENGTH, 0)
+	if err != nil {
+		panic(err)
+	}
+	shortCodeNodeID, err := parseOptionalInt("SHORT_CODE_NODE_ID", configs.Config.SHORT_CODE_NODE_ID, 64)
+	if err != nil {
+		panic(err)
+	}
+	codeGenerator, err := shortcode.New(shortcode.Options{
+		Strategy: configs.Config.SHORT_CODE_STRATEGY,
+		Length:   int(shortCodeLength),
+		Salt:     configs.Config.SHORT_CODE_SALT,
+		NodeID:   shortCodeNodeID,
+	})
+	if err != nil {
+		panic(err)
+	}
+	urlPolicy := shortlink.NewURLPolicy(shortlinkRepository, shortlink.URLPolicyOptions{
+		OwnHosts:       ownHosts(),
+		BlocklistFile:  configs.Config.DOMAIN_BLOCKLIST_FILE,
+		ReloadInterval: time.Minute,
+	})
+	urlPolicy.Start()
+	var qrLogo image.Image
+	if configs.Config.QR_LOGO_FILE != "" {
+		if qrLogo, err = qr.LoadLogo(configs.Config.QR_LOGO_FILE); err != nil {
+			panic(err)
+		}
+	}
+	var countryResolver geoip.CountryResolver
+	if configs.Config.GEOIP_DB_FILE != "" {
+		geoReader, err := geoip.Open(configs.Config.GEOIP_DB_FILE)
+		if err != nil {
+			panic(err)
+		}
+		defer geoReader.Close()
+		countryResolver = geoReader
+	}
+	domainVerifier := shortlink.NewDomainVerifier(nil, shortlink.DefaultDomainVerifyTimeout)
+	var shortlinkService shortlink.IUseCase = shortlink.NewuseCase(shortlinkRepository, clickRecorder, clickCounter, codeGenerator, urlPolicy, qrLogo, countryResolver, domainVerifier, webhookEmitter)
 	shortlink.NewHandler(r, shortlinkService, "/api/v1/shortener-link")
 
+	var webhookService webhook.IUseCase = webhook.NewuseCase(webhookRepository, urlPolicy, webhookEmitter)
+	webhook.NewHandler(r, webhookService, "/api/v1/webhooks")
+
 	r.GET("/ping", func(c *gin.Context) {
 		c.JSON(200, gin.H{
 			"message": "pong test",
 		})
 	})
 
-	if err := r.Run(":" + configs.Config.APP_PORT); err != nil {
-		panic(err)
+	server := &http.Server{
+		Addr:    ":" + configs.Config.APP_PORT,
+		Handler: r,
+	}
+	go func() {
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			panic(err)
+		}
+	}()
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+	log.Println("Shutting down")
+
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(ctx); err != nil {
+		log.Println("Failed to finish in-flight requests:", err)
+	}
+
+	// Stop producers before the workers they feed, so the clicks, counters
+	// and events buffered in memory are flushed instead of lost.
+	urlPolicy.Stop()
+	healthChecker.Stop()
+	expirySweeper.Stop()
+	clickRecorder.Stop()
+	clickCountFlusher.Stop()
+	webhookEmitter.Stop()
+	webhookWorker.Stop()
+}
+
+func setupAliasValidation() error {
+	reserved := append([]string{}, CustomValidator.DefaultReservedAliases...)
+	if configs.Config.RESERVED_ALIASES != "" {
+		reserved = append(reserved, strings.Split(configs.Config.RESERVED_ALIASES, ",")...)
+	}
+
+	var blocklist CustomValidator.Blocklist
+	if configs.Config.ALIAS_BLOCKLIST_FILE != "" {
+		words, err := CustomValidator.LoadWordBlocklist(configs.Config.ALIAS_BLOCKLIST_FILE)
+		if err != nil {
+			return err
+		}
+		blocklist = words
+	}
+
+	policy := CustomValidator.NewAliasPolicy(CustomValidator.AliasMinLength, CustomValidator.AliasMaxLength, reserved, blocklist)
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		return CustomValidator.RegisterAliasValidation(v, policy)
+	}
+	return nil
+}
+
+// ownHosts returns the hosts short links are served from.
+func ownHosts() []string {
+	baseURL, err := url.Parse(configs.Config.BASE_URL)
+	if err != nil || baseURL.Hostname() == "" {
+		return nil
+	}
+	return []string{baseURL.Hostname()}
+}
+
+// trustedProxies returns the configured reverse proxies, or nil to use the
+// address of the connection as the client IP.
+func trustedProxies() []string {
+	var proxies []string
+	for _, proxy := range strings.Split(configs.Config.TRUSTED_PROXIES, ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			proxies = append(proxies, proxy)
+		}
+	}
+	return proxies
+}
+
+// parseOptionalInt parses the numeric setting name. An empty value is zero,
+// which leaves the choice to the default of the setting's consumer.
+func parseOptionalInt(name, value string, bitSize int) (int64, error) {
+	if value == "" {
+		return 0, nil
+	}
+	n, err := strconv.ParseInt(value, 10, bitSize)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", name, err)
 	}
+	return n, nil
 }
@@ -17,6 +17,8 @@ type ConfigEnv struct {
 	ENV_MODE string
 	APP_PORT string
 
+	TRUSTED_PROXIES string
+
 	JWT_SECRET string
 
 	EMAIL_FROM string
@@ -26,6 +28,33 @@ type ConfigEnv struct {
 	SMTP_PASSWORD string
 
 	BASE_URL string
+
+	IP_HASH_SALT string
+
+	EXPIRED_LINK_FALLBACK_URL string
+
+	CACHE_DRIVER   string
+	REDIS_ADDR     string
+	REDIS_PASSWORD string
+	REDIS_DB       string
+
+	SHORT_CODE_STRATEGY string
+	SHORT_CODE_LENGTH   string
+	SHORT_CODE_SALT     string
+	SHORT_CODE_NODE_ID  string
+
+	RESERVED_ALIASES     string
+	ALIAS_BLOCKLIST_FILE string
+
+	DOMAIN_BLOCKLIST_FILE string
+
+	QR_LOGO_FILE string
+
+	GEOIP_DB_FILE string
+
+	APPLE_APP_IDS                 string
+	ANDROID_APP_PACKAGE           string
+	ANDROID_APP_CERT_FINGERPRINTS string
 }
 
 var Config = &ConfigEnv{}
@@ -46,6 +75,8 @@ func Setup(pathEnv string) {
 	Config.ENV_MODE = os.Getenv("ENV_MODE")
 	Config.APP_PORT = os.Getenv("APP_PORT")
 
+	Config.TRUSTED_PROXIES = os.Getenv("TRUSTED_PROXIES")
+
 	Config.JWT_SECRET = os.Getenv("JWT_SECRET")
 
 	Config.EMAIL_FROM = os.Getenv("EMAIL_FROM")
@@ -55,4 +86,31 @@ func Setup(pathEnv string) {
 	Config.SMTP_PASSWORD = os.Getenv("SMTP_PASSWORD")
 	
 	Config.BASE_URL = os.Getenv("BASE_URL")
+
+	Config.IP_HASH_SALT = os.Getenv("IP_HASH_SALT")
+
+	Config.EXPIRED_LINK_FALLBACK_URL = os.Getenv("EXPIRED_LINK_FALLBACK_URL")
+
+	Config.CACHE_DRIVER = os.Getenv("CACHE_DRIVER")
+	Config.REDIS_ADDR = os.Getenv("REDIS_ADDR")
+	Config.REDIS_PASSWORD = os.Getenv("REDIS_PASSWORD")
+	Config.REDIS_DB = os.Getenv("REDIS_DB")
+
+	Config.SHORT_CODE_STRATEGY = os.Getenv("SHORT_CODE_STRATEGY")
+	Config.SHORT_CODE_LENGTH = os.Getenv("SHORT_CODE_LENGTH")
+	Config.SHORT_CODE_SALT = os.Getenv("SHORT_CODE_SALT")
+	Config.SHORT_CODE_NODE_ID = os.Getenv("SHORT_CODE_NODE_ID")
+
+	Config.RESERVED_ALIASES = os.Getenv("RESERVED_ALIASES")
+	Config.ALIAS_BLOCKLIST_FILE = os.Getenv("ALIAS_BLOCKLIST_FILE")
+
+	Config.DOMAIN_BLOCKLIST_FILE = os.Getenv("DOMAIN_BLOCKLIST_FILE")
+
+	Config.QR_LOGO_FILE = os.Getenv("QR_LOGO_FILE")
+
+	Config.GEOIP_DB_FILE = os.Getenv("GEOIP_DB_FILE")
+
+	Config.APPLE_APP_IDS = os.Getenv("APPLE_APP_IDS")
+	Config.ANDROID_APP_PACKAGE = os.Getenv("ANDROID_APP_PACKAGE")
+	Config.ANDROID_APP_CERT_FINGERPRINTS = os.Getenv("ANDROID_APP_CERT_FINGERPRINTS")
 }
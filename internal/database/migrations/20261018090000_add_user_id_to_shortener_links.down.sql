@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_shortener_links_user_id;
+
+ALTER TABLE shortener_links DROP COLUMN user_id;
\ No newline at end of file
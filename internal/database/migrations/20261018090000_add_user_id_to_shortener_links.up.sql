@@ -0,0 +1,4 @@
+ALTER TABLE shortener_links
+    ADD COLUMN user_id UUID REFERENCES users(id) ON DELETE CASCADE;
+
+CREATE INDEX idx_shortener_links_user_id ON shortener_links(user_id);
\ No newline at end of file
@@ -0,0 +1 @@
+DROP TABLE shortener_link_clicks;
\ No newline at end of file
@@ -0,0 +1,16 @@
+CREATE TABLE shortener_link_clicks (
+    id UUID PRIMARY KEY,
+    shortener_link_id UUID NOT NULL REFERENCES shortener_links(id) ON DELETE CASCADE,
+    clicked_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    referrer TEXT,
+    user_agent TEXT,
+    ip_hash VARCHAR(64),
+    device VARCHAR(32),
+    browser VARCHAR(64),
+    os VARCHAR(64),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP
+);
+
+CREATE INDEX idx_shortener_link_clicks_link_clicked_at ON shortener_link_clicks(shortener_link_id, clicked_at);
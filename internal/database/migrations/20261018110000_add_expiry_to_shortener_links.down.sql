@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_shortener_links_expires_at;
+
+ALTER TABLE shortener_links
+    DROP COLUMN active_from,
+    DROP COLUMN expires_at,
+    DROP COLUMN max_clicks,
+    DROP COLUMN click_count,
+    DROP COLUMN is_expired;
\ No newline at end of file
@@ -0,0 +1,8 @@
+ALTER TABLE shortener_links
+    ADD COLUMN active_from TIMESTAMP,
+    ADD COLUMN expires_at TIMESTAMP,
+    ADD COLUMN max_clicks INTEGER,
+    ADD COLUMN click_count BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN is_expired BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX idx_shortener_links_expires_at ON shortener_links(expires_at) WHERE is_expired = FALSE;
\ No newline at end of file
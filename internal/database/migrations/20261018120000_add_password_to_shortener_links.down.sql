@@ -0,0 +1 @@
+ALTER TABLE shortener_links DROP COLUMN password_hash;
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE shortener_links ADD COLUMN password_hash VARCHAR(255);
\ No newline at end of file
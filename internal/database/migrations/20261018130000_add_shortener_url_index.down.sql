@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_shortener_links_shortener_url;
\ No newline at end of file
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_shortener_links_shortener_url;
+
+CREATE INDEX idx_shortener_links_shortener_url ON shortener_links(shortener_url);
\ No newline at end of file
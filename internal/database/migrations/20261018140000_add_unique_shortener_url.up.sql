@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_shortener_links_shortener_url;
+
+CREATE UNIQUE INDEX idx_shortener_links_shortener_url ON shortener_links(shortener_url) WHERE deleted_at IS NULL;
\ No newline at end of file
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_shortener_links_shortener_url;
+
+-- Aliases are unique regardless of case, while redirects still look codes up exactly.
+CREATE UNIQUE INDEX idx_shortener_links_shortener_url_lower ON shortener_links(LOWER(shortener_url)) WHERE deleted_at IS NULL;
+CREATE INDEX idx_shortener_links_shortener_url ON shortener_links(shortener_url);
\ No newline at end of file
@@ -0,0 +1 @@
+DROP TABLE blocked_domains;
\ No newline at end of file
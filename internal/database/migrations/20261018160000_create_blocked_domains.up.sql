@@ -0,0 +1,11 @@
+CREATE TABLE blocked_domains (
+    id UUID PRIMARY KEY,
+    domain VARCHAR(255) NOT NULL,
+    reason TEXT,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_blocked_domains_domain ON blocked_domains(domain) WHERE deleted_at IS NULL;
\ No newline at end of file
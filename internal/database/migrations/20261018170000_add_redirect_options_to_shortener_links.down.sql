@@ -0,0 +1,3 @@
+ALTER TABLE shortener_links
+    DROP COLUMN redirect_status,
+    DROP COLUMN forward_query;
\ No newline at end of file
@@ -0,0 +1,3 @@
+ALTER TABLE shortener_links
+    ADD COLUMN redirect_status SMALLINT NOT NULL DEFAULT 302,
+    ADD COLUMN forward_query BOOLEAN NOT NULL DEFAULT FALSE;
\ No newline at end of file
@@ -0,0 +1 @@
+DROP TABLE shortener_link_rules;
\ No newline at end of file
@@ -0,0 +1,13 @@
+CREATE TABLE shortener_link_rules (
+    id UUID PRIMARY KEY,
+    shortener_link_id UUID NOT NULL REFERENCES shortener_links(id) ON DELETE CASCADE,
+    position INT NOT NULL,
+    type VARCHAR(20) NOT NULL,
+    match_values TEXT[] NOT NULL,
+    destination_url TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP
+);
+
+CREATE INDEX idx_shortener_link_rules_link_position ON shortener_link_rules(shortener_link_id, position);
\ No newline at end of file
@@ -0,0 +1,3 @@
+ALTER TABLE shortener_link_clicks DROP COLUMN variant_id;
+
+DROP TABLE shortener_link_variants;
\ No newline at end of file
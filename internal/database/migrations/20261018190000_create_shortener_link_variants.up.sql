@@ -0,0 +1,17 @@
+CREATE TABLE shortener_link_variants (
+    id UUID PRIMARY KEY,
+    shortener_link_id UUID NOT NULL REFERENCES shortener_links(id) ON DELETE CASCADE,
+    position INT NOT NULL,
+    name VARCHAR(50) NOT NULL,
+    destination_url TEXT NOT NULL,
+    weight INT NOT NULL CHECK (weight > 0),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP
+);
+
+CREATE INDEX idx_shortener_link_variants_link_position ON shortener_link_variants(shortener_link_id, position);
+
+ALTER TABLE shortener_link_clicks ADD COLUMN variant_id UUID REFERENCES shortener_link_variants(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_shortener_link_clicks_variant ON shortener_link_clicks(variant_id) WHERE variant_id IS NOT NULL;
\ No newline at end of file
@@ -0,0 +1,3 @@
+ALTER TABLE shortener_links DROP COLUMN campaign_id;
+
+DROP TABLE campaigns;
\ No newline at end of file
@@ -0,0 +1,19 @@
+CREATE TABLE campaigns (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    utm_source VARCHAR(100) NOT NULL DEFAULT '',
+    utm_medium VARCHAR(100) NOT NULL DEFAULT '',
+    utm_campaign VARCHAR(100) NOT NULL DEFAULT '',
+    utm_term VARCHAR(100) NOT NULL DEFAULT '',
+    utm_content VARCHAR(100) NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_campaigns_user_name ON campaigns(user_id, LOWER(name)) WHERE deleted_at IS NULL;
+
+ALTER TABLE shortener_links ADD COLUMN campaign_id UUID REFERENCES campaigns(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_shortener_links_campaign_id ON shortener_links(campaign_id) WHERE campaign_id IS NOT NULL;
\ No newline at end of file
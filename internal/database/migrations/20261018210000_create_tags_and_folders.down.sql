@@ -0,0 +1,7 @@
+DROP TABLE shortener_link_tags;
+
+DROP TABLE tags;
+
+ALTER TABLE shortener_links DROP COLUMN folder_id;
+
+DROP TABLE folders;
\ No newline at end of file
@@ -0,0 +1,33 @@
+CREATE TABLE folders (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(50) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_folders_user_name ON folders(user_id, LOWER(name)) WHERE deleted_at IS NULL;
+
+ALTER TABLE shortener_links ADD COLUMN folder_id UUID REFERENCES folders(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_shortener_links_folder_id ON shortener_links(folder_id) WHERE folder_id IS NOT NULL;
+
+CREATE TABLE tags (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(50) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_tags_user_name ON tags(user_id, LOWER(name)) WHERE deleted_at IS NULL;
+
+CREATE TABLE shortener_link_tags (
+    shortener_link_id UUID NOT NULL REFERENCES shortener_links(id) ON DELETE CASCADE,
+    tag_id UUID NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+    PRIMARY KEY (shortener_link_id, tag_id)
+);
+
+CREATE INDEX idx_shortener_link_tags_tag_id ON shortener_link_tags(tag_id);
\ No newline at end of file
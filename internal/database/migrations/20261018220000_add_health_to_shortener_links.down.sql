@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS idx_shortener_links_is_broken;
+
+DROP INDEX IF EXISTS idx_shortener_links_health_checked_at;
+
+ALTER TABLE shortener_links
+    DROP COLUMN is_broken,
+    DROP COLUMN health_status_code,
+    DROP COLUMN health_latency_ms,
+    DROP COLUMN health_error,
+    DROP COLUMN health_checked_at;
\ No newline at end of file
@@ -0,0 +1,10 @@
+ALTER TABLE shortener_links
+    ADD COLUMN is_broken BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN health_status_code INT,
+    ADD COLUMN health_latency_ms INT,
+    ADD COLUMN health_error VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN health_checked_at TIMESTAMP;
+
+CREATE INDEX idx_shortener_links_health_checked_at ON shortener_links(health_checked_at NULLS FIRST) WHERE deleted_at IS NULL AND is_expired = FALSE;
+
+CREATE INDEX idx_shortener_links_is_broken ON shortener_links(user_id) WHERE is_broken = TRUE AND deleted_at IS NULL;
\ No newline at end of file
@@ -0,0 +1,4 @@
+ALTER TABLE shortener_links
+    DROP COLUMN preview_title,
+    DROP COLUMN preview_description,
+    DROP COLUMN preview_image_url;
\ No newline at end of file
@@ -0,0 +1,4 @@
+ALTER TABLE shortener_links
+    ADD COLUMN preview_title VARCHAR(200) NOT NULL DEFAULT '',
+    ADD COLUMN preview_description VARCHAR(500) NOT NULL DEFAULT '',
+    ADD COLUMN preview_image_url TEXT NOT NULL DEFAULT '';
\ No newline at end of file
@@ -0,0 +1 @@
+DROP TABLE shortener_link_revisions;
\ No newline at end of file
@@ -0,0 +1,14 @@
+CREATE TABLE shortener_link_revisions (
+    id UUID PRIMARY KEY,
+    shortener_link_id UUID NOT NULL REFERENCES shortener_links(id) ON DELETE CASCADE,
+    version INT NOT NULL,
+    user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    action VARCHAR(20) NOT NULL,
+    restored_from INT,
+    snapshot JSONB NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_shortener_link_revisions_version ON shortener_link_revisions(shortener_link_id, version);
\ No newline at end of file
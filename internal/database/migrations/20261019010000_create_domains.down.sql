@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_shortener_links_domain_shortener_url_lower;
+DROP INDEX IF EXISTS idx_shortener_links_shortener_url_lower;
+-- Links on branded domains would collide with BASE_URL codes.
+DELETE FROM shortener_links WHERE domain_id IS NOT NULL;
+CREATE UNIQUE INDEX idx_shortener_links_shortener_url_lower ON shortener_links(LOWER(shortener_url)) WHERE deleted_at IS NULL;
+
+ALTER TABLE shortener_links DROP COLUMN domain_id;
+
+DROP TABLE domains;
\ No newline at end of file
@@ -0,0 +1,21 @@
+CREATE TABLE domains (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    hostname VARCHAR(253) NOT NULL,
+    verification_token VARCHAR(64) NOT NULL,
+    verified_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP
+);
+
+-- Anyone may claim a hostname, but only one account can have it verified.
+CREATE UNIQUE INDEX idx_domains_user_hostname ON domains(user_id, hostname) WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX idx_domains_verified_hostname ON domains(hostname) WHERE verified_at IS NOT NULL AND deleted_at IS NULL;
+
+ALTER TABLE shortener_links ADD COLUMN domain_id UUID REFERENCES domains(id);
+
+-- Codes are unique per domain; links without a domain are served from BASE_URL.
+DROP INDEX IF EXISTS idx_shortener_links_shortener_url_lower;
+CREATE UNIQUE INDEX idx_shortener_links_shortener_url_lower ON shortener_links(LOWER(shortener_url)) WHERE domain_id IS NULL AND deleted_at IS NULL;
+CREATE UNIQUE INDEX idx_shortener_links_domain_shortener_url_lower ON shortener_links(domain_id, LOWER(shortener_url)) WHERE domain_id IS NOT NULL AND deleted_at IS NULL;
\ No newline at end of file
@@ -0,0 +1,2 @@
+ALTER TABLE shortener_links
+    DROP COLUMN stats_public;
\ No newline at end of file
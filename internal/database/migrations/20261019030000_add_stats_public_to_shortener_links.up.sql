@@ -0,0 +1,2 @@
+ALTER TABLE shortener_links
+    ADD COLUMN stats_public BOOLEAN NOT NULL DEFAULT FALSE;
\ No newline at end of file
@@ -0,0 +1,2 @@
+DROP TABLE bio_page_items;
+DROP TABLE bio_pages;
\ No newline at end of file
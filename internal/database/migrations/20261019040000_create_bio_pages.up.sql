@@ -0,0 +1,29 @@
+CREATE TABLE bio_pages (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    slug VARCHAR(32) NOT NULL,
+    title VARCHAR(100) NOT NULL,
+    description VARCHAR(500) NOT NULL DEFAULT '',
+    avatar_url TEXT NOT NULL DEFAULT '',
+    theme VARCHAR(20) NOT NULL DEFAULT 'light',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_bio_pages_slug_lower ON bio_pages(LOWER(slug)) WHERE deleted_at IS NULL;
+CREATE INDEX idx_bio_pages_user_id ON bio_pages(user_id);
+
+CREATE TABLE bio_page_items (
+    id UUID PRIMARY KEY,
+    bio_page_id UUID NOT NULL REFERENCES bio_pages(id) ON DELETE CASCADE,
+    shortener_link_id UUID NOT NULL REFERENCES shortener_links(id) ON DELETE CASCADE,
+    position INT NOT NULL,
+    label VARCHAR(100) NOT NULL DEFAULT '',
+    click_count BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP
+);
+
+CREATE INDEX idx_bio_page_items_page_position ON bio_page_items(bio_page_id, position);
\ No newline at end of file
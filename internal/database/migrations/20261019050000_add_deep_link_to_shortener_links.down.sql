@@ -0,0 +1,5 @@
+ALTER TABLE shortener_links
+    DROP COLUMN deep_link_ios_app_url,
+    DROP COLUMN deep_link_ios_store_url,
+    DROP COLUMN deep_link_android_app_url,
+    DROP COLUMN deep_link_android_store_url;
\ No newline at end of file
@@ -0,0 +1,5 @@
+ALTER TABLE shortener_links
+    ADD COLUMN deep_link_ios_app_url TEXT NOT NULL DEFAULT '',
+    ADD COLUMN deep_link_ios_store_url TEXT NOT NULL DEFAULT '',
+    ADD COLUMN deep_link_android_app_url TEXT NOT NULL DEFAULT '',
+    ADD COLUMN deep_link_android_store_url TEXT NOT NULL DEFAULT '';
\ No newline at end of file
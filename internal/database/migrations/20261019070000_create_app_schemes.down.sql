@@ -0,0 +1 @@
+DROP TABLE app_schemes;
\ No newline at end of file
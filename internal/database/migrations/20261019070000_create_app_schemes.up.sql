@@ -0,0 +1,10 @@
+CREATE TABLE app_schemes (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    scheme VARCHAR(32) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_app_schemes_user_scheme ON app_schemes(user_id, scheme) WHERE deleted_at IS NULL;
\ No newline at end of file
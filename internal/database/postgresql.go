@@ -23,7 +23,10 @@ func Setup() (*gorm.DB, error) {
 
 	db, err := gorm.Open(postgres.New(postgres.Config{
 		DSN: dsn,
-	}), &gorm.Config{})
+	}), &gorm.Config{
+		// Map driver errors such as unique violations to gorm.ErrDuplicatedKey.
+		TranslateError: true,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to database: %v", err)
 	}
@@ -0,0 +1,32 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/xcurvnubaim/njajal-gin-golang/internal/configs"
+)
+
+func SetupRedis() (*redis.Client, error) {
+	db, err := strconv.Atoi(configs.Config.REDIS_DB)
+	if err != nil {
+		db = 0
+	}
+
+	client := redis.NewClient(&redis.Options{
+		Addr:     configs.Config.REDIS_ADDR,
+		Password: configs.Config.REDIS_PASSWORD,
+		DB:       db,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		return nil, fmt.Errorf("failed to connect to redis: %v", err)
+	}
+
+	return client, nil
+}
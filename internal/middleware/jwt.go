@@ -22,6 +22,7 @@ func AuthenticateJWT() gin.HandlerFunc {
 		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
 		if tokenString == authHeader {
 			c.JSON(401, app.NewErrorResponse("Invalid token format", nil))
+			c.Abort()
 			return
 		}
 
@@ -49,10 +50,15 @@ func AuthenticateJWT() gin.HandlerFunc {
 	}
 }
 
+// IsAdmin reports whether the authenticated user has the admin role.
+func IsAdmin(c *gin.Context) bool {
+	role, exists := c.Get("role")
+	return exists && role == "admin"
+}
+
 func VerifyAdmin() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		role, exists := c.Get("role")
-		if !exists || role != "admin" {
+		if !IsAdmin(c) {
 			c.JSON(403, app.NewErrorResponse("You do not have permission to access this resource", nil))
 			c.Abort()
 			return
@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/xcurvnubaim/njajal-gin-golang/internal/pkg/app"
+)
+
+type rateLimitWindow struct {
+	count   int
+	resetAt time.Time
+}
+
+// RateLimitByIP allows at most limit requests per client IP within each
+// fixed window. State is kept in memory, so limits apply per instance. The
+// client IP only comes from X-Forwarded-For when the request passed through
+// one of the engine's trusted proxies.
+func RateLimitByIP(limit int, window time.Duration) gin.HandlerFunc {
+	var mu sync.Mutex
+	windows := make(map[string]*rateLimitWindow)
+	nextCleanup := time.Now().Add(window)
+
+	return func(c *gin.Context) {
+		now := time.Now()
+		ip := c.ClientIP()
+
+		mu.Lock()
+		if now.After(nextCleanup) {
+			for key, w := range windows {
+				if now.After(w.resetAt) {
+					delete(windows, key)
+				}
+			}
+			nextCleanup = now.Add(window)
+		}
+
+		w, ok := windows[ip]
+		if !ok || now.After(w.resetAt) {
+			w = &rateLimitWindow{resetAt: now.Add(window)}
+			windows[ip] = w
+		}
+		w.count++
+		exceeded := w.count > limit
+		mu.Unlock()
+
+		if exceeded {
+			c.JSON(429, app.NewErrorResponse("Too many requests, please try again later", nil))
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,12 @@
+package auth
+
+import "time"
+
+const (
+	// AccessTokenTTL is kept short as access tokens cannot be revoked;
+	// sessions are extended with refresh tokens instead.
+	AccessTokenTTL  = 15 * time.Minute
+	RefreshTokenTTL = 30 * 24 * time.Hour
+	// RefreshTokenBytes is the entropy of a refresh token before encoding.
+	RefreshTokenBytes = 32
+)
@@ -18,6 +18,19 @@ type (
 		VerifiedAt   *time.Time `gorm:"default:null"`
 	}
 
+	// RefreshTokenModel is one refresh token of a session. Only the SHA-256
+	// hash of the token is stored. Every refresh rotates the token and keeps
+	// the FamilyID, which identifies the session.
+	RefreshTokenModel struct {
+		common.BaseModels
+		UserID    uuid.UUID  `gorm:"column:user_id;type:uuid;not null"`
+		FamilyID  uuid.UUID  `gorm:"column:family_id;type:uuid;not null"`
+		TokenHash string     `gorm:"column:token_hash;not null"`
+		ExpiresAt time.Time  `gorm:"column:expires_at;not null"`
+		UsedAt    *time.Time `gorm:"column:used_at;default:null"`
+		RevokedAt *time.Time `gorm:"column:revoked_at;default:null"`
+	}
+
 	PayloadToken struct {
 		ID   uuid.UUID
 		Role string
@@ -28,6 +41,20 @@ func (UserModel) TableName() string {
 	return "users"
 }
 
+func (RefreshTokenModel) TableName() string {
+	return "refresh_tokens"
+}
+
+func NewRefreshToken(userID, familyID uuid.UUID, tokenHash string, expiresAt time.Time) *RefreshTokenModel {
+	return &RefreshTokenModel{
+		BaseModels: common.NewBaseModels(),
+		UserID:     userID,
+		FamilyID:   familyID,
+		TokenHash:  tokenHash,
+		ExpiresAt:  expiresAt,
+	}
+}
+
 func NewUser(email, password, otp string, otpExpiredAt time.Time) *UserModel {
 	return &UserModel{
 		BaseModels:   common.NewBaseModels(),
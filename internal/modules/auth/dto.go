@@ -9,9 +9,28 @@ type (
 	}
 
 	LoginUserResponseDTO struct {
-		Email string `json:"email"`
-		Roles string `json:"roles"`
-		Token string `json:"token"`
+		Email        string `json:"email"`
+		Roles        string `json:"roles"`
+		Token        string `json:"token"`
+		RefreshToken string `json:"refresh_token"`
+		// ExpiresIn is the lifetime of Token in seconds.
+		ExpiresIn int64 `json:"expires_in"`
+	}
+
+	RefreshTokenRequestDTO struct {
+		RefreshToken string `json:"refresh_token" binding:"required"`
+	}
+
+	// RefreshTokenResponseDTO replaces both tokens; the refresh token sent
+	// in the request can no longer be used.
+	RefreshTokenResponseDTO struct {
+		Token        string `json:"token"`
+		RefreshToken string `json:"refresh_token"`
+		ExpiresIn    int64  `json:"expires_in"`
+	}
+
+	LogoutRequestDTO struct {
+		RefreshToken string `json:"refresh_token" binding:"required"`
 	}
 
 	RegisterUserRequestDTO struct {
@@ -28,6 +28,8 @@ func (ah *AuthHandler) Routes(prefix string) {
 		authentication.POST("/register", ah.Register)
 		authentication.POST("/login", ah.Login)
 		authentication.POST("/verify", ah.VerifyUser)
+		authentication.POST("/refresh", ah.RefreshToken)
+		authentication.POST("/logout", ah.Logout)
 
 		authentication.Use(middleware.AuthenticateJWT())
 		{
@@ -151,4 +153,41 @@ func (ah *AuthHandler) VerifyUser(c *gin.Context) {
 	}
 
 	c.JSON(200, app.NewSuccessResponse("OTP verified successfully", res))
-}
\ No newline at end of file
+}
+
+func (ah *AuthHandler) RefreshToken(c *gin.Context) {
+	var data RefreshTokenRequestDTO
+	if err := c.ShouldBindJSON(&data); err != nil {
+		var errMessages = CustomValidator.FormatValidationErrors(err)
+		c.JSON(400, app.NewErrorResponse("Validation Error", &errMessages))
+		return
+	}
+
+	res, err := ah.authUseCase.RefreshToken(&data)
+	if err != nil {
+		errMsg := err.Error()
+		c.JSON(err.Code(), app.NewErrorResponse("Failed to refresh token", &errMsg))
+		return
+	}
+
+	c.JSON(200, app.NewSuccessResponse("Token refreshed successfully", res))
+}
+
+// Logout takes the refresh token rather than the access token, so a client
+// whose access token already expired can still end its session.
+func (ah *AuthHandler) Logout(c *gin.Context) {
+	var data LogoutRequestDTO
+	if err := c.ShouldBindJSON(&data); err != nil {
+		var errMessages = CustomValidator.FormatValidationErrors(err)
+		c.JSON(400, app.NewErrorResponse("Validation Error", &errMessages))
+		return
+	}
+
+	if err := ah.authUseCase.Logout(&data); err != nil {
+		errMsg := err.Error()
+		c.JSON(err.Code(), app.NewErrorResponse("Failed to logout user", &errMsg))
+		return
+	}
+
+	c.JSON(200, app.NewSuccessResponse[any]("User logged out successfully", nil))
+}
@@ -0,0 +1,113 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/xcurvnubaim/njajal-gin-golang/internal/pkg/e"
+)
+
+// RefreshToken exchanges a refresh token for a new access token and a new
+// refresh token. A token that was already exchanged is being reused, which
+// means it leaked: the whole session is revoked.
+func (uc *authUseCase) RefreshToken(data *RefreshTokenRequestDTO) (*RefreshTokenResponseDTO, e.ApiError) {
+	current, err := uc.authRepository.GetRefreshTokenByHash(hashRefreshToken(data.RefreshToken))
+	if err != nil {
+		return nil, e.NewApiError(401, "Invalid refresh token")
+	}
+
+	if current.RevokedAt != nil {
+		return nil, e.NewApiError(401, "Refresh token has been revoked")
+	}
+
+	if current.UsedAt != nil {
+		return nil, uc.revokeReusedRefreshToken(current)
+	}
+
+	now := time.Now()
+	if !now.Before(current.ExpiresAt) {
+		return nil, e.NewApiError(401, "Refresh token has expired")
+	}
+
+	user, err := uc.authRepository.GetUserByID(current.UserID)
+	if err != nil {
+		return nil, e.NewApiError(401, "User not found")
+	}
+
+	accessToken, errToken := uc.GenerateToken(PayloadToken{ID: user.ID, Role: user.Role})
+	if errToken != nil {
+		log.Println(errToken.Error())
+		return nil, e.NewApiError(500, fmt.Sprintf("Internal Server Error (%d)", e.ERROR_GENERATE_TOKEN_FAILED))
+	}
+
+	refreshToken, next, errToken := newRefreshToken(user.ID, current.FamilyID, now)
+	if errToken != nil {
+		log.Println(errToken.Error())
+		return nil, e.NewApiError(500, fmt.Sprintf("Internal Server Error (%d)", e.ERROR_GENERATE_TOKEN_FAILED))
+	}
+
+	rotated, err := uc.authRepository.RotateRefreshToken(current, next)
+	if err != nil {
+		log.Println(err.Error())
+		return nil, e.NewApiError(500, fmt.Sprintf("Internal Server Error (%d)", err.Code()))
+	}
+	if !rotated {
+		// Another request exchanged the same token first.
+		return nil, uc.revokeReusedRefreshToken(current)
+	}
+
+	return &RefreshTokenResponseDTO{
+		Token:        accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int64(AccessTokenTTL.Seconds()),
+	}, nil
+}
+
+// Logout revokes the session of the given refresh token. Access tokens
+// already issued for it stay valid until they expire.
+func (uc *authUseCase) Logout(data *LogoutRequestDTO) e.ApiError {
+	token, err := uc.authRepository.GetRefreshTokenByHash(hashRefreshToken(data.RefreshToken))
+	if err != nil {
+		return e.NewApiError(401, "Invalid refresh token")
+	}
+
+	if err := uc.authRepository.RevokeRefreshTokenFamily(token.FamilyID); err != nil {
+		log.Println(err.Error())
+		return e.NewApiError(500, fmt.Sprintf("Internal Server Error (%d)", err.Code()))
+	}
+
+	return nil
+}
+
+func (uc *authUseCase) revokeReusedRefreshToken(token *RefreshTokenModel) e.ApiError {
+	log.Println("Refresh token reuse detected, revoking session", token.FamilyID)
+	if err := uc.authRepository.RevokeRefreshTokenFamily(token.FamilyID); err != nil {
+		log.Println(err.Error())
+		return e.NewApiError(500, fmt.Sprintf("Internal Server Error (%d)", err.Code()))
+	}
+	return e.NewApiError(401, "Refresh token has already been used; the session has been revoked")
+}
+
+// newRefreshToken returns an opaque token and the model storing its hash.
+func newRefreshToken(userID, familyID uuid.UUID, now time.Time) (string, *RefreshTokenModel, error) {
+	raw := make([]byte, RefreshTokenBytes)
+	if _, err := rand.Read(raw); err != nil {
+		return "", nil, err
+	}
+
+	token := base64.RawURLEncoding.EncodeToString(raw)
+	return token, NewRefreshToken(userID, familyID, hashRefreshToken(token), now.Add(RefreshTokenTTL)), nil
+}
+
+// hashRefreshToken is a plain SHA-256: the tokens are random, so a slow hash
+// adds nothing.
+func hashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
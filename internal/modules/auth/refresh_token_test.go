@@ -0,0 +1,147 @@
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/xcurvnubaim/njajal-gin-golang/internal/configs"
+	"github.com/xcurvnubaim/njajal-gin-golang/internal/pkg/e"
+)
+
+type refreshTokenRepositoryStub struct {
+	IAuthRepository
+	user   *UserModel
+	tokens map[string]*RefreshTokenModel
+}
+
+func newRefreshTokenRepositoryStub() *refreshTokenRepositoryStub {
+	return &refreshTokenRepositoryStub{
+		user:   NewUser("jane@example.com", "hash", "", time.Now()),
+		tokens: make(map[string]*RefreshTokenModel),
+	}
+}
+
+func (s *refreshTokenRepositoryStub) GetUserByID(id uuid.UUID) (*UserModel, e.ApiError) {
+	if id != s.user.ID {
+		return nil, e.NewApiError(e.ERROR_GET_USER_BY_ID_REPOSITORY_FAILED, "record not found")
+	}
+	return s.user, nil
+}
+
+func (s *refreshTokenRepositoryStub) CreateRefreshToken(token *RefreshTokenModel) e.ApiError {
+	s.tokens[token.TokenHash] = token
+	return nil
+}
+
+func (s *refreshTokenRepositoryStub) GetRefreshTokenByHash(tokenHash string) (*RefreshTokenModel, e.ApiError) {
+	token, ok := s.tokens[tokenHash]
+	if !ok {
+		return nil, e.NewApiError(e.ERROR_GET_REFRESH_TOKEN_REPOSITORY_FAILED, "record not found")
+	}
+	copied := *token
+	return &copied, nil
+}
+
+func (s *refreshTokenRepositoryStub) RotateRefreshToken(current, next *RefreshTokenModel) (bool, e.ApiError) {
+	stored := s.tokens[current.TokenHash]
+	if stored.UsedAt != nil || stored.RevokedAt != nil {
+		return false, nil
+	}
+	now := time.Now()
+	stored.UsedAt = &now
+	s.tokens[next.TokenHash] = next
+	return true, nil
+}
+
+func (s *refreshTokenRepositoryStub) RevokeRefreshTokenFamily(familyID uuid.UUID) e.ApiError {
+	now := time.Now()
+	for _, token := range s.tokens {
+		if token.FamilyID == familyID && token.RevokedAt == nil {
+			token.RevokedAt = &now
+		}
+	}
+	return nil
+}
+
+func (s *refreshTokenRepositoryStub) login(t *testing.T) string {
+	refreshToken, token, err := newRefreshToken(s.user.ID, uuid.New(), time.Now())
+	assert.NoError(t, err)
+	s.tokens[token.TokenHash] = token
+	return refreshToken
+}
+
+func TestRefreshToken(t *testing.T) {
+	secret := configs.Config.JWT_SECRET
+	configs.Config.JWT_SECRET = "test-secret"
+	defer func() { configs.Config.JWT_SECRET = secret }()
+
+	t.Run("Rotates the refresh token", func(t *testing.T) {
+		repo := newRefreshTokenRepositoryStub()
+		uc := NewAuthUseCase(repo)
+		first := repo.login(t)
+
+		res, errApi := uc.RefreshToken(&RefreshTokenRequestDTO{RefreshToken: first})
+		assert.Nil(t, errApi)
+		assert.NotEmpty(t, res.Token)
+		assert.NotEqual(t, first, res.RefreshToken)
+		assert.Equal(t, int64(AccessTokenTTL.Seconds()), res.ExpiresIn)
+
+		second, errApi := uc.RefreshToken(&RefreshTokenRequestDTO{RefreshToken: res.RefreshToken})
+		assert.Nil(t, errApi)
+		assert.NotEmpty(t, second.RefreshToken)
+	})
+
+	t.Run("Reuse revokes the whole family", func(t *testing.T) {
+		repo := newRefreshTokenRepositoryStub()
+		uc := NewAuthUseCase(repo)
+		first := repo.login(t)
+		other := repo.login(t)
+
+		res, errApi := uc.RefreshToken(&RefreshTokenRequestDTO{RefreshToken: first})
+		assert.Nil(t, errApi)
+
+		_, errApi = uc.RefreshToken(&RefreshTokenRequestDTO{RefreshToken: first})
+		assert.Equal(t, 401, errApi.Code())
+		assert.Equal(t, "Refresh token has already been used; the session has been revoked", errApi.Error())
+
+		_, errApi = uc.RefreshToken(&RefreshTokenRequestDTO{RefreshToken: res.RefreshToken})
+		assert.Equal(t, 401, errApi.Code())
+		assert.Equal(t, "Refresh token has been revoked", errApi.Error())
+
+		// Other sessions of the user are left alone.
+		_, errApi = uc.RefreshToken(&RefreshTokenRequestDTO{RefreshToken: other})
+		assert.Nil(t, errApi)
+	})
+
+	t.Run("Expired", func(t *testing.T) {
+		repo := newRefreshTokenRepositoryStub()
+		uc := NewAuthUseCase(repo)
+		refreshToken := repo.login(t)
+		repo.tokens[hashRefreshToken(refreshToken)].ExpiresAt = time.Now().Add(-time.Second)
+
+		_, errApi := uc.RefreshToken(&RefreshTokenRequestDTO{RefreshToken: refreshToken})
+		assert.Equal(t, 401, errApi.Code())
+		assert.Equal(t, "Refresh token has expired", errApi.Error())
+	})
+
+	t.Run("Unknown", func(t *testing.T) {
+		uc := NewAuthUseCase(newRefreshTokenRepositoryStub())
+
+		_, errApi := uc.RefreshToken(&RefreshTokenRequestDTO{RefreshToken: "not-a-token"})
+		assert.Equal(t, 401, errApi.Code())
+	})
+
+	t.Run("Logout revokes the session", func(t *testing.T) {
+		repo := newRefreshTokenRepositoryStub()
+		uc := NewAuthUseCase(repo)
+		refreshToken := repo.login(t)
+
+		assert.Nil(t, uc.Logout(&LogoutRequestDTO{RefreshToken: refreshToken}))
+
+		_, errApi := uc.RefreshToken(&RefreshTokenRequestDTO{RefreshToken: refreshToken})
+		assert.Equal(t, 401, errApi.Code())
+		assert.Equal(t, "Refresh token has been revoked", errApi.Error())
+	})
+}
@@ -1,6 +1,9 @@
 package auth
 
 import (
+	"errors"
+	"time"
+
 	"github.com/google/uuid"
 	"github.com/xcurvnubaim/njajal-gin-golang/internal/pkg/e"
 	"gorm.io/gorm"
@@ -12,6 +15,10 @@ type IAuthRepository interface {
 	GetUserByID(uuid.UUID) (*UserModel, e.ApiError)
 	GetAllUser() ([]UserModel, e.ApiError)
 	UpdateUser(*UserModel) e.ApiError
+	CreateRefreshToken(*RefreshTokenModel) e.ApiError
+	GetRefreshTokenByHash(string) (*RefreshTokenModel, e.ApiError)
+	RotateRefreshToken(current, next *RefreshTokenModel) (bool, e.ApiError)
+	RevokeRefreshTokenFamily(uuid.UUID) e.ApiError
 }
 
 type authRepository struct {
@@ -68,4 +75,64 @@ func (r *authRepository) UpdateUser(user *UserModel) e.ApiError {
 	}
 
 	return nil
-}
\ No newline at end of file
+}
+
+func (r *authRepository) CreateRefreshToken(token *RefreshTokenModel) e.ApiError {
+	result := r.db.Create(token)
+	if result.Error != nil {
+		return e.NewApiError(e.ERROR_CREATE_REFRESH_TOKEN_REPOSITORY_FAILED, result.Error.Error())
+	}
+
+	return nil
+}
+
+func (r *authRepository) GetRefreshTokenByHash(tokenHash string) (*RefreshTokenModel, e.ApiError) {
+	token := &RefreshTokenModel{}
+	result := r.db.Where("token_hash = ?", tokenHash).First(token)
+	if result.Error != nil {
+		return nil, e.NewApiError(e.ERROR_GET_REFRESH_TOKEN_REPOSITORY_FAILED, result.Error.Error())
+	}
+
+	return token, nil
+}
+
+var errRefreshTokenAlreadyUsed = errors.New("refresh token already used")
+
+// RotateRefreshToken marks current as used and stores next in one
+// transaction. It reports false, storing nothing, when current was used or
+// revoked in the meantime.
+func (r *authRepository) RotateRefreshToken(current, next *RefreshTokenModel) (bool, e.ApiError) {
+	now := time.Now()
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&RefreshTokenModel{}).
+			Where("id = ? AND used_at IS NULL AND revoked_at IS NULL", current.ID).
+			Update("used_at", now)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errRefreshTokenAlreadyUsed
+		}
+		return tx.Create(next).Error
+	})
+	if errors.Is(err, errRefreshTokenAlreadyUsed) {
+		return false, nil
+	}
+	if err != nil {
+		return false, e.NewApiError(e.ERROR_ROTATE_REFRESH_TOKEN_REPOSITORY_FAILED, err.Error())
+	}
+
+	current.UsedAt = &now
+	return true, nil
+}
+
+func (r *authRepository) RevokeRefreshTokenFamily(familyID uuid.UUID) e.ApiError {
+	result := r.db.Model(&RefreshTokenModel{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		return e.NewApiError(e.ERROR_REVOKE_REFRESH_TOKEN_REPOSITORY_FAILED, result.Error.Error())
+	}
+
+	return nil
+}
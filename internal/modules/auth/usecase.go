@@ -26,6 +26,8 @@ type IAuthUseCase interface {
 	GetAllUser() (*GetAllUsersResponseDTO, e.ApiError)
 	VerifyOTPcode(*UserModel, string) error
 	VerifyUser(*VerifyOTPRequestDTO) (*VerifyOTPResponseDTO, e.ApiError)
+	RefreshToken(*RefreshTokenRequestDTO) (*RefreshTokenResponseDTO, e.ApiError)
+	Logout(*LogoutRequestDTO) e.ApiError
 }
 
 type authUseCase struct {
@@ -100,10 +102,25 @@ func (uc *authUseCase) LoginUser(data *LoginUserRequestDTO) (*LoginUserResponseD
 		return nil, e.NewApiError(500, fmt.Sprintf("Internal Server Error (%d)", e.ERROR_GENERATE_TOKEN_FAILED))
 	}
 
+	// Every login starts a new session, identified by the family of its
+	// refresh tokens.
+	refreshToken, refreshTokenModel, errToken := newRefreshToken(user.ID, uuid.New(), time.Now())
+	if errToken != nil {
+		log.Println(errToken.Error())
+		return nil, e.NewApiError(500, fmt.Sprintf("Internal Server Error (%d)", e.ERROR_GENERATE_TOKEN_FAILED))
+	}
+
+	if err := uc.authRepository.CreateRefreshToken(refreshTokenModel); err != nil {
+		log.Println(err.Error())
+		return nil, e.NewApiError(500, fmt.Sprintf("Internal Server Error (%d)", err.Code()))
+	}
+
 	return &LoginUserResponseDTO{
-		Email: user.Email,
-		Roles: user.Role,
-		Token: token,
+		Email:        user.Email,
+		Roles:        user.Role,
+		Token:        token,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int64(AccessTokenTTL.Seconds()),
 	}, nil
 }
 
@@ -132,7 +149,7 @@ func (uc *authUseCase) GenerateToken(payloadToken PayloadToken) (string, error)
 	claims := jwt.MapClaims{}
 	claims["user_id"] = payloadToken.ID
 	claims["role"] = payloadToken.Role
-	claims["exp"] = time.Now().Add(time.Hour * 24).Unix()
+	claims["exp"] = time.Now().Add(AccessTokenTTL).Unix()
 
 	// Menggunakan secret key dari variabel lingkungan
 	secretKey := configs.Config.JWT_SECRET
@@ -0,0 +1,268 @@
+package shortlink
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/xcurvnubaim/njajal-gin-golang/internal/configs"
+	"github.com/xcurvnubaim/njajal-gin-golang/internal/pkg/e"
+	"gorm.io/gorm"
+)
+
+func (uc *useCase) CreateBioPage(userID uuid.UUID, data *CreateBioPageRequestDTO) (*GetBioPage, e.ApiError) {
+	title := strings.TrimSpace(data.Title)
+	if title == "" {
+		return nil, e.NewApiError(400, "Bio page title must not be blank")
+	}
+
+	theme := data.Theme
+	if theme == "" {
+		theme = BioThemeLight
+	}
+
+	bioPage := NewBioPage(userID, data.Slug, title, strings.TrimSpace(data.Description), data.AvatarURL, theme)
+	if err := uc.repository.CreateBioPage(bioPage); err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, e.NewApiError(400, "Slug already exists")
+		}
+		return nil, e.NewApiError(500, err.Error())
+	}
+
+	res := toGetBioPage(bioPage)
+	return &res, nil
+}
+
+func (uc *useCase) GetAllBioPages(userID uuid.UUID) (*GetAllBioPagesResponseDTO, e.ApiError) {
+	bioPages, err := uc.repository.GetAllBioPages(userID)
+	if err != nil {
+		return nil, e.NewApiError(500, err.Error())
+	}
+
+	data := make([]GetBioPage, 0, len(bioPages))
+	for _, bioPage := range bioPages {
+		data = append(data, toGetBioPage(bioPage))
+	}
+
+	return &GetAllBioPagesResponseDTO{BioPages: data}, nil
+}
+
+func (uc *useCase) GetBioPage(userID, id uuid.UUID) (*GetBioPage, e.ApiError) {
+	bioPage, errApi := uc.getOwnedBioPage(userID, id)
+	if errApi != nil {
+		return nil, errApi
+	}
+
+	res := toGetBioPage(bioPage)
+	return &res, nil
+}
+
+func (uc *useCase) UpdateBioPage(userID, id uuid.UUID, data *UpdateBioPageRequestDTO) (*GetBioPage, e.ApiError) {
+	bioPage, errApi := uc.getOwnedBioPage(userID, id)
+	if errApi != nil {
+		return nil, errApi
+	}
+
+	if data.Slug != nil {
+		bioPage.Slug = *data.Slug
+	}
+	if data.Title != nil {
+		bioPage.Title = strings.TrimSpace(*data.Title)
+		if bioPage.Title == "" {
+			return nil, e.NewApiError(400, "Bio page title must not be blank")
+		}
+	}
+	if data.Description != nil {
+		bioPage.Description = strings.TrimSpace(*data.Description)
+	}
+	if data.AvatarURL != nil {
+		bioPage.AvatarURL = *data.AvatarURL
+	}
+	if data.Theme != nil {
+		bioPage.Theme = *data.Theme
+	}
+	bioPage.UpdatedAt = time.Now()
+
+	if err := uc.repository.UpdateBioPage(bioPage); err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, e.NewApiError(400, "Slug already exists")
+		}
+		return nil, e.NewApiError(500, err.Error())
+	}
+
+	res := toGetBioPage(bioPage)
+	return &res, nil
+}
+
+func (uc *useCase) DeleteBioPage(userID, id uuid.UUID) e.ApiError {
+	bioPage, errApi := uc.getOwnedBioPage(userID, id)
+	if errApi != nil {
+		return errApi
+	}
+
+	if err := uc.repository.DeleteBioPage(bioPage); err != nil {
+		return e.NewApiError(500, err.Error())
+	}
+
+	return nil
+}
+
+// ReplaceBioPageItems sets the links listed on a bio page. Every link must
+// belong to the owner of the page.
+func (uc *useCase) ReplaceBioPageItems(userID, id uuid.UUID, data *ReplaceBioPageItemsRequestDTO) (*GetBioPage, e.ApiError) {
+	bioPage, errApi := uc.getOwnedBioPage(userID, id)
+	if errApi != nil {
+		return nil, errApi
+	}
+
+	existing := make(map[string]*BioPageItemModel, len(bioPage.Items))
+	for i := range bioPage.Items {
+		existing[bioPage.Items[i].ID.String()] = &bioPage.Items[i]
+	}
+
+	items := make([]*BioPageItemModel, 0, len(data.Items))
+	for i, itemData := range data.Items {
+		shortenerLinkID, err := uuid.Parse(itemData.ShortenerLinkID)
+		if err != nil {
+			return nil, e.NewApiError(400, fmt.Sprintf("items[%d]: invalid shortener link id", i))
+		}
+		shortenerLink, errApi := uc.getOwnedShortenerLink(userID, shortenerLinkID)
+		if errApi != nil {
+			return nil, e.NewApiError(errApi.Code(), fmt.Sprintf("items[%d]: %s", i, errApi.Error()))
+		}
+
+		item := NewBioPageItem(bioPage.ID, shortenerLink.ID, i, strings.TrimSpace(itemData.Label))
+		if itemData.ID != nil {
+			previous, ok := existing[*itemData.ID]
+			if !ok {
+				return nil, e.NewApiError(400, fmt.Sprintf("items[%d]: unknown item id", i))
+			}
+			delete(existing, *itemData.ID)
+			item.BaseModels = previous.BaseModels
+			item.UpdatedAt = time.Now()
+			item.ClickCount = previous.ClickCount
+		}
+		item.ShortenerLink = shortenerLink
+		items = append(items, item)
+	}
+
+	if err := uc.repository.ReplaceBioPageItems(bioPage.ID, items); err != nil {
+		return nil, e.NewApiError(500, err.Error())
+	}
+
+	bioPage.Items = make([]BioPageItemModel, 0, len(items))
+	for _, item := range items {
+		bioPage.Items = append(bioPage.Items, *item)
+	}
+
+	res := toGetBioPage(bioPage)
+	return &res, nil
+}
+
+// GetPublicBioPage returns a bio page as shown to visitors. Items whose link
+// was deleted or cannot currently be served are left out.
+func (uc *useCase) GetPublicBioPage(slug string) (*BioPage, e.ApiError) {
+	bioPage, err := uc.repository.GetBioPageBySlug(slug)
+	if err != nil {
+		return nil, e.NewApiError(404, "Bio page not found")
+	}
+
+	now := time.Now()
+	links := make([]BioPageLink, 0, len(bioPage.Items))
+	for i := range bioPage.Items {
+		item := &bioPage.Items[i]
+		if item.ShortenerLink == nil || item.ShortenerLink.CheckAvailability(now) != nil {
+			continue
+		}
+		links = append(links, BioPageLink{
+			URL:   bioPageItemPath(bioPage.Slug, item.ID),
+			Label: item.DisplayLabel(),
+		})
+	}
+
+	return &BioPage{
+		Slug:        bioPage.Slug,
+		Title:       bioPage.Title,
+		Description: bioPage.Description,
+		AvatarURL:   bioPage.AvatarURL,
+		Theme:       bioPage.Theme,
+		Links:       links,
+	}, nil
+}
+
+// TrackBioPageItemClick counts a click on a bio page item and returns the
+// short URL to send the visitor to. The short link records its own click
+// when it is followed.
+func (uc *useCase) TrackBioPageItemClick(slug string, itemID uuid.UUID) (string, e.ApiError) {
+	bioPage, err := uc.repository.GetBioPageBySlug(slug)
+	if err != nil {
+		return "", e.NewApiError(404, "Bio page not found")
+	}
+
+	var item *BioPageItemModel
+	for i := range bioPage.Items {
+		if bioPage.Items[i].ID == itemID {
+			item = &bioPage.Items[i]
+			break
+		}
+	}
+	if item == nil || item.ShortenerLink == nil {
+		return "", e.NewApiError(404, "Bio page item not found")
+	}
+
+	if _, err := uc.repository.IncrementBioPageItemClickCount(bioPage.ID, item.ID); err != nil {
+		return "", e.NewApiError(500, err.Error())
+	}
+
+	return shortURL(item.ShortenerLink), nil
+}
+
+func (uc *useCase) getOwnedBioPage(userID, id uuid.UUID) (*BioPageModel, e.ApiError) {
+	bioPage, err := uc.repository.GetBioPageByID(id)
+	if err != nil {
+		return nil, e.NewApiError(404, "Bio page not found")
+	}
+
+	if !bioPage.IsOwnedBy(userID) {
+		return nil, e.NewApiError(403, "You do not have permission to access this bio page")
+	}
+
+	return bioPage, nil
+}
+
+func toGetBioPage(bioPage *BioPageModel) GetBioPage {
+	items := make([]GetBioPageItem, 0, len(bioPage.Items))
+	for i := range bioPage.Items {
+		item := &bioPage.Items[i]
+		res := GetBioPageItem{
+			ID:              item.ID.String(),
+			ShortenerLinkID: item.ShortenerLinkID.String(),
+			Label:           item.Label,
+			ClickCount:      item.ClickCount,
+		}
+		if item.ShortenerLink != nil {
+			res.ShortURL = shortURL(item.ShortenerLink)
+		}
+		items = append(items, res)
+	}
+
+	return GetBioPage{
+		ID:          bioPage.ID.String(),
+		Slug:        bioPage.Slug,
+		URL:         strings.TrimSuffix(configs.Config.BASE_URL, "/") + "/@" + bioPage.Slug,
+		Title:       bioPage.Title,
+		Description: bioPage.Description,
+		AvatarURL:   bioPage.AvatarURL,
+		Theme:       bioPage.Theme,
+		Items:       items,
+		CreatedAt:   bioPage.CreatedAt.Format("2006-01-02 15:04:05"),
+		UpdatedAt:   bioPage.UpdatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
+
+// bioPageItemPath is the click tracking route of a bio page item.
+func bioPageItemPath(slug string, itemID uuid.UUID) string {
+	return "/@" + slug + "/" + itemID.String()
+}
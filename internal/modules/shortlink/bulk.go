@@ -0,0 +1,186 @@
+package shortlink
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrBulkTooManyRows = fmt.Errorf("A bulk request can contain at most %d rows", MaxBulkRows)
+
+// bulkCSVColumns maps accepted CSV header names to row fields.
+var bulkCSVColumns = map[string]string{
+	"original_url":  "original_url",
+	"url":           "original_url",
+	"shortener_url": "shortener_url",
+	"alias":         "shortener_url",
+	"expires_at":    "expires_at",
+	"expiry":        "expires_at",
+}
+
+var bulkTimeLayouts = []string{time.RFC3339, "2006-01-02 15:04:05", "2006-01-02"}
+
+// parseBulkCSV reads an uploaded spreadsheet export. The first line must be a
+// header naming the columns; rows whose expiry cannot be parsed are returned
+// in rowErrors keyed by their index instead of failing the whole upload.
+func parseBulkCSV(r io.Reader) (rows []BulkCreateShortenerLinkRowDTO, rowErrors map[int]string, err error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil, errors.New("CSV file is empty")
+		}
+		return nil, nil, err
+	}
+
+	columns := make(map[string]int)
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if field, ok := bulkCSVColumns[name]; ok {
+			columns[field] = i
+		}
+	}
+	if _, ok := columns["original_url"]; !ok {
+		return nil, nil, errors.New("CSV header must contain an original_url column")
+	}
+
+	rowErrors = make(map[int]string)
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(rows) == MaxBulkRows {
+			return nil, nil, ErrBulkTooManyRows
+		}
+
+		field := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		row := BulkCreateShortenerLinkRowDTO{
+			OriginalURL:  field("original_url"),
+			ShortenerURL: field("shortener_url"),
+		}
+		if expiry := field("expires_at"); expiry != "" {
+			expiresAt, ok := parseBulkTime(expiry)
+			if !ok {
+				rowErrors[len(rows)] = "expires_at must be a date (YYYY-MM-DD) or RFC 3339 timestamp"
+			}
+			row.ExpiresAt = expiresAt
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, rowErrors, nil
+}
+
+func parseBulkTime(value string) (*time.Time, bool) {
+	for _, layout := range bulkTimeLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return &t, true
+		}
+	}
+	return nil, false
+}
+
+// linkExporter writes links to the response as they are read from the
+// database so large exports never have to be held in memory.
+type linkExporter interface {
+	Begin() error
+	Write(shortenerLink GetShortenerLink) error
+	End() error
+}
+
+func newLinkExporter(format string, w io.Writer) linkExporter {
+	if format == ExportFormatJSON {
+		return &jsonLinkExporter{w: w}
+	}
+	return &csvLinkExporter{w: csv.NewWriter(w)}
+}
+
+var exportCSVHeader = []string{"id", "original_url", "shortener_url", "active_from", "expires_at", "max_clicks", "click_count", "is_expired", "is_protected", "created_at"}
+
+type csvLinkExporter struct {
+	w *csv.Writer
+}
+
+func (e *csvLinkExporter) Begin() error {
+	return e.w.Write(exportCSVHeader)
+}
+
+func (e *csvLinkExporter) Write(shortenerLink GetShortenerLink) error {
+	maxClicks := ""
+	if shortenerLink.MaxClicks != nil {
+		maxClicks = strconv.Itoa(*shortenerLink.MaxClicks)
+	}
+	return e.w.Write([]string{
+		shortenerLink.ID,
+		shortenerLink.OriginalURL,
+		shortenerLink.ShortenerURL,
+		stringOrEmpty(shortenerLink.ActiveFrom),
+		stringOrEmpty(shortenerLink.ExpiresAt),
+		maxClicks,
+		strconv.FormatInt(shortenerLink.ClickCount, 10),
+		strconv.FormatBool(shortenerLink.IsExpired),
+		strconv.FormatBool(shortenerLink.IsProtected),
+		shortenerLink.CreatedAt,
+	})
+}
+
+func (e *csvLinkExporter) End() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+type jsonLinkExporter struct {
+	w       io.Writer
+	written int
+}
+
+func (e *jsonLinkExporter) Begin() error {
+	_, err := io.WriteString(e.w, "[")
+	return err
+}
+
+func (e *jsonLinkExporter) Write(shortenerLink GetShortenerLink) error {
+	if e.written > 0 {
+		if _, err := io.WriteString(e.w, ","); err != nil {
+			return err
+		}
+	}
+	e.written++
+
+	data, err := json.Marshal(shortenerLink)
+	if err != nil {
+		return err
+	}
+	_, err = e.w.Write(data)
+	return err
+}
+
+func (e *jsonLinkExporter) End() error {
+	_, err := io.WriteString(e.w, "]")
+	return err
+}
+
+func stringOrEmpty(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,76 @@
+package shortlink
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseBulkCSV(t *testing.T) {
+	input := "\ufeffURL,Alias,Expiry\n" +
+		"https://example.com/a,promo,2030-01-02\n" +
+		"https://example.com/b,,\n" +
+		"https://example.com/c,,next week\n"
+
+	rows, rowErrors, err := parseBulkCSV(strings.NewReader(input))
+	assert.NoError(t, err)
+	assert.Len(t, rows, 3)
+
+	assert.Equal(t, "https://example.com/a", rows[0].OriginalURL)
+	assert.Equal(t, "promo", rows[0].ShortenerURL)
+	assert.Equal(t, "2030-01-02", rows[0].ExpiresAt.Format("2006-01-02"))
+
+	assert.Equal(t, "", rows[1].ShortenerURL)
+	assert.Nil(t, rows[1].ExpiresAt)
+
+	assert.Len(t, rowErrors, 1)
+	assert.Contains(t, rowErrors, 2)
+}
+
+func TestParseBulkCSV_Errors(t *testing.T) {
+	_, _, err := parseBulkCSV(strings.NewReader(""))
+	assert.Error(t, err)
+
+	_, _, err = parseBulkCSV(strings.NewReader("alias\npromo\n"))
+	assert.Error(t, err)
+
+	var b strings.Builder
+	b.WriteString("original_url\n")
+	for i := 0; i <= MaxBulkRows; i++ {
+		b.WriteString("https://example.com\n")
+	}
+	_, _, err = parseBulkCSV(strings.NewReader(b.String()))
+	assert.Equal(t, ErrBulkTooManyRows, err)
+}
+
+func TestLinkExporter(t *testing.T) {
+	maxClicks := 5
+	links := []GetShortenerLink{
+		{ID: "1", OriginalURL: "https://example.com/a", ShortenerURL: "a", MaxClicks: &maxClicks, CreatedAt: "2030-01-01 00:00:00"},
+		{ID: "2", OriginalURL: "https://example.com/b,c", ShortenerURL: "b", CreatedAt: "2030-01-02 00:00:00"},
+	}
+
+	export := func(format string) string {
+		var buf bytes.Buffer
+		exporter := newLinkExporter(format, &buf)
+		assert.NoError(t, exporter.Begin())
+		for _, link := range links {
+			assert.NoError(t, exporter.Write(link))
+		}
+		assert.NoError(t, exporter.End())
+		return buf.String()
+	}
+
+	assert.Equal(t,
+		"id,original_url,shortener_url,active_from,expires_at,max_clicks,click_count,is_expired,is_protected,created_at\n"+
+			"1,https://example.com/a,a,,,5,0,false,false,2030-01-01 00:00:00\n"+
+			"2,\"https://example.com/b,c\",b,,,,0,false,false,2030-01-02 00:00:00\n",
+		export(ExportFormatCSV))
+
+	json := export(ExportFormatJSON)
+	assert.True(t, strings.HasPrefix(json, `[{"id":"1"`))
+	assert.True(t, strings.HasSuffix(json, `}]`))
+	assert.Contains(t, json, `},{"id":"2"`)
+}
@@ -0,0 +1,255 @@
+package shortlink
+
+import (
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/xcurvnubaim/njajal-gin-golang/internal/pkg/cache"
+	"gorm.io/gorm"
+)
+
+// LinkCache stores redirect lookups by linkCacheKey. A hit with a nil link is
+// a negative entry: the code is known not to exist.
+type LinkCache interface {
+	Get(shortenerURL string) (link *ShortenerLinkModel, hit bool)
+	Set(shortenerURL string, link *ShortenerLinkModel)
+	SetNotFound(shortenerURL string)
+	Delete(shortenerURL string)
+}
+
+type CacheStats struct {
+	Hits   uint64
+	Misses uint64
+}
+
+type lruLinkCache struct {
+	entries     *cache.LRU[string, *ShortenerLinkModel]
+	ttl         time.Duration
+	negativeTTL time.Duration
+}
+
+func NewLRULinkCache(capacity int, ttl, negativeTTL time.Duration) *lruLinkCache {
+	return &lruLinkCache{
+		entries:     cache.NewLRU[string, *ShortenerLinkModel](capacity),
+		ttl:         ttl,
+		negativeTTL: negativeTTL,
+	}
+}
+
+func (c *lruLinkCache) Get(shortenerURL string) (*ShortenerLinkModel, bool) {
+	link, ok := c.entries.Get(shortenerURL)
+	if !ok || link == nil {
+		return nil, ok
+	}
+	// Hand out copies so callers cannot mutate the cached entry.
+	copied := *link
+	return &copied, true
+}
+
+func (c *lruLinkCache) Set(shortenerURL string, link *ShortenerLinkModel) {
+	copied := *link
+	c.entries.Set(shortenerURL, &copied, c.ttl)
+}
+
+func (c *lruLinkCache) SetNotFound(shortenerURL string) {
+	c.entries.Set(shortenerURL, nil, c.negativeTTL)
+}
+
+func (c *lruLinkCache) Delete(shortenerURL string) {
+	c.entries.Delete(shortenerURL)
+}
+
+// cachedRepository serves GetShortenerLinkByShortenerURL from a LinkCache and
+// invalidates entries whenever a link is written. Verified domains are kept
+// in a small in-process cache since every redirect on a branded host looks
+// one up. Every other method is passed through to the wrapped repository.
+type cachedRepository struct {
+	IRepository
+	cache   LinkCache
+	domains *cache.LRU[string, *DomainModel]
+	hits    atomic.Uint64
+	misses  atomic.Uint64
+}
+
+func NewCachedRepository(repository IRepository, linkCache LinkCache) *cachedRepository {
+	return &cachedRepository{
+		IRepository: repository,
+		cache:       linkCache,
+		domains:     cache.NewLRU[string, *DomainModel](DefaultDomainCacheSize),
+	}
+}
+
+// linkCacheKey keys links served from BASE_URL by their code alone, and links
+// on a branded domain by domain and code. Codes cannot contain a slash.
+func linkCacheKey(domainID *uuid.UUID, shortenerURL string) string {
+	if domainID == nil {
+		return shortenerURL
+	}
+	return domainID.String() + "/" + shortenerURL
+}
+
+func (r *cachedRepository) GetShortenerLinkByShortenerURL(domainID *uuid.UUID, shortenerURL string) (*ShortenerLinkModel, error) {
+	key := linkCacheKey(domainID, shortenerURL)
+	if link, hit := r.cache.Get(key); hit {
+		r.hits.Add(1)
+		if link == nil {
+			return nil, gorm.ErrRecordNotFound
+		}
+		return link, nil
+	}
+	r.misses.Add(1)
+
+	link, err := r.IRepository.GetShortenerLinkByShortenerURL(domainID, shortenerURL)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			r.cache.SetNotFound(key)
+		}
+		return nil, err
+	}
+
+	r.cache.Set(key, link)
+	return link, nil
+}
+
+func (r *cachedRepository) CreateShortenerLink(data *ShortenerLinkModel) error {
+	if err := r.IRepository.CreateShortenerLink(data); err != nil {
+		return err
+	}
+	r.cache.Delete(linkCacheKey(data.DomainID, data.ShortenerURL))
+	return nil
+}
+
+func (r *cachedRepository) UpdateShortenerLink(data *ShortenerLinkModel, resetColumns ...string) error {
+	r.invalidateByID(data.ID)
+	if err := r.IRepository.UpdateShortenerLink(data, resetColumns...); err != nil {
+		return err
+	}
+	r.cache.Delete(linkCacheKey(data.DomainID, data.ShortenerURL))
+	return nil
+}
+
+func (r *cachedRepository) DeleteShortenerLink(data *ShortenerLinkModel) error {
+	r.invalidateByID(data.ID)
+	if err := r.IRepository.DeleteShortenerLink(data); err != nil {
+		return err
+	}
+	r.cache.Delete(linkCacheKey(data.DomainID, data.ShortenerURL))
+	return nil
+}
+
+// UndeleteShortenerLink drops the negative entry cached while the link was
+// deleted.
+func (r *cachedRepository) UndeleteShortenerLink(data *ShortenerLinkModel) error {
+	if err := r.IRepository.UndeleteShortenerLink(data); err != nil {
+		return err
+	}
+	r.cache.Delete(linkCacheKey(data.DomainID, data.ShortenerURL))
+	return nil
+}
+
+func (r *cachedRepository) ReplaceShortenerLinkRules(shortenerLinkID uuid.UUID, rules []*ShortenerLinkRuleModel) error {
+	if err := r.IRepository.ReplaceShortenerLinkRules(shortenerLinkID, rules); err != nil {
+		return err
+	}
+	r.invalidateByID(shortenerLinkID)
+	return nil
+}
+
+func (r *cachedRepository) ReplaceShortenerLinkVariants(shortenerLinkID uuid.UUID, variants []*ShortenerLinkVariantModel) error {
+	if err := r.IRepository.ReplaceShortenerLinkVariants(shortenerLinkID, variants); err != nil {
+		return err
+	}
+	r.invalidateByID(shortenerLinkID)
+	return nil
+}
+
+// UpdateCampaign drops the cached links of the campaign because they embed
+// its UTM parameters.
+func (r *cachedRepository) UpdateCampaign(data *CampaignModel) error {
+	if err := r.IRepository.UpdateCampaign(data); err != nil {
+		return err
+	}
+	r.invalidateByCampaign(data.ID)
+	return nil
+}
+
+func (r *cachedRepository) DeleteCampaign(data *CampaignModel) error {
+	shortenerLinks, _ := r.IRepository.GetShortenerLinksByCampaign(data.ID)
+	if err := r.IRepository.DeleteCampaign(data); err != nil {
+		return err
+	}
+	for _, shortenerLink := range shortenerLinks {
+		r.cache.Delete(linkCacheKey(shortenerLink.DomainID, shortenerLink.ShortenerURL))
+	}
+	return nil
+}
+
+// GetVerifiedDomainByHostname caches misses too, so requests for unknown
+// hosts do not reach the database on every redirect.
+func (r *cachedRepository) GetVerifiedDomainByHostname(hostname string) (*DomainModel, error) {
+	if domain, hit := r.domains.Get(hostname); hit {
+		if domain == nil {
+			return nil, gorm.ErrRecordNotFound
+		}
+		return domain, nil
+	}
+
+	domain, err := r.IRepository.GetVerifiedDomainByHostname(hostname)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			r.domains.Set(hostname, nil, DefaultDomainCacheTTL)
+		}
+		return nil, err
+	}
+
+	r.domains.Set(hostname, domain, DefaultDomainCacheTTL)
+	return domain, nil
+}
+
+func (r *cachedRepository) VerifyDomain(data *DomainModel) error {
+	if err := r.IRepository.VerifyDomain(data); err != nil {
+		return err
+	}
+	r.domains.Delete(data.Hostname)
+	return nil
+}
+
+func (r *cachedRepository) DeleteDomain(data *DomainModel) error {
+	if err := r.IRepository.DeleteDomain(data); err != nil {
+		return err
+	}
+	r.domains.Delete(data.Hostname)
+	return nil
+}
+
+// Transaction keeps invalidating the cache for writes made inside fn.
+func (r *cachedRepository) Transaction(fn func(tx IRepository) error) error {
+	return r.IRepository.Transaction(func(tx IRepository) error {
+		return fn(&cachedRepository{IRepository: tx, cache: r.cache, domains: r.domains})
+	})
+}
+
+func (r *cachedRepository) CacheStats() CacheStats {
+	return CacheStats{
+		Hits:   r.hits.Load(),
+		Misses: r.misses.Load(),
+	}
+}
+
+// invalidateByID drops the entry for the code currently stored in the
+// database, which differs from data.ShortenerURL when the code is renamed.
+func (r *cachedRepository) invalidateByID(id uuid.UUID) {
+	if current, err := r.IRepository.GetShortenerLinkByID(id); err == nil {
+		r.cache.Delete(linkCacheKey(current.DomainID, current.ShortenerURL))
+	}
+}
+
+func (r *cachedRepository) invalidateByCampaign(campaignID uuid.UUID) {
+	if shortenerLinks, err := r.IRepository.GetShortenerLinksByCampaign(campaignID); err == nil {
+		for _, shortenerLink := range shortenerLinks {
+			r.cache.Delete(linkCacheKey(shortenerLink.DomainID, shortenerLink.ShortenerURL))
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package shortlink
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	redisLinkKeyPrefix = "shortlink:link:"
+	redisNotFoundValue = "-"
+	redisTimeout       = 500 * time.Millisecond
+)
+
+// redisLinkCache shares redirect lookups between API replicas. Redis errors
+// are logged and treated as misses so an outage falls back to the database.
+type redisLinkCache struct {
+	client      *redis.Client
+	ttl         time.Duration
+	negativeTTL time.Duration
+}
+
+func NewRedisLinkCache(client *redis.Client, ttl, negativeTTL time.Duration) *redisLinkCache {
+	return &redisLinkCache{
+		client:      client,
+		ttl:         ttl,
+		negativeTTL: negativeTTL,
+	}
+}
+
+func (c *redisLinkCache) Get(shortenerURL string) (*ShortenerLinkModel, bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+
+	value, err := c.client.Get(ctx, redisLinkKeyPrefix+shortenerURL).Result()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			log.Println(err)
+		}
+		return nil, false
+	}
+
+	if value == redisNotFoundValue {
+		return nil, true
+	}
+
+	var link ShortenerLinkModel
+	if err := json.Unmarshal([]byte(value), &link); err != nil {
+		log.Println(err)
+		return nil, false
+	}
+	return &link, true
+}
+
+func (c *redisLinkCache) Set(shortenerURL string, link *ShortenerLinkModel) {
+	value, err := json.Marshal(link)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	c.set(shortenerURL, value, c.ttl)
+}
+
+func (c *redisLinkCache) SetNotFound(shortenerURL string) {
+	c.set(shortenerURL, redisNotFoundValue, c.negativeTTL)
+}
+
+func (c *redisLinkCache) Delete(shortenerURL string) {
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+
+	if err := c.client.Del(ctx, redisLinkKeyPrefix+shortenerURL).Err(); err != nil {
+		log.Println(err)
+	}
+}
+
+func (c *redisLinkCache) set(shortenerURL string, value interface{}, ttl time.Duration) {
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+
+	if err := c.client.Set(ctx, redisLinkKeyPrefix+shortenerURL, value, ttl).Err(); err != nil {
+		log.Println(err)
+	}
+}
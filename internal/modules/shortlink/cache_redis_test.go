@@ -0,0 +1,98 @@
+package shortlink
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestRedis(t *testing.T) (*miniredis.Miniredis, *redis.Client) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+	return server, client
+}
+
+func TestRedisLinkCache(t *testing.T) {
+	server, client := newTestRedis(t)
+	c := NewRedisLinkCache(client, time.Minute, time.Second)
+
+	link := NewShortenerLink(uuid.New(), "https://example.com", "abc")
+	c.Set("abc", link)
+
+	cached, hit := c.Get("abc")
+	assert.True(t, hit)
+	assert.Equal(t, link.ID, cached.ID)
+	assert.Equal(t, link.OriginalURL, cached.OriginalURL)
+
+	c.SetNotFound("missing")
+	cached, hit = c.Get("missing")
+	assert.True(t, hit)
+	assert.Nil(t, cached)
+
+	server.FastForward(2 * time.Second)
+	_, hit = c.Get("missing")
+	assert.False(t, hit)
+
+	c.Delete("abc")
+	_, hit = c.Get("abc")
+	assert.False(t, hit)
+}
+
+func TestRedisLinkCache_TreatsOutageAsMiss(t *testing.T) {
+	server, client := newTestRedis(t)
+	c := NewRedisLinkCache(client, time.Minute, time.Minute)
+	server.Close()
+
+	_, hit := c.Get("abc")
+	assert.False(t, hit)
+}
+
+func TestRedisClickCounter(t *testing.T) {
+	_, client := newTestRedis(t)
+	counter := NewRedisClickCounter(client)
+
+	first, second := uuid.New(), uuid.New()
+	assert.NoError(t, counter.IncrBy(first, 1))
+	assert.NoError(t, counter.IncrBy(first, 1))
+	assert.NoError(t, counter.IncrBy(second, 5))
+
+	counts, err := counter.Drain()
+	assert.NoError(t, err)
+	assert.Equal(t, map[uuid.UUID]int64{first: 2, second: 5}, counts)
+
+	counts, err = counter.Drain()
+	assert.NoError(t, err)
+	assert.Empty(t, counts)
+}
+
+type clickCountRepositoryStub struct {
+	IRepository
+	counts map[uuid.UUID]int64
+}
+
+func (s *clickCountRepositoryStub) AddShortenerLinkClickCounts(counts map[uuid.UUID]int64) error {
+	for id, n := range counts {
+		s.counts[id] += n
+	}
+	return nil
+}
+
+func TestClickCountFlusher_FlushesRedisCounts(t *testing.T) {
+	_, client := newTestRedis(t)
+	counter := NewRedisClickCounter(client)
+	repo := &clickCountRepositoryStub{counts: make(map[uuid.UUID]int64)}
+
+	id := uuid.New()
+	assert.NoError(t, counter.IncrBy(id, 3))
+
+	flusher := NewClickCountFlusher(counter, repo, time.Hour)
+	flusher.Start()
+	flusher.Stop()
+
+	assert.Equal(t, int64(3), repo.counts[id])
+}
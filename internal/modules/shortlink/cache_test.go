@@ -0,0 +1,115 @@
+package shortlink
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+type lookupRepositoryStub struct {
+	IRepository
+	links   map[string]*ShortenerLinkModel
+	lookups int
+}
+
+func (s *lookupRepositoryStub) GetShortenerLinkByShortenerURL(domainID *uuid.UUID, shortenerURL string) (*ShortenerLinkModel, error) {
+	s.lookups++
+	link, ok := s.links[linkCacheKey(domainID, shortenerURL)]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	copied := *link
+	return &copied, nil
+}
+
+func (s *lookupRepositoryStub) GetShortenerLinkByID(id uuid.UUID) (*ShortenerLinkModel, error) {
+	for _, link := range s.links {
+		if link.ID == id {
+			copied := *link
+			return &copied, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (s *lookupRepositoryStub) UpdateShortenerLink(data *ShortenerLinkModel, resetColumns ...string) error {
+	for code, link := range s.links {
+		if link.ID == data.ID {
+			delete(s.links, code)
+		}
+	}
+	s.links[linkCacheKey(data.DomainID, data.ShortenerURL)] = data
+	return nil
+}
+
+func newCachedRepositoryForTest() (*cachedRepository, *lookupRepositoryStub, *ShortenerLinkModel) {
+	link := NewShortenerLink(uuid.New(), "https://example.com", "abc")
+	stub := &lookupRepositoryStub{links: map[string]*ShortenerLinkModel{"abc": link}}
+	return NewCachedRepository(stub, NewLRULinkCache(10, time.Minute, time.Minute)), stub, link
+}
+
+func TestCachedRepository_ServesHitsFromCache(t *testing.T) {
+	repo, stub, _ := newCachedRepositoryForTest()
+
+	for i := 0; i < 3; i++ {
+		link, err := repo.GetShortenerLinkByShortenerURL(nil, "abc")
+		assert.NoError(t, err)
+		assert.Equal(t, "https://example.com", link.OriginalURL)
+	}
+
+	assert.Equal(t, 1, stub.lookups)
+	assert.Equal(t, CacheStats{Hits: 2, Misses: 1}, repo.CacheStats())
+}
+
+func TestCachedRepository_NegativeCaching(t *testing.T) {
+	repo, stub, _ := newCachedRepositoryForTest()
+
+	for i := 0; i < 2; i++ {
+		_, err := repo.GetShortenerLinkByShortenerURL(nil, "missing")
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	}
+
+	assert.Equal(t, 1, stub.lookups)
+}
+
+func TestCachedRepository_InvalidatesRenamedCode(t *testing.T) {
+	repo, _, link := newCachedRepositoryForTest()
+
+	_, err := repo.GetShortenerLinkByShortenerURL(nil, "abc")
+	assert.NoError(t, err)
+
+	updated := *link
+	updated.ShortenerURL = "xyz"
+	assert.NoError(t, repo.UpdateShortenerLink(&updated))
+
+	_, err = repo.GetShortenerLinkByShortenerURL(nil, "abc")
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+	renamed, err := repo.GetShortenerLinkByShortenerURL(nil, "xyz")
+	assert.NoError(t, err)
+	assert.Equal(t, link.ID, renamed.ID)
+}
+
+func TestCachedRepository_KeysByDomain(t *testing.T) {
+	repo, stub, _ := newCachedRepositoryForTest()
+
+	domainID := uuid.New()
+	branded := NewShortenerLink(uuid.New(), "https://example.org", "abc")
+	branded.DomainID = &domainID
+	stub.links[linkCacheKey(&domainID, "abc")] = branded
+
+	link, err := repo.GetShortenerLinkByShortenerURL(nil, "abc")
+	assert.NoError(t, err)
+	assert.Equal(t, "https://example.com", link.OriginalURL)
+
+	link, err = repo.GetShortenerLinkByShortenerURL(&domainID, "abc")
+	assert.NoError(t, err)
+	assert.Equal(t, "https://example.org", link.OriginalURL)
+
+	_, err = repo.GetShortenerLinkByShortenerURL(&domainID, "xyz")
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	assert.Equal(t, 3, stub.lookups)
+}
@@ -1 +1,102 @@
-package shortlink
\ No newline at end of file
+package shortlink
+
+import "time"
+
+const (
+	StatsIntervalHour = "hour"
+	StatsIntervalDay  = "day"
+	StatsIntervalWeek = "week"
+
+	topReferrersLimit = 10
+
+	maxGenerateAttempts = 5
+
+	MaxBulkRows       = 1000
+	MaxBulkUploadSize = 2 << 20
+
+	ExportFormatCSV  = "csv"
+	ExportFormatJSON = "json"
+
+	DefaultRedirectStatus   = 302
+	PermanentRedirectMaxAge = time.Hour
+
+	RuleTypePlatform = "platform"
+	RuleTypeLanguage = "language"
+	RuleTypeCountry  = "country"
+
+	PlatformIOS     = "ios"
+	PlatformAndroid = "android"
+	PlatformMobile  = "mobile"
+	PlatformDesktop = "desktop"
+
+	VariantCookiePrefix = "shortlink_variant_"
+	VariantCookieTTL    = 30 * 24 * time.Hour
+
+	DefaultHealthCheckInterval    = 5 * time.Minute
+	DefaultHealthRecheckAfter     = 6 * time.Hour
+	DefaultHealthCheckTimeout     = 10 * time.Second
+	DefaultHealthCheckConcurrency = 8
+	DefaultHealthCheckBatchSize   = 200
+	HealthCheckUserAgent          = "njajal-link-checker/1.0"
+	maxHealthErrorLength          = 255
+	maxHealthCheckRedirects       = 10
+
+	// PublicStatsDays is the length of the daily chart on public stats
+	// pages.
+	PublicStatsDays             = 30
+	DefaultPublicStatsCacheSize = 1000
+	PublicStatsCacheTTL         = 5 * time.Minute
+
+	BioThemeLight = "light"
+	BioThemeDark  = "dark"
+
+	// FolderNone is the folder= filter value for links outside any folder.
+	FolderNone = "none"
+
+	RevisionActionCreate   = "create"
+	RevisionActionUpdate   = "update"
+	RevisionActionDelete   = "delete"
+	RevisionActionRestore  = "restore"
+	RevisionActionUndelete = "undelete"
+
+	EventLinkCreated = "link.created"
+	EventLinkUpdated = "link.updated"
+	EventLinkDeleted = "link.deleted"
+	EventLinkExpired = "link.expired"
+	EventLinkClicked = "link.clicked"
+
+	DomainVerificationRecordPrefix = "_shortlink-verify"
+	DomainVerificationValuePrefix  = "shortlink-verify="
+	DefaultDomainVerifyTimeout     = 5 * time.Second
+	DefaultDomainCacheSize         = 1000
+	DefaultDomainCacheTTL          = time.Minute
+
+	// PreviewSuffix appended to a short code opens its preview page.
+	PreviewSuffix = "+"
+
+	// DeepLinkFallbackDelay is how long the interstitial waits for the app
+	// to open before sending the visitor to the fallback URL.
+	DeepLinkFallbackDelay = 1500 * time.Millisecond
+	// AppAssociationMaxAge is how long the .well-known app association files
+	// may be cached.
+	AppAssociationMaxAge = time.Hour
+
+	UnlockCookiePrefix = "shortlink_unlock_"
+	UnlockTokenTTL     = 15 * time.Minute
+
+	DefaultLinkCacheSize        = 10000
+	DefaultLinkCacheTTL         = 5 * time.Minute
+	DefaultLinkCacheNegativeTTL = 30 * time.Second
+
+	CacheDriverMemory = "memory"
+	CacheDriverRedis  = "redis"
+)
+
+// LinkEvents lists every event a link emits, for subscribers to pick from.
+var LinkEvents = []string{EventLinkCreated, EventLinkUpdated, EventLinkDeleted, EventLinkExpired, EventLinkClicked}
+
+var defaultStatsRange = map[string]time.Duration{
+	StatsIntervalHour: 48 * time.Hour,
+	StatsIntervalDay:  30 * 24 * time.Hour,
+	StatsIntervalWeek: 12 * 7 * 24 * time.Hour,
+}
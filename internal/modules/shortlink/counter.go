@@ -0,0 +1,171 @@
+package shortlink
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// ClickCounter accumulates click counts outside the database. Drain returns
+// and resets every pending count so it can be added to shortener_links.
+type ClickCounter interface {
+	IncrBy(shortenerLinkID uuid.UUID, n int64) error
+	Drain() (map[uuid.UUID]int64, error)
+}
+
+type memoryClickCounter struct {
+	mu     sync.Mutex
+	counts map[uuid.UUID]int64
+}
+
+func NewMemoryClickCounter() *memoryClickCounter {
+	return &memoryClickCounter{counts: make(map[uuid.UUID]int64)}
+}
+
+func (c *memoryClickCounter) IncrBy(shortenerLinkID uuid.UUID, n int64) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.counts[shortenerLinkID] += n
+	return nil
+}
+
+func (c *memoryClickCounter) Drain() (map[uuid.UUID]int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	counts := c.counts
+	c.counts = make(map[uuid.UUID]int64)
+	return counts, nil
+}
+
+const (
+	redisClickKeyPrefix = "shortlink:clicks:"
+	redisDirtyClicksKey = "shortlink:clicks:dirty"
+)
+
+// redisClickCounter keeps one INCR counter per link plus a set of links with
+// pending counts, so every replica contributes to the same totals.
+type redisClickCounter struct {
+	client *redis.Client
+}
+
+func NewRedisClickCounter(client *redis.Client) *redisClickCounter {
+	return &redisClickCounter{client: client}
+}
+
+func (c *redisClickCounter) IncrBy(shortenerLinkID uuid.UUID, n int64) error {
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+
+	id := shortenerLinkID.String()
+	_, err := c.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.IncrBy(ctx, redisClickKeyPrefix+id, n)
+		pipe.SAdd(ctx, redisDirtyClicksKey, id)
+		return nil
+	})
+	return err
+}
+
+func (c *redisClickCounter) Drain() (map[uuid.UUID]int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	counts := make(map[uuid.UUID]int64)
+	for {
+		id, err := c.client.SPop(ctx, redisDirtyClicksKey).Result()
+		if err == redis.Nil {
+			return counts, nil
+		}
+		if err != nil {
+			return counts, err
+		}
+
+		// GETDEL hands the count to exactly one replica; later INCRs start a
+		// new counter and mark the link dirty again.
+		value, err := c.client.GetDel(ctx, redisClickKeyPrefix+id).Result()
+		if err == redis.Nil {
+			continue
+		}
+		if err != nil {
+			return counts, err
+		}
+
+		parsedID, errUuid := uuid.Parse(id)
+		count, errCount := strconv.ParseInt(value, 10, 64)
+		if errUuid != nil || errCount != nil {
+			log.Println("Skipping invalid click counter", id, value)
+			continue
+		}
+		counts[parsedID] += count
+	}
+}
+
+// clickCountFlusher periodically moves pending counts from a ClickCounter into
+// shortener_links.click_count.
+type clickCountFlusher struct {
+	counter    ClickCounter
+	repository IRepository
+	interval   time.Duration
+	done       chan struct{}
+	wg         sync.WaitGroup
+}
+
+func NewClickCountFlusher(counter ClickCounter, repository IRepository, interval time.Duration) *clickCountFlusher {
+	return &clickCountFlusher{
+		counter:    counter,
+		repository: repository,
+		interval:   interval,
+		done:       make(chan struct{}),
+	}
+}
+
+func (f *clickCountFlusher) Start() {
+	f.wg.Add(1)
+	go f.run()
+}
+
+func (f *clickCountFlusher) Stop() {
+	close(f.done)
+	f.wg.Wait()
+}
+
+func (f *clickCountFlusher) run() {
+	defer f.wg.Done()
+
+	ticker := time.NewTicker(f.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			f.flush()
+		case <-f.done:
+			f.flush()
+			return
+		}
+	}
+}
+
+func (f *clickCountFlusher) flush() {
+	counts, err := f.counter.Drain()
+	if err != nil {
+		log.Println("Failed to drain click counters:", err)
+	}
+	if len(counts) == 0 {
+		return
+	}
+
+	if err := f.repository.AddShortenerLinkClickCounts(counts); err != nil {
+		log.Println("Failed to flush click counts:", err)
+		// Put the counts back so the next flush retries them.
+		for id, n := range counts {
+			if err := f.counter.IncrBy(id, n); err != nil {
+				log.Println("Failed to restore click count for", id, err)
+			}
+		}
+	}
+}
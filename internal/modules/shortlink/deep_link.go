@@ -0,0 +1,285 @@
+package shortlink
+
+import (
+	"errors"
+	"log"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/xcurvnubaim/njajal-gin-golang/internal/pkg/e"
+	"gorm.io/gorm"
+)
+
+// reservedAppSchemes cannot be registered as app schemes. Web links are
+// checked as universal links, intent URLs can launch any installed app, and
+// the rest run code or read local data in the browser.
+var reservedAppSchemes = map[string]bool{
+	"http":       true,
+	"https":      true,
+	"intent":     true,
+	"javascript": true,
+	"vbscript":   true,
+	"data":       true,
+	"file":       true,
+	"blob":       true,
+	"about":      true,
+}
+
+// appSchemePattern is the URI scheme syntax of RFC 3986, in lower case.
+var appSchemePattern = regexp.MustCompile(`^[a-z][a-z0-9+.-]*$`)
+
+func (uc *useCase) CreateAppScheme(userID uuid.UUID, data *CreateAppSchemeRequestDTO) (*GetAppScheme, e.ApiError) {
+	scheme := strings.ToLower(strings.TrimSuffix(strings.TrimSpace(data.Scheme), "://"))
+	if !appSchemePattern.MatchString(scheme) {
+		return nil, e.NewApiError(400, "Scheme must start with a letter followed by letters, digits, +, - or .")
+	}
+	if reservedAppSchemes[scheme] {
+		return nil, e.NewApiError(400, "The "+scheme+" scheme cannot be registered")
+	}
+
+	appScheme := NewAppScheme(userID, scheme)
+	if err := uc.repository.CreateAppScheme(appScheme); err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, e.NewApiError(400, "App scheme already exists")
+		}
+		return nil, e.NewApiError(500, err.Error())
+	}
+
+	res := toGetAppScheme(appScheme)
+	return &res, nil
+}
+
+func (uc *useCase) GetAllAppSchemes(userID uuid.UUID) (*GetAllAppSchemesResponseDTO, e.ApiError) {
+	appSchemes, err := uc.repository.GetAllAppSchemes(userID)
+	if err != nil {
+		return nil, e.NewApiError(500, err.Error())
+	}
+
+	data := make([]GetAppScheme, 0, len(appSchemes))
+	for _, appScheme := range appSchemes {
+		data = append(data, toGetAppScheme(appScheme))
+	}
+
+	return &GetAllAppSchemesResponseDTO{AppSchemes: data}, nil
+}
+
+// DeleteAppScheme unregisters a scheme. Links keep their app URLs, but
+// visitors are redirected without the interstitial until the scheme is
+// registered again.
+func (uc *useCase) DeleteAppScheme(userID, id uuid.UUID) e.ApiError {
+	appScheme, err := uc.repository.GetAppSchemeByID(id)
+	if err != nil {
+		return e.NewApiError(404, "App scheme not found")
+	}
+
+	if !appScheme.IsOwnedBy(userID) {
+		return e.NewApiError(403, "You do not have permission to access this app scheme")
+	}
+
+	if err := uc.repository.DeleteAppScheme(appScheme); err != nil {
+		return e.NewApiError(500, err.Error())
+	}
+
+	return nil
+}
+
+// checkDeepLink validates the app configuration of a link owned by userID.
+// Universal links and store URLs go through the URL policy like
+// destinations do; other app URLs must use a scheme the owner registered.
+func (uc *useCase) checkDeepLink(userID uuid.UUID, data *DeepLinkDTO) (DeepLink, e.ApiError) {
+	var deepLink DeepLink
+	var errApi e.ApiError
+	if deepLink.IOSAppURL, errApi = uc.checkAppURL(userID, "ios_app_url", data.IOSAppURL); errApi != nil {
+		return DeepLink{}, errApi
+	}
+	if deepLink.AndroidAppURL, errApi = uc.checkAppURL(userID, "android_app_url", data.AndroidAppURL); errApi != nil {
+		return DeepLink{}, errApi
+	}
+	if deepLink.IOSStoreURL, errApi = uc.checkStoreURL("ios_store_url", data.IOSStoreURL, deepLink.IOSAppURL); errApi != nil {
+		return DeepLink{}, errApi
+	}
+	if deepLink.AndroidStoreURL, errApi = uc.checkStoreURL("android_store_url", data.AndroidStoreURL, deepLink.AndroidAppURL); errApi != nil {
+		return DeepLink{}, errApi
+	}
+	return deepLink, nil
+}
+
+func (uc *useCase) checkAppURL(userID uuid.UUID, field, appURL string) (string, e.ApiError) {
+	if appURL == "" {
+		return "", nil
+	}
+
+	scheme, ok := appURLScheme(appURL)
+	if !ok {
+		return "", e.NewApiError(400, "deep_link."+field+" must be an absolute URL such as myapp://path")
+	}
+
+	if scheme == "http" || scheme == "https" {
+		universalLink, errApi := uc.checkOriginalURL(appURL)
+		if errApi != nil {
+			return "", e.NewApiError(400, "deep_link."+field+": "+errApi.Error())
+		}
+		return universalLink, nil
+	}
+
+	registered, err := uc.isRegisteredAppScheme(userID, scheme)
+	if err != nil {
+		return "", e.NewApiError(500, err.Error())
+	}
+	if !registered {
+		return "", e.NewApiError(400, "deep_link."+field+" must be an http(s) URL or use one of your registered app schemes")
+	}
+	return appURL, nil
+}
+
+// isAllowedAppURL checks an app URL again before the interstitial is served,
+// so links saved before their scheme was unregistered stop opening the app.
+func (uc *useCase) isAllowedAppURL(userID uuid.UUID, appURL string) bool {
+	scheme, ok := appURLScheme(appURL)
+	if !ok {
+		return false
+	}
+	if scheme == "http" || scheme == "https" {
+		return true
+	}
+
+	registered, err := uc.isRegisteredAppScheme(userID, scheme)
+	if err != nil {
+		log.Println(err)
+		return false
+	}
+	return registered
+}
+
+func (uc *useCase) isRegisteredAppScheme(userID uuid.UUID, scheme string) (bool, error) {
+	if reservedAppSchemes[scheme] {
+		return false, nil
+	}
+	return uc.repository.HasAppScheme(userID, scheme)
+}
+
+// isSafeAppURL reports whether appURL may be placed in the interstitial as
+// is: it is a web link or uses a scheme that can be registered.
+func isSafeAppURL(appURL string) bool {
+	scheme, ok := appURLScheme(appURL)
+	return ok && (scheme == "http" || scheme == "https" || !reservedAppSchemes[scheme])
+}
+
+// appURLScheme returns the lower case scheme of an absolute app URL. ok is
+// false when appURL has no valid scheme.
+func appURLScheme(appURL string) (scheme string, ok bool) {
+	parsed, err := url.Parse(appURL)
+	if err != nil {
+		return "", false
+	}
+	scheme = strings.ToLower(parsed.Scheme)
+	return scheme, appSchemePattern.MatchString(scheme)
+}
+
+func (uc *useCase) checkStoreURL(field, storeURL, appURL string) (string, e.ApiError) {
+	if storeURL == "" {
+		return "", nil
+	}
+
+	if appURL == "" {
+		return "", e.NewApiError(400, "deep_link."+field+" requires an app URL for the same platform")
+	}
+
+	storeURL, errApi := uc.checkOriginalURL(storeURL)
+	if errApi != nil {
+		return "", e.NewApiError(400, "deep_link."+field+": "+errApi.Error())
+	}
+	return storeURL, nil
+}
+
+func toGetAppScheme(appScheme *AppSchemeModel) GetAppScheme {
+	return GetAppScheme{
+		ID:        appScheme.ID.String(),
+		Scheme:    appScheme.Scheme,
+		CreatedAt: appScheme.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
+
+func toDeepLinkDTO(deepLink DeepLink) *DeepLinkDTO {
+	if !deepLink.IsSet() {
+		return nil
+	}
+	return &DeepLinkDTO{
+		IOSAppURL:       deepLink.IOSAppURL,
+		IOSStoreURL:     deepLink.IOSStoreURL,
+		AndroidAppURL:   deepLink.AndroidAppURL,
+		AndroidStoreURL: deepLink.AndroidStoreURL,
+	}
+}
+
+type (
+	// appleAppSiteAssociation lets iOS open short links in the app. See
+	// https://developer.apple.com/documentation/bundleresources/applinks
+	appleAppSiteAssociation struct {
+		AppLinks appleAppLinks `json:"applinks"`
+	}
+
+	appleAppLinks struct {
+		Details []appleAppLinksDetail `json:"details"`
+	}
+
+	appleAppLinksDetail struct {
+		AppIDs     []string             `json:"appIDs"`
+		Components []appleAppLinksMatch `json:"components"`
+	}
+
+	appleAppLinksMatch struct {
+		Path    string `json:"/"`
+		Exclude bool   `json:"exclude,omitempty"`
+	}
+
+	// assetLinkStatement lets Android open short links in the app. See
+	// https://developer.android.com/training/app-links/verify-android-applinks
+	assetLinkStatement struct {
+		Relation []string        `json:"relation"`
+		Target   assetLinkTarget `json:"target"`
+	}
+
+	assetLinkTarget struct {
+		Namespace              string   `json:"namespace"`
+		PackageName            string   `json:"package_name"`
+		SHA256CertFingerprints []string `json:"sha256_cert_fingerprints"`
+	}
+)
+
+// newAppleAppSiteAssociation claims every short link for the given apps,
+// leaving the API and bio pages to the browser.
+func newAppleAppSiteAssociation(appIDs []string) appleAppSiteAssociation {
+	return appleAppSiteAssociation{AppLinks: appleAppLinks{Details: []appleAppLinksDetail{{
+		AppIDs: appIDs,
+		Components: []appleAppLinksMatch{
+			{Path: "/api/*", Exclude: true},
+			{Path: "/@*", Exclude: true},
+			{Path: "/*"},
+		},
+	}}}}
+}
+
+func newAssetLinks(packageName string, fingerprints []string) []assetLinkStatement {
+	return []assetLinkStatement{{
+		Relation: []string{"delegate_permission/common.handle_all_urls"},
+		Target: assetLinkTarget{
+			Namespace:              "android_app",
+			PackageName:            packageName,
+			SHA256CertFingerprints: fingerprints,
+		},
+	}}
+}
+
+// splitList splits a comma separated configuration value, dropping blanks.
+func splitList(value string) []string {
+	items := make([]string, 0)
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
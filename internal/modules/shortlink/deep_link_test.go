@@ -0,0 +1,118 @@
+package shortlink
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type appSchemeRepositoryStub struct {
+	IRepository
+	userID  uuid.UUID
+	schemes []string
+}
+
+func (r *appSchemeRepositoryStub) HasAppScheme(userID uuid.UUID, scheme string) (bool, error) {
+	return userID == r.userID && slices.Contains(r.schemes, scheme), nil
+}
+
+func TestCheckDeepLink(t *testing.T) {
+	userID := uuid.New()
+	uc := &useCase{repository: &appSchemeRepositoryStub{userID: userID, schemes: []string{"myapp"}}}
+
+	tests := []struct {
+		name     string
+		data     DeepLinkDTO
+		expected DeepLink
+		err      string
+	}{
+		{
+			name:     "Registered scheme with store fallback",
+			data:     DeepLinkDTO{IOSAppURL: "MyApp://launch", IOSStoreURL: "https://apps.apple.com/app/id123"},
+			expected: DeepLink{IOSAppURL: "MyApp://launch", IOSStoreURL: "https://apps.apple.com/app/id123"},
+		},
+		{
+			name:     "Universal link",
+			data:     DeepLinkDTO{IOSAppURL: "https://app.example.com/launch"},
+			expected: DeepLink{IOSAppURL: "https://app.example.com/launch"},
+		},
+		{name: "Empty removes the configuration", data: DeepLinkDTO{}},
+		{name: "Relative app URL", data: DeepLinkDTO{IOSAppURL: "launch"}, err: "deep_link.ios_app_url must be an absolute URL such as myapp://path"},
+		{name: "Unregistered scheme", data: DeepLinkDTO{IOSAppURL: "otherapp://launch"}, err: "deep_link.ios_app_url must be an http(s) URL or use one of your registered app schemes"},
+		{name: "Android intent", data: DeepLinkDTO{AndroidAppURL: "intent://launch#Intent;scheme=myapp;package=com.example.app;end"}, err: "deep_link.android_app_url must be an http(s) URL or use one of your registered app schemes"},
+		{name: "Javascript app URL", data: DeepLinkDTO{AndroidAppURL: "JavaScript:alert(1)"}, err: "deep_link.android_app_url must be an http(s) URL or use one of your registered app schemes"},
+		{name: "Store URL without app URL", data: DeepLinkDTO{AndroidStoreURL: "https://play.google.com/store/apps/details?id=com.example.app"}, err: "deep_link.android_store_url requires an app URL for the same platform"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deepLink, errApi := uc.checkDeepLink(userID, &tt.data)
+			if tt.err != "" {
+				assert.EqualError(t, errApi, tt.err)
+				return
+			}
+			assert.Nil(t, errApi)
+			assert.Equal(t, tt.expected, deepLink)
+		})
+	}
+
+	// Schemes registered by another user do not count.
+	_, errApi := uc.checkDeepLink(uuid.New(), &DeepLinkDTO{IOSAppURL: "myapp://launch"})
+	assert.Equal(t, 400, errApi.Code())
+}
+
+func TestIsAllowedAppURL(t *testing.T) {
+	userID := uuid.New()
+	uc := &useCase{repository: &appSchemeRepositoryStub{userID: userID, schemes: []string{"myapp"}}}
+
+	assert.True(t, uc.isAllowedAppURL(userID, "myapp://launch"))
+	assert.True(t, uc.isAllowedAppURL(userID, "https://app.example.com/launch"))
+	assert.False(t, uc.isAllowedAppURL(userID, "otherapp://launch"))
+	assert.False(t, uc.isAllowedAppURL(uuid.New(), "myapp://launch"))
+	assert.False(t, uc.isAllowedAppURL(userID, "javascript:alert(1)"))
+
+	assert.True(t, isSafeAppURL("myapp://launch"))
+	assert.False(t, isSafeAppURL("data:text/html,<script>alert(1)</script>"))
+	assert.False(t, isSafeAppURL("launch"))
+}
+
+func TestCreateAppScheme_Validation(t *testing.T) {
+	uc := &useCase{}
+
+	tests := []struct {
+		scheme string
+		err    string
+	}{
+		{scheme: "1app", err: "Scheme must start with a letter followed by letters, digits, +, - or ."},
+		{scheme: "my_app", err: "Scheme must start with a letter followed by letters, digits, +, - or ."},
+		{scheme: "JavaScript", err: "The javascript scheme cannot be registered"},
+		{scheme: "https://", err: "The https scheme cannot be registered"},
+		{scheme: "intent", err: "The intent scheme cannot be registered"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.scheme, func(t *testing.T) {
+			_, errApi := uc.CreateAppScheme(uuid.New(), &CreateAppSchemeRequestDTO{Scheme: tt.scheme})
+			assert.EqualError(t, errApi, tt.err)
+		})
+	}
+}
+
+func TestAppAssociationFiles(t *testing.T) {
+	aasa, err := json.Marshal(newAppleAppSiteAssociation(splitList("ABCDE12345.com.example.app, ")))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"applinks": {"details": [{
+		"appIDs": ["ABCDE12345.com.example.app"],
+		"components": [{"/": "/api/*", "exclude": true}, {"/": "/@*", "exclude": true}, {"/": "/*"}]
+	}]}}`, string(aasa))
+
+	assetLinks, err := json.Marshal(newAssetLinks("com.example.app", splitList("AA:BB,CC:DD")))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `[{
+		"relation": ["delegate_permission/common.handle_all_urls"],
+		"target": {"namespace": "android_app", "package_name": "com.example.app", "sha256_cert_fingerprints": ["AA:BB", "CC:DD"]}
+	}]`, string(assetLinks))
+}
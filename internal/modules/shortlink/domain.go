@@ -1,23 +1,646 @@
 package shortlink
 
 import (
+	"errors"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"github.com/xcurvnubaim/njajal-gin-golang/internal/modules/common"
 )
 
 type ShortenerLinkModel struct {
 	common.BaseModels
-	OriginalURL  string `gorm:"column:original_url;not null"`
-	ShortenerURL string `gorm:"column:shortener_url;not null"`
+	UserID       uuid.UUID  `gorm:"column:user_id;type:uuid"`
+	OriginalURL  string     `gorm:"column:original_url;not null"`
+	ShortenerURL string     `gorm:"column:shortener_url;not null"`
+	ActiveFrom   *time.Time `gorm:"column:active_from;default:null"`
+	ExpiresAt    *time.Time `gorm:"column:expires_at;default:null"`
+	MaxClicks    *int       `gorm:"column:max_clicks;default:null"`
+	ClickCount   int64      `gorm:"column:click_count;default:0"`
+	IsExpired    bool       `gorm:"column:is_expired;default:false"`
+	PasswordHash string     `gorm:"column:password_hash;default:null"`
+	// RedirectStatus is one of 301, 302, 307 or 308.
+	RedirectStatus int        `gorm:"column:redirect_status;default:302"`
+	ForwardQuery   bool       `gorm:"column:forward_query;default:false"`
+	CampaignID     *uuid.UUID `gorm:"column:campaign_id;type:uuid"`
+	// Campaign is only loaded on the redirect path.
+	Campaign *CampaignModel `gorm:"foreignKey:CampaignID"`
+	FolderID *uuid.UUID     `gorm:"column:folder_id;type:uuid"`
+	// DomainID is the branded domain the link is served from, or nil for
+	// BASE_URL. Codes are unique per domain.
+	DomainID *uuid.UUID   `gorm:"column:domain_id;type:uuid"`
+	Domain   *DomainModel `gorm:"foreignKey:DomainID"`
+	// The Preview fields override the card shown by link unfurlers.
+	PreviewTitle       string `gorm:"column:preview_title;default:''"`
+	PreviewDescription string `gorm:"column:preview_description;default:''"`
+	PreviewImageURL    string `gorm:"column:preview_image_url;default:''"`
+	// StatsPublic publishes aggregate click stats at /:code/stats.
+	StatsPublic bool `gorm:"column:stats_public;default:false"`
+	// DeepLink sends iOS and Android visitors to the app through an
+	// interstitial page.
+	DeepLink DeepLink `gorm:"embedded;embeddedPrefix:deep_link_"`
+	// IsBroken and the Health fields are written by the health checker.
+	IsBroken         bool       `gorm:"column:is_broken;default:false"`
+	HealthStatusCode *int       `gorm:"column:health_status_code;default:null"`
+	HealthLatencyMs  *int       `gorm:"column:health_latency_ms;default:null"`
+	HealthError      string     `gorm:"column:health_error;default:''"`
+	HealthCheckedAt  *time.Time `gorm:"column:health_checked_at;default:null"`
+	// Tags are loaded for listings and written through
+	// ReplaceShortenerLinkTags, never on save.
+	Tags []TagModel `gorm:"many2many:shortener_link_tags;joinForeignKey:ShortenerLinkID;joinReferences:TagID"`
+	// Rules and Variants are loaded, ordered by position, on the redirect
+	// path and by GetShortenerLinkByID; they are written through their
+	// Replace methods, never on save.
+	Rules    []ShortenerLinkRuleModel    `gorm:"foreignKey:ShortenerLinkID"`
+	Variants []ShortenerLinkVariantModel `gorm:"foreignKey:ShortenerLinkID"`
 }
 
+var (
+	ErrLinkNotActive = errors.New("Shortener link is not active yet")
+	ErrLinkExpired   = errors.New("Shortener link has expired")
+	ErrLinkExhausted = errors.New("Shortener link has reached its click limit")
+	ErrLinkLocked    = errors.New("Shortener link is password protected")
+	ErrLinkBlocked   = errors.New("Shortener link destination is blocked")
+)
+
 func (ShortenerLinkModel) TableName() string {
 	return "shortener_links"
 }
 
-func NewShortenerLink(originalURL, shortenerURL string) *ShortenerLinkModel {
+func NewShortenerLink(userID uuid.UUID, originalURL, shortenerURL string) *ShortenerLinkModel {
 	return &ShortenerLinkModel{
-		BaseModels:   common.NewBaseModels(),
-		OriginalURL:  originalURL,
-		ShortenerURL: shortenerURL,
+		BaseModels:     common.NewBaseModels(),
+		UserID:         userID,
+		OriginalURL:    originalURL,
+		ShortenerURL:   shortenerURL,
+		RedirectStatus: DefaultRedirectStatus,
+	}
+}
+
+func (m *ShortenerLinkModel) IsOwnedBy(userID uuid.UUID) bool {
+	return m.UserID == userID
+}
+
+func (m *ShortenerLinkModel) IsProtected() bool {
+	return m.PasswordHash != ""
+}
+
+// CheckAvailability reports why the link cannot be served at the given time,
+// or nil when it can.
+func (m *ShortenerLinkModel) CheckAvailability(now time.Time) error {
+	switch {
+	case m.ActiveFrom != nil && now.Before(*m.ActiveFrom):
+		return ErrLinkNotActive
+	case m.MaxClicks != nil && m.ClickCount >= int64(*m.MaxClicks):
+		return ErrLinkExhausted
+	case m.IsExpired || (m.ExpiresAt != nil && !now.Before(*m.ExpiresAt)):
+		return ErrLinkExpired
+	}
+	return nil
+}
+
+// IsPermanentRedirect reports whether browsers may reuse the redirect
+// without asking the server again.
+func (m *ShortenerLinkModel) IsPermanentRedirect() bool {
+	return m.RedirectStatus == 301 || m.RedirectStatus == 308
+}
+
+// RedirectMaxAge is how long a redirect may be cached at the given time. Only
+// permanent redirects are cached, and never for links whose every visit must
+// reach the server: protected, click-limited, targeted or about to expire.
+func (m *ShortenerLinkModel) RedirectMaxAge(now time.Time) time.Duration {
+	if !m.IsPermanentRedirect() || m.IsProtected() || m.MaxClicks != nil || len(m.Rules) > 0 || len(m.Variants) > 0 || m.HasPreview() || m.DeepLink.IsSet() {
+		return 0
+	}
+
+	maxAge := PermanentRedirectMaxAge
+	if m.ExpiresAt != nil {
+		if untilExpiry := m.ExpiresAt.Sub(now); untilExpiry < maxAge {
+			maxAge = untilExpiry
+		}
+	}
+	if maxAge < time.Second {
+		return 0
+	}
+	return maxAge.Truncate(time.Second)
+}
+
+// HasPreview reports whether the owner set any preview metadata. Unfurlers
+// are only served a preview page for such links; otherwise they follow the
+// redirect and read the destination's own tags.
+func (m *ShortenerLinkModel) HasPreview() bool {
+	return m.PreviewTitle != "" || m.PreviewDescription != "" || m.PreviewImageURL != ""
+}
+
+// CampaignModel groups links and holds the UTM parameters added to their
+// destinations at redirect time.
+type CampaignModel struct {
+	common.BaseModels
+	UserID      uuid.UUID `gorm:"column:user_id;type:uuid;not null"`
+	Name        string    `gorm:"column:name;not null"`
+	UTMSource   string    `gorm:"column:utm_source"`
+	UTMMedium   string    `gorm:"column:utm_medium"`
+	UTMCampaign string    `gorm:"column:utm_campaign"`
+	UTMTerm     string    `gorm:"column:utm_term"`
+	UTMContent  string    `gorm:"column:utm_content"`
+}
+
+func (CampaignModel) TableName() string {
+	return "campaigns"
+}
+
+func NewCampaign(userID uuid.UUID, name string) *CampaignModel {
+	return &CampaignModel{
+		BaseModels: common.NewBaseModels(),
+		UserID:     userID,
+		Name:       name,
+	}
+}
+
+func (m *CampaignModel) IsOwnedBy(userID uuid.UUID) bool {
+	return m.UserID == userID
+}
+
+// UTMParams returns the campaign's non-empty UTM parameters.
+func (m *CampaignModel) UTMParams() url.Values {
+	params := make(url.Values)
+	for key, value := range map[string]string{
+		"utm_source":   m.UTMSource,
+		"utm_medium":   m.UTMMedium,
+		"utm_campaign": m.UTMCampaign,
+		"utm_term":     m.UTMTerm,
+		"utm_content":  m.UTMContent,
+	} {
+		if value != "" {
+			params.Set(key, value)
+		}
+	}
+	return params
+}
+
+// DomainModel is a branded hostname links can be served from. Links are only
+// served on it once the owner has proved control of its DNS.
+type DomainModel struct {
+	common.BaseModels
+	UserID            uuid.UUID  `gorm:"column:user_id;type:uuid;not null"`
+	Hostname          string     `gorm:"column:hostname;not null"`
+	VerificationToken string     `gorm:"column:verification_token;not null"`
+	VerifiedAt        *time.Time `gorm:"column:verified_at;default:null"`
+}
+
+func (DomainModel) TableName() string {
+	return "domains"
+}
+
+func NewDomain(userID uuid.UUID, hostname, verificationToken string) *DomainModel {
+	return &DomainModel{
+		BaseModels:        common.NewBaseModels(),
+		UserID:            userID,
+		Hostname:          hostname,
+		VerificationToken: verificationToken,
+	}
+}
+
+func (m *DomainModel) IsOwnedBy(userID uuid.UUID) bool {
+	return m.UserID == userID
+}
+
+func (m *DomainModel) IsVerified() bool {
+	return m.VerifiedAt != nil
+}
+
+// VerificationRecordName is the DNS name the owner publishes the
+// verification TXT record on.
+func (m *DomainModel) VerificationRecordName() string {
+	return DomainVerificationRecordPrefix + "." + m.Hostname
+}
+
+// VerificationRecordValue is the expected content of the TXT record.
+func (m *DomainModel) VerificationRecordValue() string {
+	return DomainVerificationValuePrefix + m.VerificationToken
+}
+
+// DeepLink is the app configuration of a link. App URLs are universal links
+// or use one of the owner's registered app schemes. Visitors without the app are sent to the store URL of
+// their platform, or to the link destination when it is empty.
+type DeepLink struct {
+	IOSAppURL       string `gorm:"column:ios_app_url;default:''" json:"ios_app_url"`
+	IOSStoreURL     string `gorm:"column:ios_store_url;default:''" json:"ios_store_url"`
+	AndroidAppURL   string `gorm:"column:android_app_url;default:''" json:"android_app_url"`
+	AndroidStoreURL string `gorm:"column:android_store_url;default:''" json:"android_store_url"`
+}
+
+func (d DeepLink) IsSet() bool {
+	return d.IOSAppURL != "" || d.AndroidAppURL != ""
+}
+
+// For returns the app and store URLs for a visitor on the given platforms.
+// appURL is empty when the link has no app for them.
+func (d DeepLink) For(platforms map[string]bool) (appURL, storeURL string) {
+	switch {
+	case platforms[PlatformIOS]:
+		return d.IOSAppURL, d.IOSStoreURL
+	case platforms[PlatformAndroid]:
+		return d.AndroidAppURL, d.AndroidStoreURL
+	}
+	return "", ""
+}
+
+// AppSchemeModel is a custom URL scheme, such as myapp, that the owner's apps
+// handle. Deep links may only open apps through registered schemes.
+type AppSchemeModel struct {
+	common.BaseModels
+	UserID uuid.UUID `gorm:"column:user_id;type:uuid;not null"`
+	Scheme string    `gorm:"column:scheme;not null"`
+}
+
+func (AppSchemeModel) TableName() string {
+	return "app_schemes"
+}
+
+func NewAppScheme(userID uuid.UUID, scheme string) *AppSchemeModel {
+	return &AppSchemeModel{
+		BaseModels: common.NewBaseModels(),
+		UserID:     userID,
+		Scheme:     scheme,
+	}
+}
+
+func (m *AppSchemeModel) IsOwnedBy(userID uuid.UUID) bool {
+	return m.UserID == userID
+}
+
+// BioPageModel is a public profile page served at /@slug that lists some of
+// the owner's links. Slugs are unique regardless of case.
+type BioPageModel struct {
+	common.BaseModels
+	UserID      uuid.UUID `gorm:"column:user_id;type:uuid;not null"`
+	Slug        string    `gorm:"column:slug;not null"`
+	Title       string    `gorm:"column:title;not null"`
+	Description string    `gorm:"column:description;default:''"`
+	AvatarURL   string    `gorm:"column:avatar_url;default:''"`
+	Theme       string    `gorm:"column:theme;not null"`
+	// Items are ordered by position and written through ReplaceBioPageItems,
+	// never on save.
+	Items []BioPageItemModel `gorm:"foreignKey:BioPageID"`
+}
+
+func (BioPageModel) TableName() string {
+	return "bio_pages"
+}
+
+func NewBioPage(userID uuid.UUID, slug, title, description, avatarURL, theme string) *BioPageModel {
+	return &BioPageModel{
+		BaseModels:  common.NewBaseModels(),
+		UserID:      userID,
+		Slug:        slug,
+		Title:       title,
+		Description: description,
+		AvatarURL:   avatarURL,
+		Theme:       theme,
+	}
+}
+
+func (m *BioPageModel) IsOwnedBy(userID uuid.UUID) bool {
+	return m.UserID == userID
+}
+
+// BioPageItemModel places a link on a bio page. Replaced items are soft
+// deleted so their click counts are kept.
+type BioPageItemModel struct {
+	common.BaseModels
+	BioPageID       uuid.UUID `gorm:"column:bio_page_id;type:uuid;not null"`
+	ShortenerLinkID uuid.UUID `gorm:"column:shortener_link_id;type:uuid;not null"`
+	Position        int       `gorm:"column:position;not null"`
+	// Label is the text of the button; empty falls back to the link
+	// preview title, then to its short URL.
+	Label      string `gorm:"column:label;default:''"`
+	ClickCount int64  `gorm:"column:click_count;default:0"`
+	// ShortenerLink is nil once the link is deleted.
+	ShortenerLink *ShortenerLinkModel `gorm:"foreignKey:ShortenerLinkID"`
+}
+
+func (BioPageItemModel) TableName() string {
+	return "bio_page_items"
+}
+
+func NewBioPageItem(bioPageID, shortenerLinkID uuid.UUID, position int, label string) *BioPageItemModel {
+	return &BioPageItemModel{
+		BaseModels:      common.NewBaseModels(),
+		BioPageID:       bioPageID,
+		ShortenerLinkID: shortenerLinkID,
+		Position:        position,
+		Label:           label,
+	}
+}
+
+// DisplayLabel is the text shown for the item on the bio page.
+func (m *BioPageItemModel) DisplayLabel() string {
+	if m.Label != "" {
+		return m.Label
+	}
+	if m.ShortenerLink == nil {
+		return ""
 	}
+	if m.ShortenerLink.PreviewTitle != "" {
+		return m.ShortenerLink.PreviewTitle
+	}
+	return shortURL(m.ShortenerLink)
+}
+
+// TagModel labels links. A link may carry many tags and tag names are unique
+// per user regardless of case.
+type TagModel struct {
+	common.BaseModels
+	UserID uuid.UUID `gorm:"column:user_id;type:uuid;not null"`
+	Name   string    `gorm:"column:name;not null"`
+}
+
+func (TagModel) TableName() string {
+	return "tags"
+}
+
+func NewTag(userID uuid.UUID, name string) *TagModel {
+	return &TagModel{
+		BaseModels: common.NewBaseModels(),
+		UserID:     userID,
+		Name:       name,
+	}
+}
+
+func (m *TagModel) IsOwnedBy(userID uuid.UUID) bool {
+	return m.UserID == userID
+}
+
+type ShortenerLinkTagModel struct {
+	ShortenerLinkID uuid.UUID `gorm:"column:shortener_link_id;type:uuid;primaryKey"`
+	TagID           uuid.UUID `gorm:"column:tag_id;type:uuid;primaryKey"`
+}
+
+func (ShortenerLinkTagModel) TableName() string {
+	return "shortener_link_tags"
+}
+
+// FolderModel holds links; a link is in at most one folder.
+type FolderModel struct {
+	common.BaseModels
+	UserID uuid.UUID `gorm:"column:user_id;type:uuid;not null"`
+	Name   string    `gorm:"column:name;not null"`
+}
+
+func (FolderModel) TableName() string {
+	return "folders"
+}
+
+func NewFolder(userID uuid.UUID, name string) *FolderModel {
+	return &FolderModel{
+		BaseModels: common.NewBaseModels(),
+		UserID:     userID,
+		Name:       name,
+	}
+}
+
+func (m *FolderModel) IsOwnedBy(userID uuid.UUID) bool {
+	return m.UserID == userID
+}
+
+// ShortenerLinkRuleModel sends visitors matching any of Values to
+// DestinationURL instead of the link's OriginalURL. Rules are evaluated in
+// Position order and the first match wins.
+type ShortenerLinkRuleModel struct {
+	common.BaseModels
+	ShortenerLinkID uuid.UUID      `gorm:"column:shortener_link_id;type:uuid;not null"`
+	Position        int            `gorm:"column:position;not null"`
+	Type            string         `gorm:"column:type;not null"`
+	Values          pq.StringArray `gorm:"column:match_values;type:text[];not null"`
+	DestinationURL  string         `gorm:"column:destination_url;not null"`
+}
+
+func (ShortenerLinkRuleModel) TableName() string {
+	return "shortener_link_rules"
+}
+
+func NewShortenerLinkRule(shortenerLinkID uuid.UUID, position int, ruleType string, values []string, destinationURL string) *ShortenerLinkRuleModel {
+	return &ShortenerLinkRuleModel{
+		BaseModels:      common.NewBaseModels(),
+		ShortenerLinkID: shortenerLinkID,
+		Position:        position,
+		Type:            ruleType,
+		Values:          values,
+		DestinationURL:  destinationURL,
+	}
+}
+
+// ShortenerLinkVariantModel is one destination of a split test. Visitors are
+// spread across the variants of a link in proportion to Weight. Replaced
+// variants are soft deleted so their clicks stay attributable.
+type ShortenerLinkVariantModel struct {
+	common.BaseModels
+	ShortenerLinkID uuid.UUID `gorm:"column:shortener_link_id;type:uuid;not null"`
+	Position        int       `gorm:"column:position;not null"`
+	Name            string    `gorm:"column:name;not null"`
+	DestinationURL  string    `gorm:"column:destination_url;not null"`
+	Weight          int       `gorm:"column:weight;not null"`
+}
+
+func (ShortenerLinkVariantModel) TableName() string {
+	return "shortener_link_variants"
+}
+
+func NewShortenerLinkVariant(shortenerLinkID uuid.UUID, position int, name, destinationURL string, weight int) *ShortenerLinkVariantModel {
+	return &ShortenerLinkVariantModel{
+		BaseModels:      common.NewBaseModels(),
+		ShortenerLinkID: shortenerLinkID,
+		Position:        position,
+		Name:            name,
+		DestinationURL:  destinationURL,
+		Weight:          weight,
+	}
+}
+
+// ShortenerLinkRevisionModel records the state of a link after a change.
+// Versions count up from 1 per link; UserID is whoever made the change.
+// RestoredFrom is set when the change rolled the link back to an earlier
+// version.
+type ShortenerLinkRevisionModel struct {
+	common.BaseModels
+	ShortenerLinkID uuid.UUID    `gorm:"column:shortener_link_id;type:uuid;not null"`
+	Version         int          `gorm:"column:version;not null"`
+	UserID          *uuid.UUID   `gorm:"column:user_id;type:uuid"`
+	Action          string       `gorm:"column:action;not null"`
+	RestoredFrom    *int         `gorm:"column:restored_from;default:null"`
+	Snapshot        LinkSnapshot `gorm:"column:snapshot;type:jsonb;serializer:json;not null"`
 }
+
+func (ShortenerLinkRevisionModel) TableName() string {
+	return "shortener_link_revisions"
+}
+
+func NewShortenerLinkRevision(shortenerLink *ShortenerLinkModel, userID uuid.UUID, action string) *ShortenerLinkRevisionModel {
+	return &ShortenerLinkRevisionModel{
+		BaseModels:      common.NewBaseModels(),
+		ShortenerLinkID: shortenerLink.ID,
+		UserID:          &userID,
+		Action:          action,
+		Snapshot:        NewLinkSnapshot(shortenerLink),
+	}
+}
+
+type ShortenerLinkClickModel struct {
+	common.BaseModels
+	ShortenerLinkID uuid.UUID  `gorm:"column:shortener_link_id;type:uuid;not null"`
+	ClickedAt       time.Time  `gorm:"column:clicked_at;not null"`
+	Referrer        string     `gorm:"column:referrer"`
+	UserAgent       string     `gorm:"column:user_agent"`
+	IPHash          string     `gorm:"column:ip_hash"`
+	Device          string     `gorm:"column:device"`
+	Browser         string     `gorm:"column:browser"`
+	OS              string     `gorm:"column:os"`
+	VariantID       *uuid.UUID `gorm:"column:variant_id;type:uuid"`
+}
+
+func (ShortenerLinkClickModel) TableName() string {
+	return "shortener_link_clicks"
+}
+
+func NewShortenerLinkClick(shortenerLinkID uuid.UUID, clickedAt time.Time, referrer, userAgent, ipHash, device, browser, os string) *ShortenerLinkClickModel {
+	return &ShortenerLinkClickModel{
+		BaseModels:      common.NewBaseModels(),
+		ShortenerLinkID: shortenerLinkID,
+		ClickedAt:       clickedAt,
+		Referrer:        referrer,
+		UserAgent:       userAgent,
+		IPHash:          ipHash,
+		Device:          device,
+		Browser:         browser,
+		OS:              os,
+	}
+}
+
+type BlockedDomainModel struct {
+	common.BaseModels
+	Domain    string     `gorm:"column:domain;not null"`
+	Reason    string     `gorm:"column:reason"`
+	CreatedBy *uuid.UUID `gorm:"column:created_by;type:uuid"`
+}
+
+func (BlockedDomainModel) TableName() string {
+	return "blocked_domains"
+}
+
+func NewBlockedDomain(domain, reason string, createdBy uuid.UUID) *BlockedDomainModel {
+	return &BlockedDomainModel{
+		BaseModels: common.NewBaseModels(),
+		Domain:     domain,
+		Reason:     reason,
+		CreatedBy:  &createdBy,
+	}
+}
+
+type (
+	// Visit describes the incoming request that resolved a short link.
+	// UnlockToken carries the signed cookie issued after a successful unlock.
+	// SkipClick resolves the link without counting it, as for HEAD requests.
+	Visit struct {
+		Referrer       string
+		UserAgent      string
+		IP             string
+		UnlockToken    string
+		Query          url.Values
+		AcceptLanguage string
+		// VariantID is the split test variant previously assigned to the
+		// visitor, read from the sticky cookie.
+		VariantID string
+		SkipClick bool
+	}
+
+	// Redirect is where and how a visit to a short link is sent. A zero
+	// MaxAge means the response must not be cached.
+	// VariantID is set when a split test variant was served.
+	// AppURL is set when the visitor should get the deep link interstitial,
+	// which tries to open the app and falls back to URL.
+	Redirect struct {
+		URL       string
+		Status    int
+		MaxAge    time.Duration
+		VariantID string
+		AppURL    string
+	}
+
+	ClickSeriesPoint struct {
+		Bucket time.Time
+		Clicks int64
+	}
+
+	ReferrerCount struct {
+		Referrer string
+		Clicks   int64
+	}
+
+	// HealthCheck is the outcome of probing a link destination. StatusCode
+	// is zero when no response was received.
+	HealthCheck struct {
+		StatusCode int
+		Latency    time.Duration
+		Error      string
+		Broken     bool
+		CheckedAt  time.Time
+	}
+
+	CampaignLinkCount struct {
+		ShortenerLinkID uuid.UUID
+		ShortenerURL    string
+		OriginalURL     string
+		Clicks          int64
+		UniqueVisitors  int64
+	}
+
+	VariantCount struct {
+		VariantID      uuid.UUID
+		Name           string
+		DestinationURL string
+		Weight         int
+		Archived       bool
+		Clicks         int64
+		UniqueVisitors int64
+	}
+
+	// Preview is what link unfurlers and the preview page show for a link.
+	Preview struct {
+		ShortURL       string
+		DestinationURL string
+		Title          string
+		Description    string
+		ImageURL       string
+	}
+
+	// BioPage is a bio page as shown to visitors. Only the links that can
+	// currently be served are listed.
+	BioPage struct {
+		Slug        string
+		Title       string
+		Description string
+		AvatarURL   string
+		Theme       string
+		Links       []BioPageLink
+	}
+
+	// BioPageLink points at the click tracking route of a bio page item.
+	BioPageLink struct {
+		URL   string
+		Label string
+	}
+
+	// QRCode is a rendered QR code image. ETag is derived from Data, which is
+	// deterministic for a given link and set of options.
+	QRCode struct {
+		Data        []byte
+		ContentType string
+		ETag        string
+		// NotModified is set instead of Data when the ETag matches the
+		// request's If-None-Match.
+		NotModified bool
+	}
+)
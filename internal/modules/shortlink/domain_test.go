@@ -0,0 +1,163 @@
+package shortlink
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShortenerLinkModel_CheckAvailability(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	past := now.Add(-time.Hour)
+	future := now.Add(time.Hour)
+	maxClicks := 3
+
+	tests := []struct {
+		name     string
+		modify   func(m *ShortenerLinkModel)
+		expected error
+	}{
+		{
+			name:     "No restrictions",
+			modify:   func(m *ShortenerLinkModel) {},
+			expected: nil,
+		},
+		{
+			name:     "Active window started",
+			modify:   func(m *ShortenerLinkModel) { m.ActiveFrom = &past; m.ExpiresAt = &future },
+			expected: nil,
+		},
+		{
+			name:     "Not active yet",
+			modify:   func(m *ShortenerLinkModel) { m.ActiveFrom = &future },
+			expected: ErrLinkNotActive,
+		},
+		{
+			name:     "Expired by date",
+			modify:   func(m *ShortenerLinkModel) { m.ExpiresAt = &past },
+			expected: ErrLinkExpired,
+		},
+		{
+			name:     "Marked expired by sweeper",
+			modify:   func(m *ShortenerLinkModel) { m.IsExpired = true },
+			expected: ErrLinkExpired,
+		},
+		{
+			name:     "Below click limit",
+			modify:   func(m *ShortenerLinkModel) { m.MaxClicks = &maxClicks; m.ClickCount = 2 },
+			expected: nil,
+		},
+		{
+			name:     "Click limit reached",
+			modify:   func(m *ShortenerLinkModel) { m.MaxClicks = &maxClicks; m.ClickCount = 3 },
+			expected: ErrLinkExhausted,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			link := NewShortenerLink(uuid.New(), "https://example.com", "abc")
+			tt.modify(link)
+			assert.Equal(t, tt.expected, link.CheckAvailability(now))
+		})
+	}
+}
+
+func TestShortenerLinkModel_RedirectMaxAge(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	soon := now.Add(10 * time.Minute)
+	maxClicks := 3
+
+	tests := []struct {
+		name     string
+		modify   func(m *ShortenerLinkModel)
+		expected time.Duration
+	}{
+		{
+			name:     "Temporary redirect by default",
+			modify:   func(m *ShortenerLinkModel) {},
+			expected: 0,
+		},
+		{
+			name:     "Permanent redirect",
+			modify:   func(m *ShortenerLinkModel) { m.RedirectStatus = 308 },
+			expected: PermanentRedirectMaxAge,
+		},
+		{
+			name:     "Permanent redirect capped by expiry",
+			modify:   func(m *ShortenerLinkModel) { m.RedirectStatus = 301; m.ExpiresAt = &soon },
+			expected: 10 * time.Minute,
+		},
+		{
+			name:     "Permanent redirect with click limit",
+			modify:   func(m *ShortenerLinkModel) { m.RedirectStatus = 301; m.MaxClicks = &maxClicks },
+			expected: 0,
+		},
+		{
+			name:     "Permanent redirect with password",
+			modify:   func(m *ShortenerLinkModel) { m.RedirectStatus = 301; m.PasswordHash = "hash" },
+			expected: 0,
+		},
+		{
+			name:     "Permanent redirect with preview metadata",
+			modify:   func(m *ShortenerLinkModel) { m.RedirectStatus = 301; m.PreviewTitle = "Launch" },
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			link := NewShortenerLink(uuid.New(), "https://example.com", "abc")
+			tt.modify(link)
+			assert.Equal(t, tt.expected, link.RedirectMaxAge(now))
+		})
+	}
+}
+
+func TestCampaignModel_UTMParams(t *testing.T) {
+	campaign := NewCampaign(uuid.New(), "Spring sale")
+	assert.Empty(t, campaign.UTMParams())
+
+	campaign.UTMSource = "newsletter"
+	campaign.UTMCampaign = "spring"
+	params := campaign.UTMParams()
+	assert.Equal(t, "utm_campaign=spring&utm_source=newsletter", params.Encode())
+
+	// Existing destination values are kept.
+	merged := mergeQuery("https://example.com/?utm_source=poster", params)
+	assert.Equal(t, "https://example.com/?utm_source=poster&utm_campaign=spring", merged)
+}
+
+func TestBioPageItemModel_DisplayLabel(t *testing.T) {
+	link := NewShortenerLink(uuid.New(), "https://example.com", "abc")
+	link.Domain = &DomainModel{Hostname: "go.brand.example"}
+	item := NewBioPageItem(uuid.New(), link.ID, 0, "")
+	item.ShortenerLink = link
+	assert.Equal(t, "https://go.brand.example/abc", item.DisplayLabel())
+
+	link.PreviewTitle = "Launch"
+	assert.Equal(t, "Launch", item.DisplayLabel())
+
+	item.Label = "Read the launch post"
+	assert.Equal(t, "Read the launch post", item.DisplayLabel())
+}
+
+func TestDeepLink_For(t *testing.T) {
+	deepLink := DeepLink{
+		IOSAppURL:   "myapp://launch",
+		IOSStoreURL: "https://apps.apple.com/app/id123",
+	}
+	assert.True(t, deepLink.IsSet())
+
+	appURL, storeURL := deepLink.For(map[string]bool{PlatformIOS: true, PlatformMobile: true})
+	assert.Equal(t, "myapp://launch", appURL)
+	assert.Equal(t, "https://apps.apple.com/app/id123", storeURL)
+
+	appURL, _ = deepLink.For(map[string]bool{PlatformAndroid: true, PlatformMobile: true})
+	assert.Empty(t, appURL)
+
+	appURL, _ = deepLink.For(map[string]bool{PlatformDesktop: true})
+	assert.Empty(t, appURL)
+}
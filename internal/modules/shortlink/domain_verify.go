@@ -0,0 +1,68 @@
+package shortlink
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"net"
+	"strings"
+	"time"
+)
+
+var ErrDomainRecordNotFound = errors.New("Verification TXT record not found")
+
+// TXTResolver looks up DNS TXT records. *net.Resolver satisfies it.
+type TXTResolver interface {
+	LookupTXT(ctx context.Context, name string) ([]string, error)
+}
+
+// DomainVerifier checks that the owner of a domain published its
+// verification token in DNS.
+type DomainVerifier struct {
+	resolver TXTResolver
+	timeout  time.Duration
+}
+
+// NewDomainVerifier uses net.DefaultResolver when resolver is nil.
+func NewDomainVerifier(resolver TXTResolver, timeout time.Duration) *DomainVerifier {
+	if resolver == nil {
+		resolver = net.DefaultResolver
+	}
+	if timeout <= 0 {
+		timeout = DefaultDomainVerifyTimeout
+	}
+	return &DomainVerifier{resolver: resolver, timeout: timeout}
+}
+
+// Verify returns nil when one of the TXT records on the verification name
+// holds the expected value, and ErrDomainRecordNotFound when none does.
+func (v *DomainVerifier) Verify(domain *DomainModel) error {
+	ctx, cancel := context.WithTimeout(context.Background(), v.timeout)
+	defer cancel()
+
+	records, err := v.resolver.LookupTXT(ctx, domain.VerificationRecordName())
+	if err != nil {
+		var dnsErr *net.DNSError
+		if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+			return ErrDomainRecordNotFound
+		}
+		return err
+	}
+
+	expected := domain.VerificationRecordValue()
+	for _, record := range records {
+		if strings.TrimSpace(record) == expected {
+			return nil
+		}
+	}
+	return ErrDomainRecordNotFound
+}
+
+func newVerificationToken() (string, error) {
+	token := make([]byte, 16)
+	if _, err := rand.Read(token); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(token), nil
+}
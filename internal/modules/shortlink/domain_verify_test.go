@@ -0,0 +1,73 @@
+package shortlink
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type txtResolverStub struct {
+	records map[string][]string
+	err     error
+}
+
+func (s *txtResolverStub) LookupTXT(ctx context.Context, name string) ([]string, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	records, ok := s.records[name]
+	if !ok {
+		return nil, &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+	}
+	return records, nil
+}
+
+func TestDomainVerifier_Verify(t *testing.T) {
+	domain := NewDomain(uuid.New(), "go.example.com", "token")
+	assert.Equal(t, "_shortlink-verify.go.example.com", domain.VerificationRecordName())
+	assert.Equal(t, "shortlink-verify=token", domain.VerificationRecordValue())
+
+	lookupErr := errors.New("server misbehaving")
+	tests := []struct {
+		name     string
+		resolver *txtResolverStub
+		expected error
+	}{
+		{
+			name:     "Record published",
+			resolver: &txtResolverStub{records: map[string][]string{"_shortlink-verify.go.example.com": {"v=spf1 -all", "shortlink-verify=token"}}},
+		},
+		{
+			name:     "Wrong token",
+			resolver: &txtResolverStub{records: map[string][]string{"_shortlink-verify.go.example.com": {"shortlink-verify=other"}}},
+			expected: ErrDomainRecordNotFound,
+		},
+		{
+			name:     "No record",
+			resolver: &txtResolverStub{},
+			expected: ErrDomainRecordNotFound,
+		},
+		{
+			name:     "Lookup failure",
+			resolver: &txtResolverStub{err: lookupErr},
+			expected: lookupErr,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewDomainVerifier(tt.resolver, 0).Verify(domain)
+			assert.Equal(t, tt.expected, err)
+		})
+	}
+}
+
+func TestRequestHostname(t *testing.T) {
+	assert.Equal(t, "go.example.com", requestHostname("Go.Example.com:8080"))
+	assert.Equal(t, "go.example.com", requestHostname("go.example.com."))
+	assert.Equal(t, "::1", requestHostname("[::1]:80"))
+}
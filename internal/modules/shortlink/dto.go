@@ -1,24 +1,520 @@
 package shortlink
 
+import "time"
+
 type (
 	CreateShortenerLinkRequestDTO struct {
-		OriginalURL  string `json:"original_url" binding:"url,required"`
-		ShortenerURL string `json:"shortener_url" `
+		OriginalURL  string     `json:"original_url" binding:"url,required"`
+		ShortenerURL string     `json:"shortener_url" binding:"omitempty,alias_format,alias_reserved,alias_blocked"`
+		ActiveFrom   *time.Time `json:"active_from"`
+		ExpiresAt    *time.Time `json:"expires_at"`
+		MaxClicks    *int       `json:"max_clicks" binding:"omitempty,min=1"`
+		Password     *string    `json:"password" binding:"omitempty,min=4,max=72"`
+		// RedirectStatus defaults to 302 so browsers keep asking the server
+		// and every visit is counted.
+		RedirectStatus *int    `json:"redirect_status" binding:"omitempty,oneof=301 302 307 308"`
+		ForwardQuery   bool    `json:"forward_query"`
+		StatsPublic    bool    `json:"stats_public"`
+		CampaignID     *string `json:"campaign_id" binding:"omitempty,uuid"`
+		FolderID       *string `json:"folder_id" binding:"omitempty,uuid"`
+		// DomainID serves the link from one of the user's verified branded
+		// domains instead of BASE_URL.
+		DomainID *string `json:"domain_id" binding:"omitempty,uuid"`
+		// Tags are given by name; missing tags are created.
+		Tags               []string     `json:"tags" binding:"omitempty,max=20,dive,required,max=50"`
+		PreviewTitle       string       `json:"preview_title" binding:"max=200"`
+		PreviewDescription string       `json:"preview_description" binding:"max=500"`
+		PreviewImageURL    string       `json:"preview_image_url" binding:"omitempty,url,max=2048"`
+		DeepLink           *DeepLinkDTO `json:"deep_link"`
 	}
 
 	CreateShortenerLinkResponseDTO struct {
-		OriginalURL  string `json:"original_url"`
-		ShortenerURL string `json:"shortener_url"`
+		OriginalURL        string       `json:"original_url"`
+		ShortenerURL       string       `json:"shortener_url"`
+		ActiveFrom         *string      `json:"active_from"`
+		ExpiresAt          *string      `json:"expires_at"`
+		MaxClicks          *int         `json:"max_clicks"`
+		IsProtected        bool         `json:"is_protected"`
+		RedirectStatus     int          `json:"redirect_status"`
+		ForwardQuery       bool         `json:"forward_query"`
+		StatsPublic        bool         `json:"stats_public"`
+		CampaignID         *string      `json:"campaign_id"`
+		FolderID           *string      `json:"folder_id"`
+		DomainID           *string      `json:"domain_id"`
+		ShortURL           string       `json:"short_url"`
+		Tags               []string     `json:"tags"`
+		PreviewTitle       string       `json:"preview_title"`
+		PreviewDescription string       `json:"preview_description"`
+		PreviewImageURL    string       `json:"preview_image_url"`
+		DeepLink           *DeepLinkDTO `json:"deep_link"`
+	}
+
+	UpdateShortenerLinkRequestDTO struct {
+		OriginalURL  *string    `json:"original_url" binding:"omitempty,url"`
+		ShortenerURL *string    `json:"shortener_url" binding:"omitempty,alias_format,alias_reserved,alias_blocked"`
+		ActiveFrom   *time.Time `json:"active_from"`
+		ExpiresAt    *time.Time `json:"expires_at"`
+		MaxClicks    *int       `json:"max_clicks" binding:"omitempty,min=1"`
+		// The clear fields remove the matching schedule limit.
+		ClearActiveFrom bool `json:"clear_active_from"`
+		ClearExpiresAt  bool `json:"clear_expires_at"`
+		ClearMaxClicks  bool `json:"clear_max_clicks"`
+		// Password replaces the link password; an empty string removes it.
+		Password       *string `json:"password" binding:"omitempty,min=4,max=72"`
+		RedirectStatus *int    `json:"redirect_status" binding:"omitempty,oneof=301 302 307 308"`
+		ForwardQuery   *bool   `json:"forward_query"`
+		StatsPublic    *bool   `json:"stats_public"`
+		// CampaignID moves the link to another campaign; an empty string
+		// removes it from its campaign.
+		CampaignID *string `json:"campaign_id" binding:"omitempty,uuid|len=0"`
+		// FolderID moves the link to another folder; an empty string takes
+		// it out of its folder.
+		FolderID *string `json:"folder_id" binding:"omitempty,uuid|len=0"`
+		// Tags replaces every tag of the link; an empty list removes them.
+		Tags *[]string `json:"tags" binding:"omitempty,max=20,dive,required,max=50"`
+		// The preview fields are cleared by an empty string.
+		PreviewTitle       *string `json:"preview_title" binding:"omitempty,max=200"`
+		PreviewDescription *string `json:"preview_description" binding:"omitempty,max=500"`
+		PreviewImageURL    *string `json:"preview_image_url" binding:"omitempty,url|len=0,max=2048"`
+		// DeepLink replaces the app configuration; an empty object removes it.
+		DeepLink *DeepLinkDTO `json:"deep_link"`
+	}
+
+	// DeepLinkDTO opens the app of iOS and Android visitors. App URLs are
+	// custom scheme or universal links; visitors without the app are sent to
+	// the store URL of their platform, or to the destination when it is empty.
+	DeepLinkDTO struct {
+		IOSAppURL       string `json:"ios_app_url" binding:"max=2048"`
+		IOSStoreURL     string `json:"ios_store_url" binding:"omitempty,url,max=2048"`
+		AndroidAppURL   string `json:"android_app_url" binding:"max=2048"`
+		AndroidStoreURL string `json:"android_store_url" binding:"omitempty,url,max=2048"`
+	}
+
+	ShortenerLinkRuleDTO struct {
+		Type           string   `json:"type" binding:"required,oneof=platform language country"`
+		Values         []string `json:"values" binding:"required,min=1,max=50,dive,required,max=35"`
+		DestinationURL string   `json:"destination_url" binding:"required,url"`
+	}
+
+	// ReplaceShortenerLinkRulesRequestDTO replaces every rule of a link. Rules
+	// are evaluated in the order given; an empty list removes targeting.
+	ReplaceShortenerLinkRulesRequestDTO struct {
+		Rules []ShortenerLinkRuleDTO `json:"rules" binding:"max=50,dive"`
+	}
+
+	GetShortenerLinkRulesResponseDTO struct {
+		Rules []ShortenerLinkRuleDTO `json:"rules"`
+	}
+
+	// ShortenerLinkVariantDTO is a split test destination. ID is omitted for
+	// new variants and must be sent back to keep an existing one.
+	ShortenerLinkVariantDTO struct {
+		ID             *string `json:"id" binding:"omitempty,uuid"`
+		Name           string  `json:"name" binding:"required,max=50"`
+		DestinationURL string  `json:"destination_url" binding:"required,url"`
+		Weight         int     `json:"weight" binding:"required,min=1,max=1000"`
+	}
+
+	// ReplaceShortenerLinkVariantsRequestDTO sets the split test of a link.
+	// An empty list turns the split test off.
+	ReplaceShortenerLinkVariantsRequestDTO struct {
+		Variants []ShortenerLinkVariantDTO `json:"variants" binding:"max=10,dive"`
+	}
+
+	GetShortenerLinkVariantsResponseDTO struct {
+		Variants []ShortenerLinkVariantDTO `json:"variants"`
+	}
+
+	BulkCreateShortenerLinkRowDTO struct {
+		OriginalURL  string     `json:"original_url" binding:"url,required"`
+		ShortenerURL string     `json:"shortener_url" binding:"omitempty,alias_format,alias_reserved,alias_blocked"`
+		ExpiresAt    *time.Time `json:"expires_at"`
+	}
+
+	BulkCreateShortenerLinkResultDTO struct {
+		Row          int     `json:"row"`
+		OriginalURL  string  `json:"original_url"`
+		ShortenerURL string  `json:"shortener_url,omitempty"`
+		Error        *string `json:"error"`
+	}
+
+	BulkCreateShortenerLinkResponseDTO struct {
+		Created int                                `json:"created"`
+		Failed  int                                `json:"failed"`
+		Results []BulkCreateShortenerLinkResultDTO `json:"results"`
+	}
+
+	ExportShortenerLinkRequestDTO struct {
+		Format string `form:"format" binding:"omitempty,oneof=csv json"`
+	}
+
+	UnlockShortenerLinkRequestDTO struct {
+		Password string `form:"password" binding:"required"`
 	}
 
 	GetShortenerLink struct {
-		ID           string `json:"id"`
-		OriginalURL  string `json:"original_url"`
-		ShortenerURL string `json:"shortener_url"`
-		CreatedAt    string `json:"created_at"`
+		ID                 string         `json:"id"`
+		OriginalURL        string         `json:"original_url"`
+		ShortenerURL       string         `json:"shortener_url"`
+		ActiveFrom         *string        `json:"active_from"`
+		ExpiresAt          *string        `json:"expires_at"`
+		MaxClicks          *int           `json:"max_clicks"`
+		ClickCount         int64          `json:"click_count"`
+		IsExpired          bool           `json:"is_expired"`
+		IsProtected        bool           `json:"is_protected"`
+		RedirectStatus     int            `json:"redirect_status"`
+		ForwardQuery       bool           `json:"forward_query"`
+		StatsPublic        bool           `json:"stats_public"`
+		CampaignID         *string        `json:"campaign_id"`
+		FolderID           *string        `json:"folder_id"`
+		DomainID           *string        `json:"domain_id"`
+		Tags               []string       `json:"tags,omitempty"`
+		PreviewTitle       string         `json:"preview_title"`
+		PreviewDescription string         `json:"preview_description"`
+		PreviewImageURL    string         `json:"preview_image_url"`
+		DeepLink           *DeepLinkDTO   `json:"deep_link"`
+		IsBroken           bool           `json:"is_broken"`
+		Health             *LinkHealthDTO `json:"health"`
+		CreatedAt          string         `json:"created_at"`
+		// DeletedAt is only set in the list of deleted links.
+		DeletedAt *string `json:"deleted_at,omitempty"`
+	}
+
+	LinkHealthDTO struct {
+		StatusCode *int   `json:"status_code"`
+		LatencyMs  *int   `json:"latency_ms"`
+		Error      string `json:"error,omitempty"`
+		CheckedAt  string `json:"checked_at"`
 	}
 
 	GetAllShortenerLinksResponseDTO struct {
 		ShortenerLink []GetShortenerLink `json:"shortener_links"`
 	}
+
+	// LinkSnapshotDTO is a link's settings as recorded in one revision.
+	LinkSnapshotDTO struct {
+		OriginalURL        string       `json:"original_url"`
+		ShortenerURL       string       `json:"shortener_url"`
+		ActiveFrom         *string      `json:"active_from"`
+		ExpiresAt          *string      `json:"expires_at"`
+		MaxClicks          *int         `json:"max_clicks"`
+		IsProtected        bool         `json:"is_protected"`
+		RedirectStatus     int          `json:"redirect_status"`
+		ForwardQuery       bool         `json:"forward_query"`
+		StatsPublic        bool         `json:"stats_public"`
+		CampaignID         *string      `json:"campaign_id"`
+		FolderID           *string      `json:"folder_id"`
+		Tags               []string     `json:"tags"`
+		PreviewTitle       string       `json:"preview_title"`
+		PreviewDescription string       `json:"preview_description"`
+		PreviewImageURL    string       `json:"preview_image_url"`
+		DeepLink           *DeepLinkDTO `json:"deep_link"`
+		// Rules and Variants are null in revisions recorded before they
+		// were tracked.
+		Rules    []ShortenerLinkRuleDTO    `json:"rules"`
+		Variants []ShortenerLinkVariantDTO `json:"variants"`
+	}
+
+	FieldChangeDTO struct {
+		Field string `json:"field"`
+		Old   any    `json:"old"`
+		New   any    `json:"new"`
+	}
+
+	// ShortenerLinkRevisionDTO is one entry of a link's history. Changes
+	// compares the snapshot with the previous version.
+	ShortenerLinkRevisionDTO struct {
+		Version      int              `json:"version"`
+		Action       string           `json:"action"`
+		UserID       *string          `json:"user_id"`
+		RestoredFrom *int             `json:"restored_from"`
+		Snapshot     LinkSnapshotDTO  `json:"snapshot"`
+		Changes      []FieldChangeDTO `json:"changes"`
+		CreatedAt    string           `json:"created_at"`
+	}
+
+	GetShortenerLinkHistoryResponseDTO struct {
+		Revisions []ShortenerLinkRevisionDTO `json:"revisions"`
+	}
+
+	GetShortenerLinkStatsRequestDTO struct {
+		Interval string    `form:"interval" binding:"omitempty,oneof=hour day week"`
+		From     time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+		To       time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+	}
+
+	GetShortenerLinkStatsResponseDTO struct {
+		TotalClicks    int64                 `json:"total_clicks"`
+		UniqueVisitors int64                 `json:"unique_visitors"`
+		Interval       string                `json:"interval"`
+		From           string                `json:"from"`
+		To             string                `json:"to"`
+		Series         []ClickSeriesPointDTO `json:"series"`
+		TopReferrers   []ReferrerCountDTO    `json:"top_referrers"`
+		Variants       []VariantStatsDTO     `json:"variants"`
+	}
+
+	VariantStatsDTO struct {
+		VariantID      string  `json:"variant_id"`
+		Name           string  `json:"name"`
+		DestinationURL string  `json:"destination_url"`
+		Weight         int     `json:"weight"`
+		Archived       bool    `json:"archived"`
+		Clicks         int64   `json:"clicks"`
+		UniqueVisitors int64   `json:"unique_visitors"`
+		ClickShare     float64 `json:"click_share"`
+	}
+
+	ClickSeriesPointDTO struct {
+		Bucket string `json:"bucket"`
+		Clicks int64  `json:"clicks"`
+	}
+
+	// GetPublicShortenerLinkStatsResponseDTO holds only aggregates; no
+	// per-visit data is ever shown on public stats pages.
+	GetPublicShortenerLinkStatsResponseDTO struct {
+		ShortURL       string           `json:"short_url"`
+		TotalClicks    int64            `json:"total_clicks"`
+		UniqueVisitors int64            `json:"unique_visitors"`
+		From           string           `json:"from"`
+		To             string           `json:"to"`
+		Daily          []DailyClicksDTO `json:"daily"`
+		GeneratedAt    string           `json:"generated_at"`
+	}
+
+	DailyClicksDTO struct {
+		Date   string `json:"date"`
+		Clicks int64  `json:"clicks"`
+	}
+
+	ReferrerCountDTO struct {
+		Referrer string `json:"referrer"`
+		Clicks   int64  `json:"clicks"`
+	}
+
+	GetShortenerLinkQRCodeRequestDTO struct {
+		Format     string `form:"format" binding:"omitempty,oneof=png svg"`
+		Size       int    `form:"size" binding:"omitempty,min=64,max=2048"`
+		Level      string `form:"level" binding:"omitempty,oneof=L M Q H"`
+		Margin     *int   `form:"margin" binding:"omitempty,min=0,max=16"`
+		Foreground string `form:"fg" binding:"omitempty,max=9"`
+		Background string `form:"bg" binding:"omitempty,max=9"`
+		Logo       bool   `form:"logo"`
+		// Domain is the branded domain of a link looked up by short code.
+		Domain string `form:"domain" binding:"omitempty,max=253"`
+		// IfNoneMatch is set from the request header, not the query.
+		IfNoneMatch string `form:"-"`
+	}
+
+	CreateCampaignRequestDTO struct {
+		Name        string `json:"name" binding:"required,max=100"`
+		UTMSource   string `json:"utm_source" binding:"max=100"`
+		UTMMedium   string `json:"utm_medium" binding:"max=100"`
+		UTMCampaign string `json:"utm_campaign" binding:"max=100"`
+		UTMTerm     string `json:"utm_term" binding:"max=100"`
+		UTMContent  string `json:"utm_content" binding:"max=100"`
+	}
+
+	// UpdateCampaignRequestDTO changes the fields that are sent; an empty
+	// UTM value removes it.
+	UpdateCampaignRequestDTO struct {
+		Name        *string `json:"name" binding:"omitempty,min=1,max=100"`
+		UTMSource   *string `json:"utm_source" binding:"omitempty,max=100"`
+		UTMMedium   *string `json:"utm_medium" binding:"omitempty,max=100"`
+		UTMCampaign *string `json:"utm_campaign" binding:"omitempty,max=100"`
+		UTMTerm     *string `json:"utm_term" binding:"omitempty,max=100"`
+		UTMContent  *string `json:"utm_content" binding:"omitempty,max=100"`
+	}
+
+	GetCampaign struct {
+		ID          string `json:"id"`
+		Name        string `json:"name"`
+		UTMSource   string `json:"utm_source"`
+		UTMMedium   string `json:"utm_medium"`
+		UTMCampaign string `json:"utm_campaign"`
+		UTMTerm     string `json:"utm_term"`
+		UTMContent  string `json:"utm_content"`
+		LinkCount   int64  `json:"link_count"`
+		CreatedAt   string `json:"created_at"`
+	}
+
+	GetAllCampaignsResponseDTO struct {
+		Campaigns []GetCampaign `json:"campaigns"`
+	}
+
+	GetCampaignStatsResponseDTO struct {
+		TotalClicks    int64                  `json:"total_clicks"`
+		UniqueVisitors int64                  `json:"unique_visitors"`
+		Interval       string                 `json:"interval"`
+		From           string                 `json:"from"`
+		To             string                 `json:"to"`
+		Series         []ClickSeriesPointDTO  `json:"series"`
+		Links          []CampaignLinkStatsDTO `json:"links"`
+	}
+
+	CampaignLinkStatsDTO struct {
+		ID             string `json:"id"`
+		ShortenerURL   string `json:"shortener_url"`
+		OriginalURL    string `json:"original_url"`
+		Clicks         int64  `json:"clicks"`
+		UniqueVisitors int64  `json:"unique_visitors"`
+	}
+
+	CreateTagRequestDTO struct {
+		Name string `json:"name" binding:"required,max=50"`
+	}
+
+	UpdateTagRequestDTO struct {
+		Name string `json:"name" binding:"required,max=50"`
+	}
+
+	GetTag struct {
+		ID        string `json:"id"`
+		Name      string `json:"name"`
+		LinkCount int64  `json:"link_count"`
+		CreatedAt string `json:"created_at"`
+	}
+
+	GetAllTagsResponseDTO struct {
+		Tags []GetTag `json:"tags"`
+	}
+
+	CreateFolderRequestDTO struct {
+		Name string `json:"name" binding:"required,max=50"`
+	}
+
+	UpdateFolderRequestDTO struct {
+		Name string `json:"name" binding:"required,max=50"`
+	}
+
+	GetFolder struct {
+		ID        string `json:"id"`
+		Name      string `json:"name"`
+		LinkCount int64  `json:"link_count"`
+		CreatedAt string `json:"created_at"`
+	}
+
+	GetAllFoldersResponseDTO struct {
+		Folders []GetFolder `json:"folders"`
+	}
+
+	CreateDomainRequestDTO struct {
+		Hostname string `json:"hostname" binding:"required,fqdn,max=253"`
+	}
+
+	// DomainVerificationRecordDTO is the DNS record the owner must publish
+	// before the domain can be used.
+	DomainVerificationRecordDTO struct {
+		Type  string `json:"type"`
+		Name  string `json:"name"`
+		Value string `json:"value"`
+	}
+
+	GetDomain struct {
+		ID                 string                      `json:"id"`
+		Hostname           string                      `json:"hostname"`
+		IsVerified         bool                        `json:"is_verified"`
+		VerifiedAt         *string                     `json:"verified_at"`
+		VerificationRecord DomainVerificationRecordDTO `json:"verification_record"`
+		LinkCount          int64                       `json:"link_count"`
+		CreatedAt          string                      `json:"created_at"`
+	}
+
+	GetAllDomainsResponseDTO struct {
+		Domains []GetDomain `json:"domains"`
+	}
+
+	CreateBioPageRequestDTO struct {
+		Slug        string `json:"slug" binding:"required,alias_format,alias_blocked"`
+		Title       string `json:"title" binding:"required,max=100"`
+		Description string `json:"description" binding:"max=500"`
+		AvatarURL   string `json:"avatar_url" binding:"omitempty,url,max=2048"`
+		Theme       string `json:"theme" binding:"omitempty,oneof=light dark"`
+	}
+
+	UpdateBioPageRequestDTO struct {
+		Slug        *string `json:"slug" binding:"omitempty,alias_format,alias_blocked"`
+		Title       *string `json:"title" binding:"omitempty,max=100"`
+		Description *string `json:"description" binding:"omitempty,max=500"`
+		// AvatarURL is removed by an empty string.
+		AvatarURL *string `json:"avatar_url" binding:"omitempty,url|len=0,max=2048"`
+		Theme     *string `json:"theme" binding:"omitempty,oneof=light dark"`
+	}
+
+	// BioPageItemDTO places a link on a bio page. ID is omitted for new items
+	// and must be sent back to keep an existing one with its click count.
+	BioPageItemDTO struct {
+		ID              *string `json:"id" binding:"omitempty,uuid"`
+		ShortenerLinkID string  `json:"shortener_link_id" binding:"required,uuid"`
+		Label           string  `json:"label" binding:"max=100"`
+	}
+
+	// ReplaceBioPageItemsRequestDTO replaces every item of a bio page. Items
+	// are shown in the order given.
+	ReplaceBioPageItemsRequestDTO struct {
+		Items []BioPageItemDTO `json:"items" binding:"max=50,dive"`
+	}
+
+	GetBioPageItem struct {
+		ID              string `json:"id"`
+		ShortenerLinkID string `json:"shortener_link_id"`
+		ShortURL        string `json:"short_url"`
+		Label           string `json:"label"`
+		ClickCount      int64  `json:"click_count"`
+	}
+
+	GetBioPage struct {
+		ID          string           `json:"id"`
+		Slug        string           `json:"slug"`
+		URL         string           `json:"url"`
+		Title       string           `json:"title"`
+		Description string           `json:"description"`
+		AvatarURL   string           `json:"avatar_url"`
+		Theme       string           `json:"theme"`
+		Items       []GetBioPageItem `json:"items"`
+		CreatedAt   string           `json:"created_at"`
+		UpdatedAt   string           `json:"updated_at"`
+	}
+
+	GetAllBioPagesResponseDTO struct {
+		BioPages []GetBioPage `json:"bio_pages"`
+	}
+
+	// CreateAppSchemeRequestDTO registers a custom scheme, such as myapp,
+	// for the app URLs of deep links.
+	CreateAppSchemeRequestDTO struct {
+		Scheme string `json:"scheme" binding:"required,max=32"`
+	}
+
+	GetAppScheme struct {
+		ID        string `json:"id"`
+		Scheme    string `json:"scheme"`
+		CreatedAt string `json:"created_at"`
+	}
+
+	GetAllAppSchemesResponseDTO struct {
+		AppSchemes []GetAppScheme `json:"app_schemes"`
+	}
+
+	GetCacheStatsResponseDTO struct {
+		Hits     uint64  `json:"hits"`
+		Misses   uint64  `json:"misses"`
+		HitRatio float64 `json:"hit_ratio"`
+	}
+
+	CreateBlockedDomainRequestDTO struct {
+		Domain string `json:"domain" binding:"required,fqdn"`
+		Reason string `json:"reason" binding:"max=255"`
+	}
+
+	GetBlockedDomain struct {
+		ID        string `json:"id"`
+		Domain    string `json:"domain"`
+		Reason    string `json:"reason"`
+		CreatedAt string `json:"created_at"`
+	}
+
+	GetAllBlockedDomainsResponseDTO struct {
+		BlockedDomains []GetBlockedDomain `json:"blocked_domains"`
+	}
 )
@@ -0,0 +1,44 @@
+package shortlink
+
+import "github.com/google/uuid"
+
+// EventEmitter receives link events on behalf of the link owner. Events are
+// emitted after the change is saved and Emit must not block, since clicks are
+// emitted from the redirect path.
+type EventEmitter interface {
+	Emit(userID uuid.UUID, event string, data any)
+}
+
+// ClickEvent is the payload of EventLinkClicked. The visitor IP is left out.
+type ClickEvent struct {
+	ShortenerLinkID string  `json:"shortener_link_id"`
+	ShortenerURL    string  `json:"shortener_url"`
+	ShortURL        string  `json:"short_url"`
+	VariantID       *string `json:"variant_id"`
+	Referrer        string  `json:"referrer"`
+	Device          string  `json:"device"`
+	Browser         string  `json:"browser"`
+	OS              string  `json:"os"`
+	ClickedAt       string  `json:"clicked_at"`
+}
+
+func newClickEvent(shortenerLink *ShortenerLinkModel, click *ShortenerLinkClickModel) ClickEvent {
+	return ClickEvent{
+		ShortenerLinkID: shortenerLink.ID.String(),
+		ShortenerURL:    shortenerLink.ShortenerURL,
+		ShortURL:        shortURL(shortenerLink),
+		VariantID:       formatUUID(click.VariantID),
+		Referrer:        click.Referrer,
+		Device:          click.Device,
+		Browser:         click.Browser,
+		OS:              click.OS,
+		ClickedAt:       click.ClickedAt.Format("2006-01-02 15:04:05"),
+	}
+}
+
+// emit passes an event to the emitter, if one is configured.
+func (uc *useCase) emit(userID uuid.UUID, event string, data any) {
+	if uc.emitter != nil {
+		uc.emitter.Emit(userID, event, data)
+	}
+}
@@ -1,11 +1,25 @@
 package shortlink
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
 	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/google/uuid"
+	"github.com/xcurvnubaim/njajal-gin-golang/internal/configs"
+	"github.com/xcurvnubaim/njajal-gin-golang/internal/middleware"
 	"github.com/xcurvnubaim/njajal-gin-golang/internal/pkg/app"
 	"github.com/xcurvnubaim/njajal-gin-golang/internal/pkg/query"
+	"github.com/xcurvnubaim/njajal-gin-golang/internal/pkg/useragent"
 	CustomValidator "github.com/xcurvnubaim/njajal-gin-golang/internal/pkg/validator"
 )
 
@@ -24,29 +38,94 @@ func NewHandler(app *gin.Engine, useCase IUseCase, prefixApi string) {
 }
 
 func (h *Handler) Routes(prefix string) {
+	// Short links are served from the root so they resolve as BASE_URL + code.
+	h.app.GET("/:shortenerURL", h.GetOriginalURL)
+	h.app.HEAD("/:shortenerURL", h.GetOriginalURL)
+	h.app.POST("/:shortenerURL/unlock", middleware.RateLimitByIP(10, 15*time.Minute), h.UnlockShortenerLink)
+	h.app.GET("/:shortenerURL/stats", h.GetPublicShortenerLinkStats)
+	h.app.GET("/@:slug", h.GetPublicBioPage)
+	h.app.GET("/.well-known/apple-app-site-association", h.GetAppleAppSiteAssociation)
+	h.app.GET("/.well-known/assetlinks.json", h.GetAssetLinks)
+	h.app.GET("/@:slug/:item", h.TrackBioPageItemClick)
+	// Short URLs handed out before links moved to the root. The wildcard
+	// shares its name with the /:id routes of the group below.
+	h.app.GET(prefix+"/:id", h.RedirectLegacyShortURL)
+	h.app.HEAD(prefix+"/:id", h.RedirectLegacyShortURL)
+
 	routes := h.app.Group(prefix)
 	{
-		routes.POST("/", h.CreateShortenerLink)
-		routes.GET("/:shortenerURL", h.GetOriginalURL)
-		routes.GET("/", h.GetAllShortenerLink)
-		// authentication.POST("/register", h.Register)
-		// authentication.POST("/login", h.Login)
-
-		// authentication.Use(middleware.AuthenticateJWT())
-		// {
-		// 	authentication.GET("/me", h.GetMe)
-		// }
+		routes.Use(middleware.AuthenticateJWT())
+		{
+			routes.POST("/", h.CreateShortenerLink)
+			routes.POST("/bulk", h.BulkCreateShortenerLink)
+			routes.GET("/", h.GetAllShortenerLink)
+			routes.GET("/export", h.ExportShortenerLink)
+			routes.GET("/broken", h.GetBrokenShortenerLink)
+			routes.GET("/deleted", h.GetDeletedShortenerLinks)
+			routes.GET("/cache/stats", middleware.VerifyAdmin(), h.GetCacheStats)
+			routes.GET("/blocked-domains", middleware.VerifyAdmin(), h.GetAllBlockedDomains)
+			routes.POST("/blocked-domains", middleware.VerifyAdmin(), h.CreateBlockedDomain)
+			routes.DELETE("/blocked-domains/:id", middleware.VerifyAdmin(), h.DeleteBlockedDomain)
+			routes.POST("/campaigns", h.CreateCampaign)
+			routes.GET("/campaigns", h.GetAllCampaigns)
+			routes.GET("/campaigns/:id", h.GetCampaign)
+			routes.PATCH("/campaigns/:id", h.UpdateCampaign)
+			routes.DELETE("/campaigns/:id", h.DeleteCampaign)
+			routes.GET("/campaigns/:id/stats", h.GetCampaignStats)
+			routes.POST("/tags", h.CreateTag)
+			routes.GET("/tags", h.GetAllTags)
+			routes.PATCH("/tags/:id", h.UpdateTag)
+			routes.DELETE("/tags/:id", h.DeleteTag)
+			routes.POST("/folders", h.CreateFolder)
+			routes.GET("/folders", h.GetAllFolders)
+			routes.PATCH("/folders/:id", h.UpdateFolder)
+			routes.DELETE("/folders/:id", h.DeleteFolder)
+			routes.POST("/domains", h.CreateDomain)
+			routes.GET("/domains", h.GetAllDomains)
+			routes.GET("/domains/:id", h.GetDomain)
+			routes.POST("/domains/:id/verify", h.VerifyDomain)
+			routes.DELETE("/domains/:id", h.DeleteDomain)
+			routes.POST("/bio-pages", h.CreateBioPage)
+			routes.GET("/bio-pages", h.GetAllBioPages)
+			routes.GET("/bio-pages/:id", h.GetBioPage)
+			routes.PATCH("/bio-pages/:id", h.UpdateBioPage)
+			routes.DELETE("/bio-pages/:id", h.DeleteBioPage)
+			routes.PUT("/bio-pages/:id/items", h.ReplaceBioPageItems)
+			routes.POST("/app-schemes", h.CreateAppScheme)
+			routes.GET("/app-schemes", h.GetAllAppSchemes)
+			routes.DELETE("/app-schemes/:id", h.DeleteAppScheme)
+			routes.PATCH("/:id", h.UpdateShortenerLink)
+			routes.DELETE("/:id", h.DeleteShortenerLink)
+			routes.POST("/:id/undelete", h.UndeleteShortenerLink)
+			routes.GET("/:id/history", h.GetShortenerLinkHistory)
+			routes.POST("/:id/restore/:version", h.RestoreShortenerLinkVersion)
+			routes.GET("/:id/stats", h.GetShortenerLinkStats)
+			// Takes an ID or a short code; gin needs one wildcard name
+			// per path segment.
+			routes.GET("/:id/qr", h.GetShortenerLinkQRCode)
+			routes.GET("/:id/rules", h.GetShortenerLinkRules)
+			routes.PUT("/:id/rules", h.ReplaceShortenerLinkRules)
+			routes.GET("/:id/variants", h.GetShortenerLinkVariants)
+			routes.PUT("/:id/variants", h.ReplaceShortenerLinkVariants)
+		}
 	}
 }
 
 func (h *Handler) CreateShortenerLink(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(401, app.NewErrorResponse("Invalid user ID", nil))
+		return
+	}
+
 	var data CreateShortenerLinkRequestDTO
 	if err := c.ShouldBindJSON(&data); err != nil {
-		c.JSON(400, gin.H{"error": err.Error()})
+		var errMessages = CustomValidator.FormatValidationErrors(err)
+		c.JSON(400, app.NewErrorResponse("Validation Error", &errMessages))
 		return
 	}
 
-	res, err := h.useCase.CreateShortenerLink(&data)
+	res, err := h.useCase.CreateShortenerLink(userID, &data)
 	if err != nil {
 		errMsg := err.Error()
 		c.JSON(err.Code(), app.NewErrorResponse("Failed to create shortener link", &errMsg))
@@ -56,40 +135,1582 @@ func (h *Handler) CreateShortenerLink(c *gin.Context) {
 	c.JSON(200, app.NewSuccessResponse("Shortener link created successfully", res))
 }
 
+// BulkCreateShortenerLink accepts a JSON array of rows, a CSV request body or
+// a CSV uploaded as the multipart field "file". Invalid rows are reported in
+// the results and do not prevent the remaining rows from being created.
+func (h *Handler) BulkCreateShortenerLink(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(401, app.NewErrorResponse("Invalid user ID", nil))
+		return
+	}
+
+	rows, rowErrors, err := bindBulkRows(c)
+	if err != nil {
+		errMsg := err.Error()
+		c.JSON(400, app.NewErrorResponse("Validation Error", &errMsg))
+		return
+	}
+
+	results := make([]BulkCreateShortenerLinkResultDTO, len(rows))
+	validRows := make([]BulkCreateShortenerLinkRowDTO, 0, len(rows))
+	validIndexes := make([]int, 0, len(rows))
+	for i := range rows {
+		results[i] = BulkCreateShortenerLinkResultDTO{Row: i + 1, OriginalURL: rows[i].OriginalURL}
+		if errMsg, invalid := rowErrors[i]; invalid {
+			results[i].Error = &errMsg
+			continue
+		}
+		if err := binding.Validator.ValidateStruct(&rows[i]); err != nil {
+			errMsg := CustomValidator.FormatValidationErrors(err)
+			results[i].Error = &errMsg
+			continue
+		}
+		validRows = append(validRows, rows[i])
+		validIndexes = append(validIndexes, i)
+	}
+
+	if len(validRows) > 0 {
+		created, errApi := h.useCase.BulkCreateShortenerLink(userID, validRows)
+		if errApi != nil {
+			errMsg := errApi.Error()
+			c.JSON(errApi.Code(), app.NewErrorResponse("Failed to create shortener links", &errMsg))
+			return
+		}
+		for i, result := range created {
+			result.Row = validIndexes[i] + 1
+			results[validIndexes[i]] = result
+		}
+	}
+
+	res := BulkCreateShortenerLinkResponseDTO{Results: results}
+	for _, result := range results {
+		if result.Error != nil {
+			res.Failed++
+		} else {
+			res.Created++
+		}
+	}
+
+	c.JSON(200, app.NewSuccessResponse("Bulk shortener link request processed", &res))
+}
+
+func bindBulkRows(c *gin.Context) ([]BulkCreateShortenerLinkRowDTO, map[int]string, error) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, MaxBulkUploadSize)
+
+	var rows []BulkCreateShortenerLinkRowDTO
+	var rowErrors map[int]string
+	switch c.ContentType() {
+	case "multipart/form-data":
+		fileHeader, err := c.FormFile("file")
+		if err != nil {
+			return nil, nil, errors.New("file: This field is required")
+		}
+		file, err := fileHeader.Open()
+		if err != nil {
+			return nil, nil, err
+		}
+		defer file.Close()
+
+		if rows, rowErrors, err = parseBulkCSV(file); err != nil {
+			return nil, nil, err
+		}
+	case "text/csv":
+		var err error
+		if rows, rowErrors, err = parseBulkCSV(c.Request.Body); err != nil {
+			return nil, nil, err
+		}
+	default:
+		// Decoded without binding so one invalid row does not reject the batch.
+		if err := json.NewDecoder(c.Request.Body).Decode(&rows); err != nil {
+			return nil, nil, errors.New("Request body must be a JSON array of links")
+		}
+	}
+
+	if len(rows) == 0 {
+		return nil, nil, errors.New("At least one link is required")
+	}
+	if len(rows) > MaxBulkRows {
+		return nil, nil, ErrBulkTooManyRows
+	}
+
+	return rows, rowErrors, nil
+}
+
+// ExportShortenerLink streams the caller's links as CSV or JSON, honouring the
+// same search, filter and order parameters as the list endpoint.
+func (h *Handler) ExportShortenerLink(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(401, app.NewErrorResponse("Invalid user ID", nil))
+		return
+	}
+
+	var data ExportShortenerLinkRequestDTO
+	if err := c.ShouldBindQuery(&data); err != nil {
+		var errMessages = CustomValidator.FormatValidationErrors(err)
+		c.JSON(400, app.NewErrorResponse("Validation Error", &errMessages))
+		return
+	}
+	if data.Format == "" {
+		data.Format = ExportFormatCSV
+	}
+
+	queryParams, err := parseShortenerLinkQuery(c, "format")
+	if err != nil {
+		errMsg := err.Error()
+		c.JSON(400, app.NewErrorResponse("Validation Error", &errMsg))
+		return
+	}
+	queryParams.Page = 0
+	queryParams.PageSize = 0
+
+	contentType := "text/csv; charset=utf-8"
+	if data.Format == ExportFormatJSON {
+		contentType = "application/json; charset=utf-8"
+	}
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="shortener-links-%s.%s"`, time.Now().Format("20060102"), data.Format))
+	c.Status(200)
+
+	exporter := newLinkExporter(data.Format, c.Writer)
+	if err := exporter.Begin(); err != nil {
+		log.Println(err)
+		return
+	}
+	errApi := h.useCase.ExportShortenerLink(userID, queryParams, func(shortenerLink GetShortenerLink) error {
+		return exporter.Write(shortenerLink)
+	})
+	if errApi != nil {
+		// The status line is already sent; a truncated body is all we can signal.
+		log.Println(errApi.Error())
+		return
+	}
+	if err := exporter.End(); err != nil {
+		log.Println(err)
+	}
+}
+
+// RedirectLegacyShortURL sends /api/v1/shortener-link/:code to /:code, so the
+// visit is handled and counted by GetOriginalURL.
+func (h *Handler) RedirectLegacyShortURL(c *gin.Context) {
+	location := "/" + url.PathEscape(c.Param("id"))
+	if c.Request.URL.RawQuery != "" {
+		location += "?" + c.Request.URL.RawQuery
+	}
+	c.Redirect(http.StatusMovedPermanently, location)
+}
+
 func (h *Handler) GetOriginalURL(c *gin.Context) {
 	shortenerURL := c.Param("shortenerURL")
 	log.Println(shortenerURL)
-	res, err := h.useCase.GetOriginalURL(shortenerURL)
+	if code, ok := strings.CutSuffix(shortenerURL, PreviewSuffix); ok {
+		h.servePreview(c, code, true)
+		return
+	}
+
+	unlockToken, _ := c.Cookie(UnlockCookiePrefix + shortenerURL)
+	// Unfurlers get the preview card when the owner customised it; otherwise
+	// they follow the redirect like a browser.
+	if useragent.IsLinkPreviewer(c.Request.UserAgent()) {
+		if preview, hasMetadata, err := h.useCase.GetShortenerLinkPreview(c.Request.Host, shortenerURL, unlockToken); err == nil && hasMetadata {
+			h.renderPreviewPage(c, preview, false)
+			return
+		}
+	}
+
+	variantID, _ := c.Cookie(VariantCookiePrefix + shortenerURL)
+	res, err := h.useCase.GetOriginalURL(c.Request.Host, shortenerURL, &Visit{
+		Referrer:       c.Request.Referer(),
+		UserAgent:      c.Request.UserAgent(),
+		IP:             c.ClientIP(),
+		UnlockToken:    unlockToken,
+		Query:          c.Request.URL.Query(),
+		AcceptLanguage: c.GetHeader("Accept-Language"),
+		VariantID:      variantID,
+		// HEAD is used by link checkers and unfurlers, not by visitors.
+		SkipClick: c.Request.Method == http.MethodHead,
+	})
 	if err != nil {
+		if err.Code() == 401 {
+			h.renderUnlockPage(c, 401, shortenerURL, "")
+			return
+		}
+		if err.Code() == 410 && configs.Config.EXPIRED_LINK_FALLBACK_URL != "" {
+			c.Redirect(302, configs.Config.EXPIRED_LINK_FALLBACK_URL)
+			return
+		}
 		errMsg := err.Error()
 		c.JSON(err.Code(), app.NewErrorResponse("Failed to get original URL", &errMsg))
 		return
 	}
 
-	c.Redirect(301, *res)
+	if res.VariantID != "" && res.VariantID != variantID {
+		c.SetSameSite(http.SameSiteLaxMode)
+		c.SetCookie(
+			VariantCookiePrefix+shortenerURL,
+			res.VariantID,
+			int(VariantCookieTTL.Seconds()),
+			"/"+shortenerURL,
+			"",
+			configs.Config.ENV_MODE == "production",
+			true,
+		)
+	}
+
+	// The use case only returns app URLs it allows for the link owner; the
+	// scheme is checked once more since the page trusts AppURL.
+	if res.AppURL != "" && isSafeAppURL(res.AppURL) {
+		h.renderDeepLinkPage(c, res)
+		return
+	}
+
+	setRedirectCacheHeaders(c, res.MaxAge)
+	c.Redirect(res.Status, res.URL)
+}
+
+// renderDeepLinkPage serves the interstitial that tries to open the app and
+// falls back to res.URL.
+func (h *Handler) renderDeepLinkPage(c *gin.Context, res *Redirect) {
+	setRedirectCacheHeaders(c, 0)
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	c.Status(200)
+	if c.Request.Method == http.MethodHead {
+		return
+	}
+	err := renderDeepLinkPage(c.Writer, deepLinkPageData{
+		AppURL:          template.URL(res.AppURL),
+		FallbackURL:     res.URL,
+		FallbackDelayMs: DeepLinkFallbackDelay.Milliseconds(),
+	})
+	if err != nil {
+		log.Println(err)
+	}
+}
+
+// GetAppleAppSiteAssociation lets the iOS apps in APPLE_APP_IDS open short
+// links as universal links.
+func (h *Handler) GetAppleAppSiteAssociation(c *gin.Context) {
+	appIDs := splitList(configs.Config.APPLE_APP_IDS)
+	if len(appIDs) == 0 {
+		c.JSON(404, app.NewErrorResponse("Apple app site association is not configured", nil))
+		return
+	}
+
+	c.Header("Cache-Control", fmt.Sprintf("public, max-age=%d", int(AppAssociationMaxAge.Seconds())))
+	c.JSON(200, newAppleAppSiteAssociation(appIDs))
+}
+
+// GetAssetLinks lets the Android app in ANDROID_APP_PACKAGE open short links
+// as app links.
+func (h *Handler) GetAssetLinks(c *gin.Context) {
+	fingerprints := splitList(configs.Config.ANDROID_APP_CERT_FINGERPRINTS)
+	if configs.Config.ANDROID_APP_PACKAGE == "" || len(fingerprints) == 0 {
+		c.JSON(404, app.NewErrorResponse("Android asset links are not configured", nil))
+		return
+	}
+
+	c.Header("Cache-Control", fmt.Sprintf("public, max-age=%d", int(AppAssociationMaxAge.Seconds())))
+	c.JSON(200, newAssetLinks(configs.Config.ANDROID_APP_PACKAGE, fingerprints))
+}
+
+// setRedirectCacheHeaders tells browsers and proxies how long a redirect may
+// be reused. Without explicit headers a 301 is cached indefinitely, hiding
+// later edits and clicks.
+func setRedirectCacheHeaders(c *gin.Context, maxAge time.Duration) {
+	if maxAge <= 0 {
+		c.Header("Cache-Control", "private, no-cache, no-store, must-revalidate")
+		c.Header("Expires", time.Unix(0, 0).UTC().Format(http.TimeFormat))
+		return
+	}
+
+	c.Header("Cache-Control", fmt.Sprintf("public, max-age=%d", int(maxAge.Seconds())))
+	c.Header("Expires", time.Now().Add(maxAge).UTC().Format(http.TimeFormat))
+}
+
+// servePreview renders the preview page of a link without recording a click.
+func (h *Handler) servePreview(c *gin.Context, shortenerURL string, interactive bool) {
+	unlockToken, _ := c.Cookie(UnlockCookiePrefix + shortenerURL)
+	preview, _, err := h.useCase.GetShortenerLinkPreview(c.Request.Host, shortenerURL, unlockToken)
+	if err != nil {
+		if err.Code() == 401 {
+			h.renderUnlockPage(c, 401, shortenerURL, "")
+			return
+		}
+		errMsg := err.Error()
+		c.JSON(err.Code(), app.NewErrorResponse("Failed to get shortener link preview", &errMsg))
+		return
+	}
+
+	h.renderPreviewPage(c, preview, interactive)
+}
+
+func (h *Handler) renderPreviewPage(c *gin.Context, preview *Preview, interactive bool) {
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Vary", "User-Agent")
+	c.Status(200)
+	if c.Request.Method == http.MethodHead {
+		return
+	}
+	if err := renderPreviewPage(c.Writer, previewPageData{Preview: *preview, Interactive: interactive}); err != nil {
+		log.Println(err)
+	}
+}
+
+func (h *Handler) UnlockShortenerLink(c *gin.Context) {
+	shortenerURL := c.Param("shortenerURL")
+
+	var data UnlockShortenerLinkRequestDTO
+	if err := c.ShouldBind(&data); err != nil {
+		h.renderUnlockPage(c, 400, shortenerURL, "Password is required")
+		return
+	}
+
+	token, err := h.useCase.UnlockShortenerLink(c.Request.Host, shortenerURL, &data)
+	if err != nil {
+		if err.Code() == 401 {
+			h.renderUnlockPage(c, 401, shortenerURL, err.Error())
+			return
+		}
+		errMsg := err.Error()
+		c.JSON(err.Code(), app.NewErrorResponse("Failed to unlock shortener link", &errMsg))
+		return
+	}
+
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(
+		UnlockCookiePrefix+shortenerURL,
+		token,
+		int(UnlockTokenTTL.Seconds()),
+		"/"+shortenerURL,
+		"",
+		configs.Config.ENV_MODE == "production",
+		true,
+	)
+	c.Redirect(303, "/"+shortenerURL)
+}
+
+func (h *Handler) renderUnlockPage(c *gin.Context, code int, shortenerURL, errMsg string) {
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	c.Header("Cache-Control", "no-store")
+	c.Status(code)
+	if err := renderUnlockPage(c.Writer, unlockPageData{ShortenerURL: shortenerURL, Error: errMsg}); err != nil {
+		log.Println(err)
+	}
+}
+
+// GetPublicShortenerLinkStats serves the public stats page of a link, as JSON
+// to clients that ask for it and as HTML otherwise.
+func (h *Handler) GetPublicShortenerLinkStats(c *gin.Context) {
+	res, err := h.useCase.GetPublicShortenerLinkStats(c.Request.Host, c.Param("shortenerURL"))
+	if err != nil {
+		errMsg := err.Error()
+		c.JSON(err.Code(), app.NewErrorResponse("Failed to get shortener link stats", &errMsg))
+		return
+	}
+
+	c.Header("Cache-Control", fmt.Sprintf("public, max-age=%d", int(PublicStatsCacheTTL.Seconds())))
+	c.Header("Vary", "Accept")
+	if c.NegotiateFormat(binding.MIMEHTML, binding.MIMEJSON) == binding.MIMEJSON {
+		c.JSON(200, app.NewSuccessResponse("Shortener link stats retrieved successfully", res))
+		return
+	}
+
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	c.Status(200)
+	if err := renderPublicStatsPage(c.Writer, newPublicStatsPageData(res)); err != nil {
+		log.Println(err)
+	}
+}
+
+func (h *Handler) GetPublicBioPage(c *gin.Context) {
+	res, err := h.useCase.GetPublicBioPage(c.Param("slug"))
+	if err != nil {
+		errMsg := err.Error()
+		c.JSON(err.Code(), app.NewErrorResponse("Failed to get bio page", &errMsg))
+		return
+	}
+
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	c.Status(200)
+	if err := renderBioPage(c.Writer, res); err != nil {
+		log.Println(err)
+	}
+}
+
+// TrackBioPageItemClick counts the click and sends the visitor on to the
+// short link of the item.
+func (h *Handler) TrackBioPageItemClick(c *gin.Context) {
+	itemID, errUuid := uuid.Parse(c.Param("item"))
+	if errUuid != nil {
+		c.JSON(400, app.NewErrorResponse("Invalid bio page item ID", nil))
+		return
+	}
+
+	location, err := h.useCase.TrackBioPageItemClick(c.Param("slug"), itemID)
+	if err != nil {
+		errMsg := err.Error()
+		c.JSON(err.Code(), app.NewErrorResponse("Failed to open bio page item", &errMsg))
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.Redirect(http.StatusFound, location)
 }
 
+// GetAllShortenerLink lists the caller's links, or the links of every user
+// when the caller is an admin.
 func (h *Handler) GetAllShortenerLink(c *gin.Context) {
+	var userID *uuid.UUID
+	if !middleware.IsAdmin(c) {
+		id, ok := getUserID(c)
+		if !ok {
+			c.JSON(401, app.NewErrorResponse("Invalid user ID", nil))
+			return
+		}
+		userID = &id
+	}
+
+	queryParams, err := parseShortenerLinkQuery(c)
+	if err != nil {
+		errMsg := err.Error()
+		c.JSON(400, app.NewErrorResponse("Validation Error", &errMsg))
+		return
+	}
+
+	res, errApi := h.useCase.GetAllShortenerLink(userID, queryParams)
+	if errApi != nil {
+		errMsg := errApi.Error()
+		c.JSON(errApi.Code(), app.NewErrorResponse("Failed to get all shorten link", &errMsg))
+		return
+	}
+
+	c.JSON(200, app.NewPaginationResponse("All shorten link retrieved successfully", res.Meta, res.Data))
+}
+
+// GetBrokenShortenerLink lists the user's links whose destination failed its
+// last health check. It accepts the same parameters as the list endpoint.
+func (h *Handler) GetBrokenShortenerLink(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(401, app.NewErrorResponse("Invalid user ID", nil))
+		return
+	}
+
+	queryParams, err := parseShortenerLinkQuery(c)
+	if err != nil {
+		errMsg := err.Error()
+		c.JSON(400, app.NewErrorResponse("Validation Error", &errMsg))
+		return
+	}
+	if queryParams.Filters == nil {
+		queryParams.Filters = &map[string]string{}
+	}
+	(*queryParams.Filters)["is_broken"] = "true"
+
+	res, errApi := h.useCase.GetAllShortenerLink(&userID, queryParams)
+	if errApi != nil {
+		errMsg := errApi.Error()
+		c.JSON(errApi.Code(), app.NewErrorResponse("Failed to get broken shorten link", &errMsg))
+		return
+	}
+
+	c.JSON(200, app.NewPaginationResponse("Broken shorten link retrieved successfully", res.Meta, res.Data))
+}
+
+// parseShortenerLinkQuery parses the list query parameters. ignoredKeys are
+// endpoint specific parameters that must not be treated as filters.
+func parseShortenerLinkQuery(c *gin.Context, ignoredKeys ...string) (*query.QueryParams, error) {
 	queryParams := query.NewQueryParams([]string{"original_url"})
 	queryParams.Parse(c, "10")
+	if queryParams.Filters != nil {
+		for _, key := range ignoredKeys {
+			delete(*queryParams.Filters, key)
+		}
+		if len(*queryParams.Filters) == 0 {
+			queryParams.Filters = nil
+		}
+	}
+
 	err := queryParams.Validate(CustomValidator.ParamValidator{
 		MaxSearchLength:       100,
-		AllowedOrderByColumns: []string{"created_at", "original_url"},
+		AllowedOrderByColumns: []string{"created_at", "original_url", "expires_at"},
+		AllowedFilterKeys:     []string{"is_expired", "is_broken", "campaign_id", "domain_id", "tag", "folder"},
+		MaxFilterValueLength:  50,
+		MaxFilterValues:       10,
 		MaxPageSize:           100,
 	})
+	if err != nil {
+		return nil, err
+	}
+
+	if queryParams.Filters != nil {
+		if campaignID, ok := (*queryParams.Filters)["campaign_id"]; ok {
+			if _, err := uuid.Parse(campaignID); err != nil {
+				return nil, errors.New("invalid campaign_id filter")
+			}
+		}
+		if domainID, ok := (*queryParams.Filters)["domain_id"]; ok {
+			if _, err := uuid.Parse(domainID); err != nil {
+				return nil, errors.New("invalid domain_id filter")
+			}
+		}
+		for _, folderID := range queryParams.FilterValues["folder"] {
+			if _, err := uuid.Parse(folderID); err != nil && folderID != FolderNone {
+				return nil, errors.New("invalid folder filter: must be a folder ID or " + FolderNone)
+			}
+		}
+	}
+
+	return queryParams, nil
+}
+
+func (h *Handler) UpdateShortenerLink(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(401, app.NewErrorResponse("Invalid user ID", nil))
+		return
+	}
 
+	id, errUuid := uuid.Parse(c.Param("id"))
+	if errUuid != nil {
+		c.JSON(400, app.NewErrorResponse("Invalid shortener link ID", nil))
+		return
+	}
+
+	var data UpdateShortenerLinkRequestDTO
+	if err := c.ShouldBindJSON(&data); err != nil {
+		var errMessages = CustomValidator.FormatValidationErrors(err)
+		c.JSON(400, app.NewErrorResponse("Validation Error", &errMessages))
+		return
+	}
+
+	res, err := h.useCase.UpdateShortenerLink(userID, id, &data)
 	if err != nil {
 		errMsg := err.Error()
-		c.JSON(400, app.NewErrorResponse("Validation Error", &errMsg))
+		c.JSON(err.Code(), app.NewErrorResponse("Failed to update shortener link", &errMsg))
 		return
 	}
 
-	res, errApi := h.useCase.GetAllShortenerLink(queryParams)
-	if errApi != nil {
-		errMsg := errApi.Error()
-		c.JSON(errApi.Code(), app.NewErrorResponse("Failed to get all shorten link", &errMsg))
+	c.JSON(200, app.NewSuccessResponse("Shortener link updated successfully", res))
+}
+
+func (h *Handler) GetShortenerLinkRules(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(401, app.NewErrorResponse("Invalid user ID", nil))
 		return
 	}
 
-	c.JSON(200, app.NewPaginationResponse("All shorten link retrieved successfully", res.Meta, res.Data))
-}
\ No newline at end of file
+	id, errUuid := uuid.Parse(c.Param("id"))
+	if errUuid != nil {
+		c.JSON(400, app.NewErrorResponse("Invalid shortener link ID", nil))
+		return
+	}
+
+	res, err := h.useCase.GetShortenerLinkRules(userID, id)
+	if err != nil {
+		errMsg := err.Error()
+		c.JSON(err.Code(), app.NewErrorResponse("Failed to get shortener link rules", &errMsg))
+		return
+	}
+
+	c.JSON(200, app.NewSuccessResponse("Shortener link rules retrieved successfully", res))
+}
+
+func (h *Handler) ReplaceShortenerLinkRules(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(401, app.NewErrorResponse("Invalid user ID", nil))
+		return
+	}
+
+	id, errUuid := uuid.Parse(c.Param("id"))
+	if errUuid != nil {
+		c.JSON(400, app.NewErrorResponse("Invalid shortener link ID", nil))
+		return
+	}
+
+	var data ReplaceShortenerLinkRulesRequestDTO
+	if err := c.ShouldBindJSON(&data); err != nil {
+		var errMessages = CustomValidator.FormatValidationErrors(err)
+		c.JSON(400, app.NewErrorResponse("Validation Error", &errMessages))
+		return
+	}
+
+	res, err := h.useCase.ReplaceShortenerLinkRules(userID, id, &data)
+	if err != nil {
+		errMsg := err.Error()
+		c.JSON(err.Code(), app.NewErrorResponse("Failed to update shortener link rules", &errMsg))
+		return
+	}
+
+	c.JSON(200, app.NewSuccessResponse("Shortener link rules updated successfully", res))
+}
+
+func (h *Handler) GetShortenerLinkVariants(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(401, app.NewErrorResponse("Invalid user ID", nil))
+		return
+	}
+
+	id, errUuid := uuid.Parse(c.Param("id"))
+	if errUuid != nil {
+		c.JSON(400, app.NewErrorResponse("Invalid shortener link ID", nil))
+		return
+	}
+
+	res, err := h.useCase.GetShortenerLinkVariants(userID, id)
+	if err != nil {
+		errMsg := err.Error()
+		c.JSON(err.Code(), app.NewErrorResponse("Failed to get shortener link variants", &errMsg))
+		return
+	}
+
+	c.JSON(200, app.NewSuccessResponse("Shortener link variants retrieved successfully", res))
+}
+
+func (h *Handler) ReplaceShortenerLinkVariants(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(401, app.NewErrorResponse("Invalid user ID", nil))
+		return
+	}
+
+	id, errUuid := uuid.Parse(c.Param("id"))
+	if errUuid != nil {
+		c.JSON(400, app.NewErrorResponse("Invalid shortener link ID", nil))
+		return
+	}
+
+	var data ReplaceShortenerLinkVariantsRequestDTO
+	if err := c.ShouldBindJSON(&data); err != nil {
+		var errMessages = CustomValidator.FormatValidationErrors(err)
+		c.JSON(400, app.NewErrorResponse("Validation Error", &errMessages))
+		return
+	}
+
+	res, err := h.useCase.ReplaceShortenerLinkVariants(userID, id, &data)
+	if err != nil {
+		errMsg := err.Error()
+		c.JSON(err.Code(), app.NewErrorResponse("Failed to update shortener link variants", &errMsg))
+		return
+	}
+
+	c.JSON(200, app.NewSuccessResponse("Shortener link variants updated successfully", res))
+}
+
+func (h *Handler) DeleteShortenerLink(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(401, app.NewErrorResponse("Invalid user ID", nil))
+		return
+	}
+
+	id, errUuid := uuid.Parse(c.Param("id"))
+	if errUuid != nil {
+		c.JSON(400, app.NewErrorResponse("Invalid shortener link ID", nil))
+		return
+	}
+
+	if err := h.useCase.DeleteShortenerLink(userID, id); err != nil {
+		errMsg := err.Error()
+		c.JSON(err.Code(), app.NewErrorResponse("Failed to delete shortener link", &errMsg))
+		return
+	}
+
+	c.JSON(200, app.NewSuccessResponse[any]("Shortener link deleted successfully", nil))
+}
+
+func (h *Handler) GetDeletedShortenerLinks(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(401, app.NewErrorResponse("Invalid user ID", nil))
+		return
+	}
+
+	res, err := h.useCase.GetDeletedShortenerLinks(userID)
+	if err != nil {
+		errMsg := err.Error()
+		c.JSON(err.Code(), app.NewErrorResponse("Failed to get deleted shortener links", &errMsg))
+		return
+	}
+
+	c.JSON(200, app.NewSuccessResponse("Deleted shortener links retrieved successfully", res))
+}
+
+func (h *Handler) UndeleteShortenerLink(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(401, app.NewErrorResponse("Invalid user ID", nil))
+		return
+	}
+
+	id, errUuid := uuid.Parse(c.Param("id"))
+	if errUuid != nil {
+		c.JSON(400, app.NewErrorResponse("Invalid shortener link ID", nil))
+		return
+	}
+
+	res, err := h.useCase.UndeleteShortenerLink(userID, id)
+	if err != nil {
+		errMsg := err.Error()
+		c.JSON(err.Code(), app.NewErrorResponse("Failed to undelete shortener link", &errMsg))
+		return
+	}
+
+	c.JSON(200, app.NewSuccessResponse("Shortener link undeleted successfully", res))
+}
+
+func (h *Handler) GetShortenerLinkHistory(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(401, app.NewErrorResponse("Invalid user ID", nil))
+		return
+	}
+
+	id, errUuid := uuid.Parse(c.Param("id"))
+	if errUuid != nil {
+		c.JSON(400, app.NewErrorResponse("Invalid shortener link ID", nil))
+		return
+	}
+
+	res, err := h.useCase.GetShortenerLinkHistory(userID, id)
+	if err != nil {
+		errMsg := err.Error()
+		c.JSON(err.Code(), app.NewErrorResponse("Failed to get shortener link history", &errMsg))
+		return
+	}
+
+	c.JSON(200, app.NewSuccessResponse("Shortener link history retrieved successfully", res))
+}
+
+func (h *Handler) RestoreShortenerLinkVersion(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(401, app.NewErrorResponse("Invalid user ID", nil))
+		return
+	}
+
+	id, errUuid := uuid.Parse(c.Param("id"))
+	if errUuid != nil {
+		c.JSON(400, app.NewErrorResponse("Invalid shortener link ID", nil))
+		return
+	}
+
+	version, errVersion := strconv.Atoi(c.Param("version"))
+	if errVersion != nil || version < 1 {
+		c.JSON(400, app.NewErrorResponse("Invalid version", nil))
+		return
+	}
+
+	res, err := h.useCase.RestoreShortenerLinkVersion(userID, id, version)
+	if err != nil {
+		errMsg := err.Error()
+		c.JSON(err.Code(), app.NewErrorResponse("Failed to restore shortener link", &errMsg))
+		return
+	}
+
+	c.JSON(200, app.NewSuccessResponse("Shortener link restored successfully", res))
+}
+
+func (h *Handler) GetShortenerLinkStats(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(401, app.NewErrorResponse("Invalid user ID", nil))
+		return
+	}
+
+	id, errUuid := uuid.Parse(c.Param("id"))
+	if errUuid != nil {
+		c.JSON(400, app.NewErrorResponse("Invalid shortener link ID", nil))
+		return
+	}
+
+	var data GetShortenerLinkStatsRequestDTO
+	if err := c.ShouldBindQuery(&data); err != nil {
+		var errMessages = CustomValidator.FormatValidationErrors(err)
+		c.JSON(400, app.NewErrorResponse("Validation Error", &errMessages))
+		return
+	}
+
+	res, err := h.useCase.GetShortenerLinkStats(userID, id, &data)
+	if err != nil {
+		errMsg := err.Error()
+		c.JSON(err.Code(), app.NewErrorResponse("Failed to get shortener link stats", &errMsg))
+		return
+	}
+
+	c.JSON(200, app.NewSuccessResponse("Shortener link stats retrieved successfully", res))
+}
+
+// GetShortenerLinkQRCode serves a QR code for the link given by ID or short
+// code; codes of branded domain links also need the domain query parameter.
+// The output is deterministic, so repeat requests are answered with 304 Not
+// Modified when the client already has the same ETag.
+func (h *Handler) GetShortenerLinkQRCode(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(401, app.NewErrorResponse("Invalid user ID", nil))
+		return
+	}
+
+	var data GetShortenerLinkQRCodeRequestDTO
+	if err := c.ShouldBindQuery(&data); err != nil {
+		var errMessages = CustomValidator.FormatValidationErrors(err)
+		c.JSON(400, app.NewErrorResponse("Validation Error", &errMessages))
+		return
+	}
+
+	data.IfNoneMatch = c.GetHeader("If-None-Match")
+
+	res, err := h.useCase.GetShortenerLinkQRCode(userID, c.Param("id"), &data)
+	if err != nil {
+		errMsg := err.Error()
+		c.JSON(err.Code(), app.NewErrorResponse("Failed to generate QR code", &errMsg))
+		return
+	}
+
+	c.Header("ETag", res.ETag)
+	c.Header("Cache-Control", "private, max-age=86400")
+	if res.NotModified {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	c.Data(200, res.ContentType, res.Data)
+}
+
+func (h *Handler) GetCacheStats(c *gin.Context) {
+	res, err := h.useCase.GetCacheStats()
+	if err != nil {
+		errMsg := err.Error()
+		c.JSON(err.Code(), app.NewErrorResponse("Failed to get cache stats", &errMsg))
+		return
+	}
+
+	c.JSON(200, app.NewSuccessResponse("Cache stats retrieved successfully", res))
+}
+
+func (h *Handler) GetAllBlockedDomains(c *gin.Context) {
+	res, err := h.useCase.GetAllBlockedDomains()
+	if err != nil {
+		errMsg := err.Error()
+		c.JSON(err.Code(), app.NewErrorResponse("Failed to get blocked domains", &errMsg))
+		return
+	}
+
+	c.JSON(200, app.NewSuccessResponse("Blocked domains retrieved successfully", res))
+}
+
+func (h *Handler) CreateBlockedDomain(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(401, app.NewErrorResponse("Invalid user ID", nil))
+		return
+	}
+
+	var data CreateBlockedDomainRequestDTO
+	if err := c.ShouldBindJSON(&data); err != nil {
+		var errMessages = CustomValidator.FormatValidationErrors(err)
+		c.JSON(400, app.NewErrorResponse("Validation Error", &errMessages))
+		return
+	}
+
+	res, err := h.useCase.CreateBlockedDomain(userID, &data)
+	if err != nil {
+		errMsg := err.Error()
+		c.JSON(err.Code(), app.NewErrorResponse("Failed to block domain", &errMsg))
+		return
+	}
+
+	c.JSON(200, app.NewSuccessResponse("Domain blocked successfully", res))
+}
+
+func (h *Handler) DeleteBlockedDomain(c *gin.Context) {
+	id, errUuid := uuid.Parse(c.Param("id"))
+	if errUuid != nil {
+		c.JSON(400, app.NewErrorResponse("Invalid blocked domain ID", nil))
+		return
+	}
+
+	if err := h.useCase.DeleteBlockedDomain(id); err != nil {
+		errMsg := err.Error()
+		c.JSON(err.Code(), app.NewErrorResponse("Failed to unblock domain", &errMsg))
+		return
+	}
+
+	c.JSON(200, app.NewSuccessResponse[any]("Domain unblocked successfully", nil))
+}
+
+func (h *Handler) CreateCampaign(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(401, app.NewErrorResponse("Invalid user ID", nil))
+		return
+	}
+
+	var data CreateCampaignRequestDTO
+	if err := c.ShouldBindJSON(&data); err != nil {
+		var errMessages = CustomValidator.FormatValidationErrors(err)
+		c.JSON(400, app.NewErrorResponse("Validation Error", &errMessages))
+		return
+	}
+
+	res, err := h.useCase.CreateCampaign(userID, &data)
+	if err != nil {
+		errMsg := err.Error()
+		c.JSON(err.Code(), app.NewErrorResponse("Failed to create campaign", &errMsg))
+		return
+	}
+
+	c.JSON(200, app.NewSuccessResponse("Campaign created successfully", res))
+}
+
+func (h *Handler) GetAllCampaigns(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(401, app.NewErrorResponse("Invalid user ID", nil))
+		return
+	}
+
+	res, err := h.useCase.GetAllCampaigns(userID)
+	if err != nil {
+		errMsg := err.Error()
+		c.JSON(err.Code(), app.NewErrorResponse("Failed to get campaigns", &errMsg))
+		return
+	}
+
+	c.JSON(200, app.NewSuccessResponse("Campaigns retrieved successfully", res))
+}
+
+func (h *Handler) GetCampaign(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(401, app.NewErrorResponse("Invalid user ID", nil))
+		return
+	}
+
+	id, errUuid := uuid.Parse(c.Param("id"))
+	if errUuid != nil {
+		c.JSON(400, app.NewErrorResponse("Invalid campaign ID", nil))
+		return
+	}
+
+	res, err := h.useCase.GetCampaign(userID, id)
+	if err != nil {
+		errMsg := err.Error()
+		c.JSON(err.Code(), app.NewErrorResponse("Failed to get campaign", &errMsg))
+		return
+	}
+
+	c.JSON(200, app.NewSuccessResponse("Campaign retrieved successfully", res))
+}
+
+func (h *Handler) UpdateCampaign(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(401, app.NewErrorResponse("Invalid user ID", nil))
+		return
+	}
+
+	id, errUuid := uuid.Parse(c.Param("id"))
+	if errUuid != nil {
+		c.JSON(400, app.NewErrorResponse("Invalid campaign ID", nil))
+		return
+	}
+
+	var data UpdateCampaignRequestDTO
+	if err := c.ShouldBindJSON(&data); err != nil {
+		var errMessages = CustomValidator.FormatValidationErrors(err)
+		c.JSON(400, app.NewErrorResponse("Validation Error", &errMessages))
+		return
+	}
+
+	res, err := h.useCase.UpdateCampaign(userID, id, &data)
+	if err != nil {
+		errMsg := err.Error()
+		c.JSON(err.Code(), app.NewErrorResponse("Failed to update campaign", &errMsg))
+		return
+	}
+
+	c.JSON(200, app.NewSuccessResponse("Campaign updated successfully", res))
+}
+
+func (h *Handler) DeleteCampaign(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(401, app.NewErrorResponse("Invalid user ID", nil))
+		return
+	}
+
+	id, errUuid := uuid.Parse(c.Param("id"))
+	if errUuid != nil {
+		c.JSON(400, app.NewErrorResponse("Invalid campaign ID", nil))
+		return
+	}
+
+	if err := h.useCase.DeleteCampaign(userID, id); err != nil {
+		errMsg := err.Error()
+		c.JSON(err.Code(), app.NewErrorResponse("Failed to delete campaign", &errMsg))
+		return
+	}
+
+	c.JSON(200, app.NewSuccessResponse[any]("Campaign deleted successfully", nil))
+}
+
+func (h *Handler) GetCampaignStats(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(401, app.NewErrorResponse("Invalid user ID", nil))
+		return
+	}
+
+	id, errUuid := uuid.Parse(c.Param("id"))
+	if errUuid != nil {
+		c.JSON(400, app.NewErrorResponse("Invalid campaign ID", nil))
+		return
+	}
+
+	var data GetShortenerLinkStatsRequestDTO
+	if err := c.ShouldBindQuery(&data); err != nil {
+		var errMessages = CustomValidator.FormatValidationErrors(err)
+		c.JSON(400, app.NewErrorResponse("Validation Error", &errMessages))
+		return
+	}
+
+	res, err := h.useCase.GetCampaignStats(userID, id, &data)
+	if err != nil {
+		errMsg := err.Error()
+		c.JSON(err.Code(), app.NewErrorResponse("Failed to get campaign stats", &errMsg))
+		return
+	}
+
+	c.JSON(200, app.NewSuccessResponse("Campaign stats retrieved successfully", res))
+}
+
+func (h *Handler) CreateTag(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(401, app.NewErrorResponse("Invalid user ID", nil))
+		return
+	}
+
+	var data CreateTagRequestDTO
+	if err := c.ShouldBindJSON(&data); err != nil {
+		var errMessages = CustomValidator.FormatValidationErrors(err)
+		c.JSON(400, app.NewErrorResponse("Validation Error", &errMessages))
+		return
+	}
+
+	res, err := h.useCase.CreateTag(userID, &data)
+	if err != nil {
+		errMsg := err.Error()
+		c.JSON(err.Code(), app.NewErrorResponse("Failed to create tag", &errMsg))
+		return
+	}
+
+	c.JSON(200, app.NewSuccessResponse("Tag created successfully", res))
+}
+
+func (h *Handler) GetAllTags(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(401, app.NewErrorResponse("Invalid user ID", nil))
+		return
+	}
+
+	res, err := h.useCase.GetAllTags(userID)
+	if err != nil {
+		errMsg := err.Error()
+		c.JSON(err.Code(), app.NewErrorResponse("Failed to get tags", &errMsg))
+		return
+	}
+
+	c.JSON(200, app.NewSuccessResponse("Tags retrieved successfully", res))
+}
+
+func (h *Handler) UpdateTag(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(401, app.NewErrorResponse("Invalid user ID", nil))
+		return
+	}
+
+	id, errUuid := uuid.Parse(c.Param("id"))
+	if errUuid != nil {
+		c.JSON(400, app.NewErrorResponse("Invalid tag ID", nil))
+		return
+	}
+
+	var data UpdateTagRequestDTO
+	if err := c.ShouldBindJSON(&data); err != nil {
+		var errMessages = CustomValidator.FormatValidationErrors(err)
+		c.JSON(400, app.NewErrorResponse("Validation Error", &errMessages))
+		return
+	}
+
+	res, err := h.useCase.UpdateTag(userID, id, &data)
+	if err != nil {
+		errMsg := err.Error()
+		c.JSON(err.Code(), app.NewErrorResponse("Failed to update tag", &errMsg))
+		return
+	}
+
+	c.JSON(200, app.NewSuccessResponse("Tag updated successfully", res))
+}
+
+func (h *Handler) DeleteTag(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(401, app.NewErrorResponse("Invalid user ID", nil))
+		return
+	}
+
+	id, errUuid := uuid.Parse(c.Param("id"))
+	if errUuid != nil {
+		c.JSON(400, app.NewErrorResponse("Invalid tag ID", nil))
+		return
+	}
+
+	if err := h.useCase.DeleteTag(userID, id); err != nil {
+		errMsg := err.Error()
+		c.JSON(err.Code(), app.NewErrorResponse("Failed to delete tag", &errMsg))
+		return
+	}
+
+	c.JSON(200, app.NewSuccessResponse[any]("Tag deleted successfully", nil))
+}
+
+func (h *Handler) CreateFolder(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(401, app.NewErrorResponse("Invalid user ID", nil))
+		return
+	}
+
+	var data CreateFolderRequestDTO
+	if err := c.ShouldBindJSON(&data); err != nil {
+		var errMessages = CustomValidator.FormatValidationErrors(err)
+		c.JSON(400, app.NewErrorResponse("Validation Error", &errMessages))
+		return
+	}
+
+	res, err := h.useCase.CreateFolder(userID, &data)
+	if err != nil {
+		errMsg := err.Error()
+		c.JSON(err.Code(), app.NewErrorResponse("Failed to create folder", &errMsg))
+		return
+	}
+
+	c.JSON(200, app.NewSuccessResponse("Folder created successfully", res))
+}
+
+func (h *Handler) GetAllFolders(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(401, app.NewErrorResponse("Invalid user ID", nil))
+		return
+	}
+
+	res, err := h.useCase.GetAllFolders(userID)
+	if err != nil {
+		errMsg := err.Error()
+		c.JSON(err.Code(), app.NewErrorResponse("Failed to get folders", &errMsg))
+		return
+	}
+
+	c.JSON(200, app.NewSuccessResponse("Folders retrieved successfully", res))
+}
+
+func (h *Handler) UpdateFolder(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(401, app.NewErrorResponse("Invalid user ID", nil))
+		return
+	}
+
+	id, errUuid := uuid.Parse(c.Param("id"))
+	if errUuid != nil {
+		c.JSON(400, app.NewErrorResponse("Invalid folder ID", nil))
+		return
+	}
+
+	var data UpdateFolderRequestDTO
+	if err := c.ShouldBindJSON(&data); err != nil {
+		var errMessages = CustomValidator.FormatValidationErrors(err)
+		c.JSON(400, app.NewErrorResponse("Validation Error", &errMessages))
+		return
+	}
+
+	res, err := h.useCase.UpdateFolder(userID, id, &data)
+	if err != nil {
+		errMsg := err.Error()
+		c.JSON(err.Code(), app.NewErrorResponse("Failed to update folder", &errMsg))
+		return
+	}
+
+	c.JSON(200, app.NewSuccessResponse("Folder updated successfully", res))
+}
+
+func (h *Handler) DeleteFolder(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(401, app.NewErrorResponse("Invalid user ID", nil))
+		return
+	}
+
+	id, errUuid := uuid.Parse(c.Param("id"))
+	if errUuid != nil {
+		c.JSON(400, app.NewErrorResponse("Invalid folder ID", nil))
+		return
+	}
+
+	if err := h.useCase.DeleteFolder(userID, id); err != nil {
+		errMsg := err.Error()
+		c.JSON(err.Code(), app.NewErrorResponse("Failed to delete folder", &errMsg))
+		return
+	}
+
+	c.JSON(200, app.NewSuccessResponse[any]("Folder deleted successfully", nil))
+}
+
+func (h *Handler) CreateDomain(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(401, app.NewErrorResponse("Invalid user ID", nil))
+		return
+	}
+
+	var data CreateDomainRequestDTO
+	if err := c.ShouldBindJSON(&data); err != nil {
+		var errMessages = CustomValidator.FormatValidationErrors(err)
+		c.JSON(400, app.NewErrorResponse("Validation Error", &errMessages))
+		return
+	}
+
+	res, err := h.useCase.CreateDomain(userID, &data)
+	if err != nil {
+		errMsg := err.Error()
+		c.JSON(err.Code(), app.NewErrorResponse("Failed to create domain", &errMsg))
+		return
+	}
+
+	c.JSON(200, app.NewSuccessResponse("Domain created successfully", res))
+}
+
+func (h *Handler) GetAllDomains(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(401, app.NewErrorResponse("Invalid user ID", nil))
+		return
+	}
+
+	res, err := h.useCase.GetAllDomains(userID)
+	if err != nil {
+		errMsg := err.Error()
+		c.JSON(err.Code(), app.NewErrorResponse("Failed to get domains", &errMsg))
+		return
+	}
+
+	c.JSON(200, app.NewSuccessResponse("Domains retrieved successfully", res))
+}
+
+func (h *Handler) GetDomain(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(401, app.NewErrorResponse("Invalid user ID", nil))
+		return
+	}
+
+	id, errUuid := uuid.Parse(c.Param("id"))
+	if errUuid != nil {
+		c.JSON(400, app.NewErrorResponse("Invalid domain ID", nil))
+		return
+	}
+
+	res, err := h.useCase.GetDomain(userID, id)
+	if err != nil {
+		errMsg := err.Error()
+		c.JSON(err.Code(), app.NewErrorResponse("Failed to get domain", &errMsg))
+		return
+	}
+
+	c.JSON(200, app.NewSuccessResponse("Domain retrieved successfully", res))
+}
+
+func (h *Handler) VerifyDomain(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(401, app.NewErrorResponse("Invalid user ID", nil))
+		return
+	}
+
+	id, errUuid := uuid.Parse(c.Param("id"))
+	if errUuid != nil {
+		c.JSON(400, app.NewErrorResponse("Invalid domain ID", nil))
+		return
+	}
+
+	res, err := h.useCase.VerifyDomain(userID, id)
+	if err != nil {
+		errMsg := err.Error()
+		c.JSON(err.Code(), app.NewErrorResponse("Failed to verify domain", &errMsg))
+		return
+	}
+
+	c.JSON(200, app.NewSuccessResponse("Domain verified successfully", res))
+}
+
+func (h *Handler) DeleteDomain(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(401, app.NewErrorResponse("Invalid user ID", nil))
+		return
+	}
+
+	id, errUuid := uuid.Parse(c.Param("id"))
+	if errUuid != nil {
+		c.JSON(400, app.NewErrorResponse("Invalid domain ID", nil))
+		return
+	}
+
+	if err := h.useCase.DeleteDomain(userID, id); err != nil {
+		errMsg := err.Error()
+		c.JSON(err.Code(), app.NewErrorResponse("Failed to delete domain", &errMsg))
+		return
+	}
+
+	c.JSON(200, app.NewSuccessResponse[any]("Domain deleted successfully", nil))
+}
+
+// getUserID reads the user_id claim set by middleware.AuthenticateJWT.
+func getUserID(c *gin.Context) (uuid.UUID, bool) {
+	userID, exist := c.Get("user_id")
+	if !exist {
+		return uuid.Nil, false
+	}
+
+	userIDStr, ok := userID.(string)
+	if !ok {
+		return uuid.Nil, false
+	}
+
+	parsedID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return uuid.Nil, false
+	}
+
+	return parsedID, true
+}
+
+func (h *Handler) CreateBioPage(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(401, app.NewErrorResponse("Invalid user ID", nil))
+		return
+	}
+
+	var data CreateBioPageRequestDTO
+	if err := c.ShouldBindJSON(&data); err != nil {
+		var errMessages = CustomValidator.FormatValidationErrors(err)
+		c.JSON(400, app.NewErrorResponse("Validation Error", &errMessages))
+		return
+	}
+
+	res, err := h.useCase.CreateBioPage(userID, &data)
+	if err != nil {
+		errMsg := err.Error()
+		c.JSON(err.Code(), app.NewErrorResponse("Failed to create bio page", &errMsg))
+		return
+	}
+
+	c.JSON(200, app.NewSuccessResponse("Bio page created successfully", res))
+}
+
+func (h *Handler) GetAllBioPages(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(401, app.NewErrorResponse("Invalid user ID", nil))
+		return
+	}
+
+	res, err := h.useCase.GetAllBioPages(userID)
+	if err != nil {
+		errMsg := err.Error()
+		c.JSON(err.Code(), app.NewErrorResponse("Failed to get bio pages", &errMsg))
+		return
+	}
+
+	c.JSON(200, app.NewSuccessResponse("Bio pages retrieved successfully", res))
+}
+
+func (h *Handler) GetBioPage(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(401, app.NewErrorResponse("Invalid user ID", nil))
+		return
+	}
+
+	id, errUuid := uuid.Parse(c.Param("id"))
+	if errUuid != nil {
+		c.JSON(400, app.NewErrorResponse("Invalid bio page ID", nil))
+		return
+	}
+
+	res, err := h.useCase.GetBioPage(userID, id)
+	if err != nil {
+		errMsg := err.Error()
+		c.JSON(err.Code(), app.NewErrorResponse("Failed to get bio page", &errMsg))
+		return
+	}
+
+	c.JSON(200, app.NewSuccessResponse("Bio page retrieved successfully", res))
+}
+
+func (h *Handler) UpdateBioPage(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(401, app.NewErrorResponse("Invalid user ID", nil))
+		return
+	}
+
+	id, errUuid := uuid.Parse(c.Param("id"))
+	if errUuid != nil {
+		c.JSON(400, app.NewErrorResponse("Invalid bio page ID", nil))
+		return
+	}
+
+	var data UpdateBioPageRequestDTO
+	if err := c.ShouldBindJSON(&data); err != nil {
+		var errMessages = CustomValidator.FormatValidationErrors(err)
+		c.JSON(400, app.NewErrorResponse("Validation Error", &errMessages))
+		return
+	}
+
+	res, err := h.useCase.UpdateBioPage(userID, id, &data)
+	if err != nil {
+		errMsg := err.Error()
+		c.JSON(err.Code(), app.NewErrorResponse("Failed to update bio page", &errMsg))
+		return
+	}
+
+	c.JSON(200, app.NewSuccessResponse("Bio page updated successfully", res))
+}
+
+func (h *Handler) DeleteBioPage(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(401, app.NewErrorResponse("Invalid user ID", nil))
+		return
+	}
+
+	id, errUuid := uuid.Parse(c.Param("id"))
+	if errUuid != nil {
+		c.JSON(400, app.NewErrorResponse("Invalid bio page ID", nil))
+		return
+	}
+
+	if err := h.useCase.DeleteBioPage(userID, id); err != nil {
+		errMsg := err.Error()
+		c.JSON(err.Code(), app.NewErrorResponse("Failed to delete bio page", &errMsg))
+		return
+	}
+
+	c.JSON(200, app.NewSuccessResponse[any]("Bio page deleted successfully", nil))
+}
+
+func (h *Handler) ReplaceBioPageItems(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(401, app.NewErrorResponse("Invalid user ID", nil))
+		return
+	}
+
+	id, errUuid := uuid.Parse(c.Param("id"))
+	if errUuid != nil {
+		c.JSON(400, app.NewErrorResponse("Invalid bio page ID", nil))
+		return
+	}
+
+	var data ReplaceBioPageItemsRequestDTO
+	if err := c.ShouldBindJSON(&data); err != nil {
+		var errMessages = CustomValidator.FormatValidationErrors(err)
+		c.JSON(400, app.NewErrorResponse("Validation Error", &errMessages))
+		return
+	}
+
+	res, err := h.useCase.ReplaceBioPageItems(userID, id, &data)
+	if err != nil {
+		errMsg := err.Error()
+		c.JSON(err.Code(), app.NewErrorResponse("Failed to update bio page items", &errMsg))
+		return
+	}
+
+	c.JSON(200, app.NewSuccessResponse("Bio page items updated successfully", res))
+}
+
+func (h *Handler) CreateAppScheme(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(401, app.NewErrorResponse("Invalid user ID", nil))
+		return
+	}
+
+	var data CreateAppSchemeRequestDTO
+	if err := c.ShouldBindJSON(&data); err != nil {
+		var errMessages = CustomValidator.FormatValidationErrors(err)
+		c.JSON(400, app.NewErrorResponse("Validation Error", &errMessages))
+		return
+	}
+
+	res, err := h.useCase.CreateAppScheme(userID, &data)
+	if err != nil {
+		errMsg := err.Error()
+		c.JSON(err.Code(), app.NewErrorResponse("Failed to create app scheme", &errMsg))
+		return
+	}
+
+	c.JSON(200, app.NewSuccessResponse("App scheme created successfully", res))
+}
+
+func (h *Handler) GetAllAppSchemes(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(401, app.NewErrorResponse("Invalid user ID", nil))
+		return
+	}
+
+	res, err := h.useCase.GetAllAppSchemes(userID)
+	if err != nil {
+		errMsg := err.Error()
+		c.JSON(err.Code(), app.NewErrorResponse("Failed to get app schemes", &errMsg))
+		return
+	}
+
+	c.JSON(200, app.NewSuccessResponse("App schemes retrieved successfully", res))
+}
+
+func (h *Handler) DeleteAppScheme(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(401, app.NewErrorResponse("Invalid user ID", nil))
+		return
+	}
+
+	id, errUuid := uuid.Parse(c.Param("id"))
+	if errUuid != nil {
+		c.JSON(400, app.NewErrorResponse("Invalid app scheme ID", nil))
+		return
+	}
+
+	if err := h.useCase.DeleteAppScheme(userID, id); err != nil {
+		errMsg := err.Error()
+		c.JSON(err.Code(), app.NewErrorResponse("Failed to delete app scheme", &errMsg))
+		return
+	}
+
+	c.JSON(200, app.NewSuccessResponse[any]("App scheme deleted successfully", nil))
+}
@@ -0,0 +1,216 @@
+package shortlink
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/xcurvnubaim/njajal-gin-golang/internal/pkg/safehttp"
+)
+
+type HealthCheckerOptions struct {
+	// Interval is how often the checker looks for links due for a check.
+	Interval time.Duration
+	// RecheckAfter is how long a result stays fresh.
+	RecheckAfter time.Duration
+	// Timeout bounds each request, redirects included.
+	Timeout     time.Duration
+	Concurrency int
+	// BatchSize is the maximum number of links checked per interval.
+	BatchSize int
+	// Client defaults to an http.Client that only connects to public
+	// addresses and follows up to 10 redirects to allowed destinations.
+	Client *http.Client
+}
+
+// healthChecker periodically probes link destinations and stores the status
+// code, latency and whether the destination looks broken.
+type healthChecker struct {
+	repository IRepository
+	options    HealthCheckerOptions
+	client     *http.Client
+	done       chan struct{}
+	wg         sync.WaitGroup
+}
+
+func NewHealthChecker(repository IRepository, options HealthCheckerOptions) *healthChecker {
+	if options.Interval <= 0 {
+		options.Interval = DefaultHealthCheckInterval
+	}
+	if options.RecheckAfter <= 0 {
+		options.RecheckAfter = DefaultHealthRecheckAfter
+	}
+	if options.Timeout <= 0 {
+		options.Timeout = DefaultHealthCheckTimeout
+	}
+	if options.Concurrency <= 0 {
+		options.Concurrency = DefaultHealthCheckConcurrency
+	}
+	if options.BatchSize <= 0 {
+		options.BatchSize = DefaultHealthCheckBatchSize
+	}
+
+	client := options.Client
+	if client == nil {
+		// The owner sees the status, latency and error of every check, so
+		// neither the destination nor a redirect may reach internal hosts.
+		client = &http.Client{
+			Transport:     safehttp.NewTransport(),
+			CheckRedirect: checkHealthRedirect,
+		}
+	}
+
+	return &healthChecker{
+		repository: repository,
+		options:    options,
+		client:     client,
+		done:       make(chan struct{}),
+	}
+}
+
+func (h *healthChecker) Start() {
+	h.wg.Add(1)
+	go h.run()
+}
+
+// Stop waits for the checks in flight to finish.
+func (h *healthChecker) Stop() {
+	close(h.done)
+	h.wg.Wait()
+}
+
+func (h *healthChecker) run() {
+	defer h.wg.Done()
+
+	ticker := time.NewTicker(h.options.Interval)
+	defer ticker.Stop()
+
+	h.checkDue()
+	for {
+		select {
+		case <-ticker.C:
+			h.checkDue()
+		case <-h.done:
+			return
+		}
+	}
+}
+
+// checkDue checks one batch of links whose last result is stale, at most
+// Concurrency at a time.
+func (h *healthChecker) checkDue() {
+	now := time.Now()
+	shortenerLinks, err := h.repository.GetShortenerLinksDueForHealthCheck(now.Add(-h.options.RecheckAfter), h.options.BatchSize)
+	if err != nil {
+		log.Println("Failed to get shortener links due for health check:", err)
+		return
+	}
+
+	slots := make(chan struct{}, h.options.Concurrency)
+	var wg sync.WaitGroup
+	for _, shortenerLink := range shortenerLinks {
+		select {
+		case <-h.done:
+			wg.Wait()
+			return
+		case slots <- struct{}{}:
+		}
+
+		wg.Add(1)
+		go func(shortenerLink *ShortenerLinkModel) {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			check := h.Check(shortenerLink.OriginalURL)
+			if err := h.repository.UpdateShortenerLinkHealth(shortenerLink.ID, check); err != nil {
+				log.Println("Failed to store health check for", shortenerLink.ID, err)
+			}
+		}(shortenerLink)
+	}
+	wg.Wait()
+}
+
+// Check probes destination with a HEAD request, falling back to GET for
+// servers that do not support HEAD.
+func (h *healthChecker) Check(destination string) HealthCheck {
+	start := time.Now()
+	statusCode, err := h.probe(http.MethodHead, destination)
+	if err == nil && (statusCode == http.StatusMethodNotAllowed || statusCode == http.StatusNotImplemented) {
+		statusCode, err = h.probe(http.MethodGet, destination)
+	}
+
+	check := HealthCheck{
+		StatusCode: statusCode,
+		Latency:    time.Since(start),
+		CheckedAt:  time.Now(),
+	}
+	if err != nil {
+		check.Error = truncate(err.Error(), maxHealthErrorLength)
+		check.Broken = true
+		return check
+	}
+	check.Broken = isBrokenStatus(statusCode)
+	return check
+}
+
+func (h *healthChecker) probe(method, destination string) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), h.options.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, method, destination, nil)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("User-Agent", HealthCheckUserAgent)
+
+	res, err := h.client.Do(req)
+	if err != nil {
+		var urlErr interface{ Timeout() bool }
+		if errors.As(err, &urlErr) && urlErr.Timeout() {
+			return 0, errors.New("timed out")
+		}
+		return 0, err
+	}
+	defer res.Body.Close()
+	// Drain a little of the body so the connection can be reused.
+	_, _ = io.CopyN(io.Discard, res.Body, 4<<10)
+
+	return res.StatusCode, nil
+}
+
+// checkHealthRedirect re-checks every redirect target, since the destination
+// was only checked when the link was saved.
+func checkHealthRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= maxHealthCheckRedirects {
+		return fmt.Errorf("stopped after %d redirects", maxHealthCheckRedirects)
+	}
+	if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+		return ErrURLScheme
+	}
+	if isPrivateHost(normalizeDomain(req.URL.Hostname())) {
+		return ErrURLPrivateHost
+	}
+	return nil
+}
+
+// isBrokenStatus treats client and server errors as broken, except statuses
+// that usually mean the checker itself was turned away.
+func isBrokenStatus(statusCode int) bool {
+	switch statusCode {
+	case http.StatusUnauthorized, http.StatusForbidden, http.StatusTooManyRequests:
+		return false
+	}
+	return statusCode >= 400
+}
+
+func truncate(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	return s[:max]
+}
@@ -0,0 +1,178 @@
+package shortlink
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type healthRepositoryStub struct {
+	IRepository
+	links  []*ShortenerLinkModel
+	mu     sync.Mutex
+	checks map[uuid.UUID]HealthCheck
+}
+
+func (s *healthRepositoryStub) GetShortenerLinksDueForHealthCheck(checkedBefore time.Time, limit int) ([]*ShortenerLinkModel, error) {
+	if len(s.links) > limit {
+		return s.links[:limit], nil
+	}
+	return s.links, nil
+}
+
+func (s *healthRepositoryStub) UpdateShortenerLinkHealth(id uuid.UUID, check HealthCheck) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.checks == nil {
+		s.checks = make(map[uuid.UUID]HealthCheck)
+	}
+	s.checks[id] = check
+	return nil
+}
+
+func newHealthTestServer() *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ok", func(w http.ResponseWriter, r *http.Request) {})
+	mux.HandleFunc("/missing", func(w http.ResponseWriter, r *http.Request) {
+		http.NotFound(w, r)
+	})
+	mux.HandleFunc("/no-head", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})
+	mux.HandleFunc("/moved", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/missing", http.StatusFound)
+	})
+	mux.HandleFunc("/forbidden", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	})
+	mux.HandleFunc("/slow", func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+	})
+	return httptest.NewServer(mux)
+}
+
+// newTestHealthChecker lets the checker reach httptest servers, which listen
+// on loopback addresses the default client refuses.
+func newTestHealthChecker(repository IRepository, options HealthCheckerOptions) *healthChecker {
+	checker := NewHealthChecker(repository, options)
+	checker.client = &http.Client{}
+	return checker
+}
+
+func TestHealthChecker_Check(t *testing.T) {
+	server := newHealthTestServer()
+	defer server.Close()
+
+	checker := newTestHealthChecker(nil, HealthCheckerOptions{Timeout: 50 * time.Millisecond})
+
+	tests := []struct {
+		name       string
+		path       string
+		statusCode int
+		broken     bool
+	}{
+		{name: "Healthy", path: "/ok", statusCode: 200},
+		{name: "Not found", path: "/missing", statusCode: 404, broken: true},
+		{name: "Falls back to GET", path: "/no-head", statusCode: 200},
+		{name: "Follows redirects", path: "/moved", statusCode: 404, broken: true},
+		{name: "Forbidden is not broken", path: "/forbidden", statusCode: 403},
+		{name: "Timeout", path: "/slow", broken: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check := checker.Check(server.URL + tt.path)
+			assert.Equal(t, tt.statusCode, check.StatusCode)
+			assert.Equal(t, tt.broken, check.Broken)
+			assert.False(t, check.CheckedAt.IsZero())
+			if tt.statusCode == 0 {
+				assert.Equal(t, "timed out", check.Error)
+			}
+		})
+	}
+}
+
+func TestHealthChecker_Unreachable(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	url := server.URL
+	server.Close()
+
+	check := newTestHealthChecker(nil, HealthCheckerOptions{}).Check(url)
+	assert.True(t, check.Broken)
+	assert.Zero(t, check.StatusCode)
+	assert.NotEmpty(t, check.Error)
+}
+
+func TestHealthChecker_LimitsConcurrency(t *testing.T) {
+	var inFlight, maxInFlight atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		current := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			seen := maxInFlight.Load()
+			if current <= seen || maxInFlight.CompareAndSwap(seen, current) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+	}))
+	defer server.Close()
+
+	repo := &healthRepositoryStub{}
+	for i := 0; i < 10; i++ {
+		repo.links = append(repo.links, &ShortenerLinkModel{OriginalURL: server.URL})
+		repo.links[i].ID = uuid.New()
+	}
+
+	checker := newTestHealthChecker(repo, HealthCheckerOptions{Concurrency: 3, BatchSize: 8})
+	checker.checkDue()
+
+	assert.Len(t, repo.checks, 8)
+	assert.LessOrEqual(t, maxInFlight.Load(), int32(3))
+	for _, check := range repo.checks {
+		assert.Equal(t, 200, check.StatusCode)
+		assert.False(t, check.Broken)
+	}
+}
+
+func TestHealthChecker_RefusesInternalAddresses(t *testing.T) {
+	server := newHealthTestServer()
+	defer server.Close()
+
+	check := NewHealthChecker(nil, HealthCheckerOptions{}).Check(server.URL + "/ok")
+	assert.True(t, check.Broken)
+	assert.Zero(t, check.StatusCode)
+	assert.Contains(t, check.Error, "not public")
+}
+
+func TestCheckHealthRedirect(t *testing.T) {
+	tests := []struct {
+		name  string
+		url   string
+		via   int
+		error bool
+	}{
+		{name: "Public", url: "https://example.com/next"},
+		{name: "Metadata address", url: "http://169.254.169.254/latest/meta-data/", error: true},
+		{name: "Loopback", url: "http://127.0.0.1:8080/", error: true},
+		{name: "Localhost", url: "http://localhost/", error: true},
+		{name: "Other scheme", url: "ftp://example.com/", error: true},
+		{name: "Too many redirects", url: "https://example.com/", via: 10, error: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+			err := checkHealthRedirect(req, make([]*http.Request, tt.via))
+			assert.Equal(t, tt.error, err != nil)
+		})
+	}
+}
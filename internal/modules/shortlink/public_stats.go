@@ -0,0 +1,71 @@
+package shortlink
+
+import (
+	"time"
+
+	"github.com/xcurvnubaim/njajal-gin-golang/internal/pkg/e"
+)
+
+// GetPublicShortenerLinkStats returns the click totals and daily chart of a
+// link whose owner made its stats public. Links with private stats are
+// reported as not found so the page does not reveal that they exist. Results
+// are cached for PublicStatsCacheTTL.
+func (uc *useCase) GetPublicShortenerLinkStats(host, shortenerURL string) (*GetPublicShortenerLinkStatsResponseDTO, e.ApiError) {
+	shortenerLink, err := uc.getShortenerLinkByHost(host, shortenerURL)
+	if err != nil || !shortenerLink.StatsPublic {
+		return nil, e.NewApiError(404, "Shortener URL not found")
+	}
+
+	// The link lookup above runs on every request, so a link made private
+	// again stops being served from this cache at once.
+	if stats, ok := uc.publicStats.Get(shortenerLink.ID); ok {
+		return stats, nil
+	}
+
+	now := time.Now()
+	from := startOfDay(now).AddDate(0, 0, -(PublicStatsDays - 1))
+
+	total, unique, err := uc.repository.CountShortenerLinkClicks(shortenerLink.ID, shortenerLink.CreatedAt, now)
+	if err != nil {
+		return nil, e.NewApiError(500, err.Error())
+	}
+
+	series, err := uc.repository.GetShortenerLinkClickSeries(shortenerLink.ID, StatsIntervalDay, from, now)
+	if err != nil {
+		return nil, e.NewApiError(500, err.Error())
+	}
+
+	stats := &GetPublicShortenerLinkStatsResponseDTO{
+		ShortURL:       shortURL(shortenerLink),
+		TotalClicks:    total,
+		UniqueVisitors: unique,
+		From:           from.Format("2006-01-02"),
+		To:             now.Format("2006-01-02"),
+		Daily:          dailyClicks(series, from, PublicStatsDays),
+		GeneratedAt:    now.Format(time.RFC3339),
+	}
+	uc.publicStats.Set(shortenerLink.ID, stats, PublicStatsCacheTTL)
+
+	return stats, nil
+}
+
+// dailyClicks lays series out over days consecutive days starting at from,
+// with zero for the days without clicks.
+func dailyClicks(series []ClickSeriesPoint, from time.Time, days int) []DailyClicksDTO {
+	clicks := make(map[string]int64, len(series))
+	for _, point := range series {
+		clicks[point.Bucket.Format("2006-01-02")] += point.Clicks
+	}
+
+	data := make([]DailyClicksDTO, 0, days)
+	for i := 0; i < days; i++ {
+		date := from.AddDate(0, 0, i).Format("2006-01-02")
+		data = append(data, DailyClicksDTO{Date: date, Clicks: clicks[date]})
+	}
+	return data
+}
+
+func startOfDay(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,23 @@
+package shortlink
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDailyClicks(t *testing.T) {
+	from := time.Date(2026, 3, 30, 0, 0, 0, 0, time.UTC)
+	series := []ClickSeriesPoint{
+		{Bucket: time.Date(2026, 3, 30, 0, 0, 0, 0, time.UTC), Clicks: 4},
+		{Bucket: time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC), Clicks: 9},
+	}
+
+	assert.Equal(t, []DailyClicksDTO{
+		{Date: "2026-03-30", Clicks: 4},
+		{Date: "2026-03-31", Clicks: 0},
+		{Date: "2026-04-01", Clicks: 9},
+		{Date: "2026-04-02", Clicks: 0},
+	}, dailyClicks(series, from, 4))
+}
@@ -0,0 +1,95 @@
+package shortlink
+
+import (
+	"log"
+	"sync"
+	"time"
+)
+
+type IClickRecorder interface {
+	Record(click *ShortenerLinkClickModel)
+}
+
+// clickRecorder buffers clicks in memory and batch-inserts them from a
+// background goroutine so the redirect path never waits on the database.
+type clickRecorder struct {
+	repository    IRepository
+	queue         chan *ShortenerLinkClickModel
+	batchSize     int
+	flushInterval time.Duration
+	done          chan struct{}
+	wg            sync.WaitGroup
+}
+
+func NewClickRecorder(repository IRepository, bufferSize, batchSize int, flushInterval time.Duration) *clickRecorder {
+	return &clickRecorder{
+		repository:    repository,
+		queue:         make(chan *ShortenerLinkClickModel, bufferSize),
+		batchSize:     batchSize,
+		flushInterval: flushInterval,
+		done:          make(chan struct{}),
+	}
+}
+
+func (r *clickRecorder) Start() {
+	r.wg.Add(1)
+	go r.run()
+}
+
+// Stop flushes every buffered click and waits for the worker to exit.
+func (r *clickRecorder) Stop() {
+	close(r.done)
+	r.wg.Wait()
+}
+
+// Record enqueues a click without blocking. When the buffer is full the click
+// is dropped rather than slowing down the redirect.
+func (r *clickRecorder) Record(click *ShortenerLinkClickModel) {
+	select {
+	case r.queue <- click:
+	default:
+		log.Println("Click buffer is full, dropping click for", click.ShortenerLinkID)
+	}
+}
+
+func (r *clickRecorder) run() {
+	defer r.wg.Done()
+
+	ticker := time.NewTicker(r.flushInterval)
+	defer ticker.Stop()
+
+	batch := make([]*ShortenerLinkClickModel, 0, r.batchSize)
+	for {
+		select {
+		case click := <-r.queue:
+			batch = append(batch, click)
+			if len(batch) >= r.batchSize {
+				batch = r.flush(batch)
+			}
+		case <-ticker.C:
+			batch = r.flush(batch)
+		case <-r.done:
+			for {
+				select {
+				case click := <-r.queue:
+					batch = append(batch, click)
+				default:
+					r.flush(batch)
+					return
+				}
+			}
+		}
+	}
+}
+
+func (r *clickRecorder) flush(batch []*ShortenerLinkClickModel) []*ShortenerLinkClickModel {
+	if len(batch) == 0 {
+		return batch
+	}
+
+	if err := r.repository.CreateShortenerLinkClicks(batch); err != nil {
+		log.Println("Failed to store", len(batch), "clicks:", err)
+	}
+
+	return make([]*ShortenerLinkClickModel, 0, r.batchSize)
+}
@@ -0,0 +1,86 @@
+package shortlink
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type clickRepositoryStub struct {
+	IRepository
+	mu      sync.Mutex
+	batches [][]*ShortenerLinkClickModel
+}
+
+func (s *clickRepositoryStub) CreateShortenerLinkClicks(data []*ShortenerLinkClickModel) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.batches = append(s.batches, data)
+	return nil
+}
+
+func (s *clickRepositoryStub) total() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	total := 0
+	for _, batch := range s.batches {
+		total += len(batch)
+	}
+	return total
+}
+
+func newTestClick() *ShortenerLinkClickModel {
+	return NewShortenerLinkClick(uuid.New(), time.Now(), "", "", "", "", "", "")
+}
+
+func TestClickRecorder_FlushesFullBatches(t *testing.T) {
+	repo := &clickRepositoryStub{}
+	recorder := NewClickRecorder(repo, 10, 2, time.Hour)
+	recorder.Start()
+	defer recorder.Stop()
+
+	recorder.Record(newTestClick())
+	recorder.Record(newTestClick())
+
+	assert.Eventually(t, func() bool { return repo.total() == 2 }, time.Second, 10*time.Millisecond)
+}
+
+func TestClickRecorder_FlushesOnInterval(t *testing.T) {
+	repo := &clickRepositoryStub{}
+	recorder := NewClickRecorder(repo, 10, 100, 20*time.Millisecond)
+	recorder.Start()
+	defer recorder.Stop()
+
+	recorder.Record(newTestClick())
+
+	assert.Eventually(t, func() bool { return repo.total() == 1 }, time.Second, 10*time.Millisecond)
+}
+
+func TestClickRecorder_StopDrainsBuffer(t *testing.T) {
+	repo := &clickRepositoryStub{}
+	recorder := NewClickRecorder(repo, 10, 100, time.Hour)
+	recorder.Start()
+
+	for i := 0; i < 5; i++ {
+		recorder.Record(newTestClick())
+	}
+	recorder.Stop()
+
+	assert.Equal(t, 5, repo.total())
+}
+
+func TestClickRecorder_DropsWhenBufferFull(t *testing.T) {
+	repo := &clickRepositoryStub{}
+	recorder := NewClickRecorder(repo, 1, 100, time.Hour)
+
+	recorder.Record(newTestClick())
+	recorder.Record(newTestClick())
+
+	recorder.Start()
+	recorder.Stop()
+
+	assert.Equal(t, 1, repo.total())
+}
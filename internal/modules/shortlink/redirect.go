@@ -0,0 +1,41 @@
+package shortlink
+
+import (
+	"net/url"
+	"strings"
+)
+
+// mergeQuery appends the given parameters to destination. Keys already
+// present in the destination keep their value, so the link owner's parameters
+// are never overridden or duplicated by the visitor's.
+func mergeQuery(destination string, params url.Values) string {
+	if len(params) == 0 {
+		return destination
+	}
+
+	parsed, err := url.Parse(destination)
+	if err != nil {
+		return destination
+	}
+
+	existing := parsed.Query()
+	extra := make(url.Values)
+	for key, values := range params {
+		if _, ok := existing[key]; ok {
+			continue
+		}
+		extra[key] = values
+	}
+	if len(extra) == 0 {
+		return destination
+	}
+
+	// Encode only the new parameters so the destination's own query string is
+	// left byte for byte as the owner wrote it.
+	if parsed.RawQuery == "" {
+		parsed.RawQuery = extra.Encode()
+	} else {
+		parsed.RawQuery = strings.TrimSuffix(parsed.RawQuery, "&") + "&" + extra.Encode()
+	}
+	return parsed.String()
+}
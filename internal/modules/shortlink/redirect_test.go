@@ -0,0 +1,62 @@
+package shortlink
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeQuery(t *testing.T) {
+	tests := []struct {
+		name        string
+		destination string
+		params      url.Values
+		expected    string
+	}{
+		{
+			name:        "No params",
+			destination: "https://example.com/a?b=1",
+			expected:    "https://example.com/a?b=1",
+		},
+		{
+			name:        "Adds params",
+			destination: "https://example.com/a",
+			params:      url.Values{"ref": {"mail"}},
+			expected:    "https://example.com/a?ref=mail",
+		},
+		{
+			name:        "Keeps destination params",
+			destination: "https://example.com/a?ref=poster&x=%2F",
+			params:      url.Values{"ref": {"mail"}, "id": {"7"}},
+			expected:    "https://example.com/a?ref=poster&x=%2F&id=7",
+		},
+		{
+			name:        "Keeps fragment",
+			destination: "https://example.com/a#top",
+			params:      url.Values{"id": {"7"}},
+			expected:    "https://example.com/a?id=7#top",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, mergeQuery(tt.destination, tt.params))
+		})
+	}
+}
+
+func TestHandler_RedirectLegacyShortURL(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	NewHandler(r, nil, "/api/v1/shortener-link")
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/shortener-link/abc123?utm_source=mail", nil))
+
+	assert.Equal(t, http.StatusMovedPermanently, w.Code)
+	assert.Equal(t, "/abc123?utm_source=mail", w.Header().Get("Location"))
+}
@@ -2,15 +2,93 @@ package shortlink
 
 import (
 	"log"
+	"slices"
+	"strings"
+	"time"
 
+	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type IRepository interface {
 	CreateShortenerLink(data *ShortenerLinkModel) error
-	GetShortenerLinkByShortenerURL(shortenerURL string) (*ShortenerLinkModel, error)
+	GetShortenerLinkByShortenerURL(domainID *uuid.UUID, shortenerURL string) (*ShortenerLinkModel, error)
+	GetShortenerLinkByID(id uuid.UUID) (*ShortenerLinkModel, error)
+	UpdateShortenerLink(data *ShortenerLinkModel, resetColumns ...string) error
+	DeleteShortenerLink(data *ShortenerLinkModel) error
+	GetShortenerLinkRules(shortenerLinkID uuid.UUID) ([]*ShortenerLinkRuleModel, error)
+	ReplaceShortenerLinkRules(shortenerLinkID uuid.UUID, rules []*ShortenerLinkRuleModel) error
+	GetShortenerLinkVariants(shortenerLinkID uuid.UUID) ([]*ShortenerLinkVariantModel, error)
+	ReplaceShortenerLinkVariants(shortenerLinkID uuid.UUID, variants []*ShortenerLinkVariantModel) error
 	CountShortenerLink(applyQuery func(*gorm.DB) *gorm.DB) (int64, error)
 	GetAllShortenerLink(applyQuery func(*gorm.DB) *gorm.DB) ([]*ShortenerLinkModel, error)
+	EachShortenerLink(applyQuery func(*gorm.DB) *gorm.DB, fn func(*ShortenerLinkModel) error) error
+	Transaction(fn func(tx IRepository) error) error
+	IncrementShortenerLinkClickCount(shortenerLink *ShortenerLinkModel) (bool, error)
+	AddShortenerLinkClickCounts(counts map[uuid.UUID]int64) error
+	MarkExpiredShortenerLinks(now time.Time) ([]*ShortenerLinkModel, error)
+	CreateShortenerLinkClicks(data []*ShortenerLinkClickModel) error
+	GetShortenerLinksDueForHealthCheck(checkedBefore time.Time, limit int) ([]*ShortenerLinkModel, error)
+	UpdateShortenerLinkHealth(id uuid.UUID, check HealthCheck) error
+	GetAllBlockedDomains() ([]*BlockedDomainModel, error)
+	CreateBlockedDomain(data *BlockedDomainModel) error
+	DeleteBlockedDomain(id uuid.UUID) error
+	CountShortenerLinkClicks(shortenerLinkID uuid.UUID, from, to time.Time) (total int64, uniqueVisitors int64, err error)
+	GetShortenerLinkClickSeries(shortenerLinkID uuid.UUID, interval string, from, to time.Time) ([]ClickSeriesPoint, error)
+	GetShortenerLinkTopReferrers(shortenerLinkID uuid.UUID, from, to time.Time, limit int) ([]ReferrerCount, error)
+	GetShortenerLinkVariantCounts(shortenerLinkID uuid.UUID, from, to time.Time) ([]VariantCount, error)
+	CreateCampaign(data *CampaignModel) error
+	GetCampaignByID(id uuid.UUID) (*CampaignModel, error)
+	GetAllCampaigns(userID uuid.UUID) ([]*CampaignModel, error)
+	UpdateCampaign(data *CampaignModel) error
+	DeleteCampaign(data *CampaignModel) error
+	CountShortenerLinksByCampaign(userID uuid.UUID) (map[uuid.UUID]int64, error)
+	GetShortenerLinksByCampaign(campaignID uuid.UUID) ([]*ShortenerLinkModel, error)
+	CountCampaignClicks(campaignID uuid.UUID, from, to time.Time) (total int64, uniqueVisitors int64, err error)
+	GetCampaignClickSeries(campaignID uuid.UUID, interval string, from, to time.Time) ([]ClickSeriesPoint, error)
+	GetCampaignLinkCounts(campaignID uuid.UUID, from, to time.Time) ([]CampaignLinkCount, error)
+	CreateTag(data *TagModel) error
+	GetTagByID(id uuid.UUID) (*TagModel, error)
+	GetTagsByNames(userID uuid.UUID, names []string) ([]*TagModel, error)
+	GetAllTags(userID uuid.UUID) ([]*TagModel, error)
+	UpdateTag(data *TagModel) error
+	DeleteTag(data *TagModel) error
+	CountShortenerLinksByTag(userID uuid.UUID) (map[uuid.UUID]int64, error)
+	ReplaceShortenerLinkTags(shortenerLinkID uuid.UUID, tagIDs []uuid.UUID) error
+	CreateFolder(data *FolderModel) error
+	GetFolderByID(id uuid.UUID) (*FolderModel, error)
+	GetAllFolders(userID uuid.UUID) ([]*FolderModel, error)
+	UpdateFolder(data *FolderModel) error
+	DeleteFolder(data *FolderModel) error
+	CountShortenerLinksByFolder(userID uuid.UUID) (map[uuid.UUID]int64, error)
+	CreateShortenerLinkRevision(data *ShortenerLinkRevisionModel) error
+	GetShortenerLinkRevisions(shortenerLinkID uuid.UUID) ([]*ShortenerLinkRevisionModel, error)
+	GetShortenerLinkRevision(shortenerLinkID uuid.UUID, version int) (*ShortenerLinkRevisionModel, error)
+	GetDeletedShortenerLinkByID(id uuid.UUID) (*ShortenerLinkModel, error)
+	GetDeletedShortenerLinks(userID uuid.UUID) ([]*ShortenerLinkModel, error)
+	UndeleteShortenerLink(data *ShortenerLinkModel) error
+	CreateDomain(data *DomainModel) error
+	GetDomainByID(id uuid.UUID) (*DomainModel, error)
+	GetAllDomains(userID uuid.UUID) ([]*DomainModel, error)
+	GetVerifiedDomainByHostname(hostname string) (*DomainModel, error)
+	GetVerifiedDomainHostnames() ([]string, error)
+	VerifyDomain(data *DomainModel) error
+	DeleteDomain(data *DomainModel) error
+	CountShortenerLinksByDomain(userID uuid.UUID) (map[uuid.UUID]int64, error)
+	CreateBioPage(data *BioPageModel) error
+	GetBioPageByID(id uuid.UUID) (*BioPageModel, error)
+	GetBioPageBySlug(slug string) (*BioPageModel, error)
+	GetAllBioPages(userID uuid.UUID) ([]*BioPageModel, error)
+	UpdateBioPage(data *BioPageModel) error
+	DeleteBioPage(data *BioPageModel) error
+	ReplaceBioPageItems(bioPageID uuid.UUID, items []*BioPageItemModel) error
+	IncrementBioPageItemClickCount(bioPageID, itemID uuid.UUID) (bool, error)
+	CreateAppScheme(data *AppSchemeModel) error
+	GetAppSchemeByID(id uuid.UUID) (*AppSchemeModel, error)
+	GetAllAppSchemes(userID uuid.UUID) ([]*AppSchemeModel, error)
+	HasAppScheme(userID uuid.UUID, scheme string) (bool, error)
+	DeleteAppScheme(data *AppSchemeModel) error
 }
 
 type repository struct {
@@ -30,9 +108,29 @@ func (r *repository) CreateShortenerLink(data *ShortenerLinkModel) error {
 	return nil
 }
 
-func (r *repository) GetShortenerLinkByShortenerURL(shortenerURL string) (*ShortenerLinkModel, error) {
+// GetShortenerLinkByShortenerURL looks a code up on the given branded domain,
+// or among the links served from BASE_URL when domainID is nil.
+func (r *repository) GetShortenerLinkByShortenerURL(domainID *uuid.UUID, shortenerURL string) (*ShortenerLinkModel, error) {
 	var shortenerLink ShortenerLinkModel
-	err := r.db.Where("shortener_url = ?", shortenerURL).First(&shortenerLink).Error
+	db := r.db.Preload("Rules", byPosition).Preload("Variants", byPosition).Preload("Campaign").Preload("Domain").
+		Where("shortener_url = ?", shortenerURL)
+	if domainID != nil {
+		db = db.Where("domain_id = ?", *domainID)
+	} else {
+		db = db.Where("domain_id IS NULL")
+	}
+	err := db.First(&shortenerLink).Error
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	return &shortenerLink, nil
+}
+
+func (r *repository) GetShortenerLinkByID(id uuid.UUID) (*ShortenerLinkModel, error) {
+	var shortenerLink ShortenerLinkModel
+	err := r.db.Preload("Tags", tagsByName).Preload("Domain").Preload("Rules", byPosition).Preload("Variants", byPosition).
+		Where("id = ?", id).First(&shortenerLink).Error
 	if err != nil {
 		log.Println(err)
 		return nil, err
@@ -40,6 +138,116 @@ func (r *repository) GetShortenerLinkByShortenerURL(shortenerURL string) (*Short
 	return &shortenerLink, nil
 }
 
+// shortenerLinkEditableColumns are the columns UpdateShortenerLink writes.
+// Click counts, expiry and health results are kept up to date by the redirect
+// path and background workers, so saving them from a row loaded earlier
+// would undo their updates.
+var shortenerLinkEditableColumns = []string{
+	"original_url", "shortener_url", "active_from", "expires_at", "max_clicks",
+	"password_hash", "redirect_status", "forward_query", "campaign_id", "folder_id",
+	"preview_title", "preview_description", "preview_image_url", "stats_public",
+	"deep_link_ios_app_url", "deep_link_ios_store_url",
+	"deep_link_android_app_url", "deep_link_android_store_url",
+	"updated_at",
+}
+
+// healthResetColumns queue a link for the next health check when its
+// destination changes.
+var healthResetColumns = []string{"is_broken", "health_checked_at"}
+
+// UpdateShortenerLink saves the owner-editable columns of data. resetColumns
+// names other columns the caller changed on purpose, such as is_expired after
+// a schedule change.
+func (r *repository) UpdateShortenerLink(data *ShortenerLinkModel, resetColumns ...string) error {
+	columns := append(slices.Clone(shortenerLinkEditableColumns), resetColumns...)
+	err := r.db.Model(data).Select(columns).Updates(data).Error
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	return nil
+}
+
+func (r *repository) DeleteShortenerLink(data *ShortenerLinkModel) error {
+	err := r.db.Delete(data).Error
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	return nil
+}
+
+func (r *repository) GetShortenerLinkRules(shortenerLinkID uuid.UUID) ([]*ShortenerLinkRuleModel, error) {
+	var rules []*ShortenerLinkRuleModel
+	err := r.db.Where("shortener_link_id = ?", shortenerLinkID).Order("position").Find(&rules).Error
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	return rules, nil
+}
+
+// ReplaceShortenerLinkRules swaps the whole rule list of a link atomically so
+// the redirect path never sees a partially written list.
+func (r *repository) ReplaceShortenerLinkRules(shortenerLinkID uuid.UUID, rules []*ShortenerLinkRuleModel) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("shortener_link_id = ?", shortenerLinkID).Delete(&ShortenerLinkRuleModel{}).Error; err != nil {
+			return err
+		}
+		if len(rules) == 0 {
+			return nil
+		}
+		return tx.Create(rules).Error
+	})
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	return nil
+}
+
+func (r *repository) GetShortenerLinkVariants(shortenerLinkID uuid.UUID) ([]*ShortenerLinkVariantModel, error) {
+	var variants []*ShortenerLinkVariantModel
+	err := r.db.Where("shortener_link_id = ?", shortenerLinkID).Order("position").Find(&variants).Error
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	return variants, nil
+}
+
+// ReplaceShortenerLinkVariants saves the given variants and soft deletes any
+// other variant of the link. Variants keep their ID across edits so sticky
+// assignments and click attribution survive weight or destination changes.
+func (r *repository) ReplaceShortenerLinkVariants(shortenerLinkID uuid.UUID, variants []*ShortenerLinkVariantModel) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		keep := make([]uuid.UUID, 0, len(variants))
+		for _, variant := range variants {
+			keep = append(keep, variant.ID)
+		}
+
+		remove := tx.Where("shortener_link_id = ?", shortenerLinkID)
+		if len(keep) > 0 {
+			remove = remove.Where("id NOT IN ?", keep)
+		}
+		if err := remove.Delete(&ShortenerLinkVariantModel{}).Error; err != nil {
+			return err
+		}
+
+		for _, variant := range variants {
+			if err := tx.Save(variant).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	return nil
+}
+
 func (r *repository) CountShortenerLink(applyQuery func(*gorm.DB) *gorm.DB) (int64, error) {
 	var count int64
 	err := applyQuery(r.db).Model(&ShortenerLinkModel{}).Count(&count).Error
@@ -59,3 +267,982 @@ func (r *repository) GetAllShortenerLink(applyQuery func(*gorm.DB) *gorm.DB) ([]
 	}
 	return shortenerLinks, nil
 }
+
+// EachShortenerLink streams matching links row by row instead of loading the
+// whole result set, stopping at the first error returned by fn.
+func (r *repository) EachShortenerLink(applyQuery func(*gorm.DB) *gorm.DB, fn func(*ShortenerLinkModel) error) error {
+	rows, err := applyQuery(r.db.Model(&ShortenerLinkModel{})).Rows()
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var shortenerLink ShortenerLinkModel
+		if err := r.db.ScanRows(rows, &shortenerLink); err != nil {
+			log.Println(err)
+			return err
+		}
+		if err := fn(&shortenerLink); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// Transaction runs fn with a repository bound to a database transaction.
+// Calling Transaction on that repository again creates a savepoint, so a
+// failed statement can be rolled back without aborting the outer transaction.
+func (r *repository) Transaction(fn func(tx IRepository) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return fn(&repository{tx})
+	})
+}
+
+// IncrementShortenerLinkClickCount atomically consumes one click, returning
+// false when the link has already reached its max_clicks limit.
+func (r *repository) IncrementShortenerLinkClickCount(shortenerLink *ShortenerLinkModel) (bool, error) {
+	result := r.db.Model(&ShortenerLinkModel{}).
+		Where("id = ? AND (max_clicks IS NULL OR click_count < max_clicks)", shortenerLink.ID).
+		UpdateColumn("click_count", gorm.Expr("click_count + 1"))
+	if result.Error != nil {
+		log.Println(result.Error)
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *repository) AddShortenerLinkClickCounts(counts map[uuid.UUID]int64) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		for id, n := range counts {
+			err := tx.Model(&ShortenerLinkModel{}).
+				Where("id = ?", id).
+				UpdateColumn("click_count", gorm.Expr("click_count + ?", n)).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	return nil
+}
+
+// MarkExpiredShortenerLinks flags the links that just expired and returns
+// them.
+func (r *repository) MarkExpiredShortenerLinks(now time.Time) ([]*ShortenerLinkModel, error) {
+	var shortenerLinks []*ShortenerLinkModel
+	err := r.db.Model(&shortenerLinks).Clauses(clause.Returning{}).
+		Where("is_expired = ?", false).
+		Where("expires_at <= ? OR (max_clicks IS NOT NULL AND click_count >= max_clicks)", now).
+		UpdateColumn("is_expired", true).Error
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	return shortenerLinks, nil
+}
+
+func (r *repository) CreateShortenerLinkClicks(data []*ShortenerLinkClickModel) error {
+	err := r.db.CreateInBatches(data, 100).Error
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	return nil
+}
+
+// GetShortenerLinksDueForHealthCheck returns live links never checked or last
+// checked before checkedBefore, oldest first.
+func (r *repository) GetShortenerLinksDueForHealthCheck(checkedBefore time.Time, limit int) ([]*ShortenerLinkModel, error) {
+	var shortenerLinks []*ShortenerLinkModel
+	err := r.db.Select("id", "original_url").
+		Where("is_expired = ?", false).
+		Where("health_checked_at IS NULL OR health_checked_at < ?", checkedBefore).
+		Order("health_checked_at ASC NULLS FIRST").
+		Limit(limit).
+		Find(&shortenerLinks).Error
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	return shortenerLinks, nil
+}
+
+// UpdateShortenerLinkHealth only writes the health columns, leaving the rest
+// of the link untouched.
+func (r *repository) UpdateShortenerLinkHealth(id uuid.UUID, check HealthCheck) error {
+	var statusCode *int
+	if check.StatusCode != 0 {
+		statusCode = &check.StatusCode
+	}
+	err := r.db.Model(&ShortenerLinkModel{}).Where("id = ?", id).UpdateColumns(map[string]interface{}{
+		"is_broken":          check.Broken,
+		"health_status_code": statusCode,
+		"health_latency_ms":  int(check.Latency.Milliseconds()),
+		"health_error":       check.Error,
+		"health_checked_at":  check.CheckedAt,
+	}).Error
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	return nil
+}
+
+func (r *repository) CountShortenerLinkClicks(shortenerLinkID uuid.UUID, from, to time.Time) (int64, int64, error) {
+	var result struct {
+		Total          int64
+		UniqueVisitors int64
+	}
+	err := r.db.Model(&ShortenerLinkClickModel{}).
+		Select("COUNT(*) AS total, COUNT(DISTINCT ip_hash) AS unique_visitors").
+		Where("shortener_link_id = ? AND clicked_at BETWEEN ? AND ?", shortenerLinkID, from, to).
+		Scan(&result).Error
+	if err != nil {
+		log.Println(err)
+		return 0, 0, err
+	}
+	return result.Total, result.UniqueVisitors, nil
+}
+
+// GetShortenerLinkClickSeries buckets clicks with date_trunc, so interval must
+// be a Postgres date_trunc field such as hour, day or week.
+func (r *repository) GetShortenerLinkClickSeries(shortenerLinkID uuid.UUID, interval string, from, to time.Time) ([]ClickSeriesPoint, error) {
+	var series []ClickSeriesPoint
+	err := r.db.Model(&ShortenerLinkClickModel{}).
+		Select("date_trunc(?, clicked_at) AS bucket, COUNT(*) AS clicks", interval).
+		Where("shortener_link_id = ? AND clicked_at BETWEEN ? AND ?", shortenerLinkID, from, to).
+		Group("bucket").
+		Order("bucket ASC").
+		Scan(&series).Error
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	return series, nil
+}
+
+func (r *repository) GetShortenerLinkTopReferrers(shortenerLinkID uuid.UUID, from, to time.Time, limit int) ([]ReferrerCount, error) {
+	var referrers []ReferrerCount
+	err := r.db.Model(&ShortenerLinkClickModel{}).
+		Select("referrer, COUNT(*) AS clicks").
+		Where("shortener_link_id = ? AND clicked_at BETWEEN ? AND ? AND referrer <> ''", shortenerLinkID, from, to).
+		Group("referrer").
+		Order("clicks DESC").
+		Limit(limit).
+		Scan(&referrers).Error
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	return referrers, nil
+}
+
+// GetShortenerLinkVariantCounts compares the variants of a split test. Deleted
+// variants are included only while they still have clicks in the range.
+func (r *repository) GetShortenerLinkVariantCounts(shortenerLinkID uuid.UUID, from, to time.Time) ([]VariantCount, error) {
+	var counts []VariantCount
+	err := r.db.Table("shortener_link_variants AS v").
+		Select("v.id AS variant_id, v.name, v.destination_url, v.weight, v.deleted_at IS NOT NULL AS archived, "+
+			"COUNT(c.id) AS clicks, COUNT(DISTINCT c.ip_hash) AS unique_visitors").
+		Joins("LEFT JOIN shortener_link_clicks c ON c.variant_id = v.id AND c.clicked_at BETWEEN ? AND ?", from, to).
+		Where("v.shortener_link_id = ?", shortenerLinkID).
+		Group("v.id").
+		Having("v.deleted_at IS NULL OR COUNT(c.id) > 0").
+		Order("v.deleted_at IS NOT NULL, v.position").
+		Scan(&counts).Error
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	return counts, nil
+}
+
+func (r *repository) GetAllBlockedDomains() ([]*BlockedDomainModel, error) {
+	var blockedDomains []*BlockedDomainModel
+	err := r.db.Order("domain ASC").Find(&blockedDomains).Error
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	return blockedDomains, nil
+}
+
+func (r *repository) CreateBlockedDomain(data *BlockedDomainModel) error {
+	err := r.db.Create(data).Error
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	return nil
+}
+
+func (r *repository) DeleteBlockedDomain(id uuid.UUID) error {
+	result := r.db.Where("id = ?", id).Delete(&BlockedDomainModel{})
+	if result.Error != nil {
+		log.Println(result.Error)
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *repository) CreateCampaign(data *CampaignModel) error {
+	err := r.db.Create(data).Error
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	return nil
+}
+
+func (r *repository) GetCampaignByID(id uuid.UUID) (*CampaignModel, error) {
+	var campaign CampaignModel
+	err := r.db.Where("id = ?", id).First(&campaign).Error
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	return &campaign, nil
+}
+
+func (r *repository) GetAllCampaigns(userID uuid.UUID) ([]*CampaignModel, error) {
+	var campaigns []*CampaignModel
+	err := r.db.Where("user_id = ?", userID).Order("created_at DESC").Find(&campaigns).Error
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	return campaigns, nil
+}
+
+func (r *repository) UpdateCampaign(data *CampaignModel) error {
+	err := r.db.Save(data).Error
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	return nil
+}
+
+// DeleteCampaign detaches every link, deleted ones included, before removing
+// the campaign so no link keeps merging its UTM parameters.
+func (r *repository) DeleteCampaign(data *CampaignModel) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Model(&ShortenerLinkModel{}).
+			Where("campaign_id = ?", data.ID).
+			Update("campaign_id", nil).Error; err != nil {
+			return err
+		}
+		return tx.Delete(data).Error
+	})
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	return nil
+}
+
+// CountShortenerLinksByCampaign returns the number of live links in each of
+// the user's campaigns. Campaigns without links are absent from the map.
+func (r *repository) CountShortenerLinksByCampaign(userID uuid.UUID) (map[uuid.UUID]int64, error) {
+	var rows []struct {
+		CampaignID uuid.UUID
+		Count      int64
+	}
+	err := r.db.Model(&ShortenerLinkModel{}).
+		Select("campaign_id, COUNT(*) AS count").
+		Where("user_id = ? AND campaign_id IS NOT NULL", userID).
+		Group("campaign_id").
+		Scan(&rows).Error
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	counts := make(map[uuid.UUID]int64, len(rows))
+	for _, row := range rows {
+		counts[row.CampaignID] = row.Count
+	}
+	return counts, nil
+}
+
+// GetShortenerLinksByCampaign returns the live links of a campaign with only
+// the columns identifying them loaded.
+func (r *repository) GetShortenerLinksByCampaign(campaignID uuid.UUID) ([]*ShortenerLinkModel, error) {
+	var shortenerLinks []*ShortenerLinkModel
+	err := r.db.Select("id", "domain_id", "shortener_url").
+		Where("campaign_id = ?", campaignID).
+		Find(&shortenerLinks).Error
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	return shortenerLinks, nil
+}
+
+// campaignLinkIDs selects the links of a campaign, deleted ones included, so
+// their clicks still count towards the campaign.
+func (r *repository) campaignLinkIDs(campaignID uuid.UUID) *gorm.DB {
+	return r.db.Unscoped().Model(&ShortenerLinkModel{}).Select("id").Where("campaign_id = ?", campaignID)
+}
+
+func (r *repository) CountCampaignClicks(campaignID uuid.UUID, from, to time.Time) (int64, int64, error) {
+	var result struct {
+		Total          int64
+		UniqueVisitors int64
+	}
+	err := r.db.Model(&ShortenerLinkClickModel{}).
+		Select("COUNT(*) AS total, COUNT(DISTINCT ip_hash) AS unique_visitors").
+		Where("shortener_link_id IN (?) AND clicked_at BETWEEN ? AND ?", r.campaignLinkIDs(campaignID), from, to).
+		Scan(&result).Error
+	if err != nil {
+		log.Println(err)
+		return 0, 0, err
+	}
+	return result.Total, result.UniqueVisitors, nil
+}
+
+func (r *repository) GetCampaignClickSeries(campaignID uuid.UUID, interval string, from, to time.Time) ([]ClickSeriesPoint, error) {
+	var series []ClickSeriesPoint
+	err := r.db.Model(&ShortenerLinkClickModel{}).
+		Select("date_trunc(?, clicked_at) AS bucket, COUNT(*) AS clicks", interval).
+		Where("shortener_link_id IN (?) AND clicked_at BETWEEN ? AND ?", r.campaignLinkIDs(campaignID), from, to).
+		Group("bucket").
+		Order("bucket ASC").
+		Scan(&series).Error
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	return series, nil
+}
+
+// GetCampaignLinkCounts breaks the campaign's clicks down per live link, most
+// clicked first.
+func (r *repository) GetCampaignLinkCounts(campaignID uuid.UUID, from, to time.Time) ([]CampaignLinkCount, error) {
+	var counts []CampaignLinkCount
+	err := r.db.Table("shortener_links AS l").
+		Select("l.id AS shortener_link_id, l.shortener_url, l.original_url, "+
+			"COUNT(c.id) AS clicks, COUNT(DISTINCT c.ip_hash) AS unique_visitors").
+		Joins("LEFT JOIN shortener_link_clicks c ON c.shortener_link_id = l.id AND c.clicked_at BETWEEN ? AND ?", from, to).
+		Where("l.campaign_id = ? AND l.deleted_at IS NULL", campaignID).
+		Group("l.id").
+		Order("clicks DESC, l.shortener_url").
+		Scan(&counts).Error
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	return counts, nil
+}
+
+func (r *repository) CreateTag(data *TagModel) error {
+	err := r.db.Create(data).Error
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	return nil
+}
+
+func (r *repository) GetTagByID(id uuid.UUID) (*TagModel, error) {
+	var tag TagModel
+	err := r.db.Where("id = ?", id).First(&tag).Error
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	return &tag, nil
+}
+
+// GetTagsByNames looks tags up by name, ignoring case.
+func (r *repository) GetTagsByNames(userID uuid.UUID, names []string) ([]*TagModel, error) {
+	lowered := make([]string, 0, len(names))
+	for _, name := range names {
+		lowered = append(lowered, strings.ToLower(name))
+	}
+
+	var tags []*TagModel
+	err := r.db.Where("user_id = ? AND LOWER(name) IN ?", userID, lowered).Find(&tags).Error
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	return tags, nil
+}
+
+func (r *repository) GetAllTags(userID uuid.UUID) ([]*TagModel, error) {
+	var tags []*TagModel
+	err := r.db.Scopes(tagsByName).Where("user_id = ?", userID).Find(&tags).Error
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	return tags, nil
+}
+
+func (r *repository) UpdateTag(data *TagModel) error {
+	err := r.db.Save(data).Error
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	return nil
+}
+
+// DeleteTag removes the tag from every link before deleting it, so a tag
+// created later with the same name starts out empty.
+func (r *repository) DeleteTag(data *TagModel) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("tag_id = ?", data.ID).Delete(&ShortenerLinkTagModel{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(data).Error
+	})
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	return nil
+}
+
+// CountShortenerLinksByTag returns the number of live links carrying each of
+// the user's tags. Tags without links are absent from the map.
+func (r *repository) CountShortenerLinksByTag(userID uuid.UUID) (map[uuid.UUID]int64, error) {
+	var rows []struct {
+		TagID uuid.UUID
+		Count int64
+	}
+	err := r.db.Table("shortener_link_tags AS lt").
+		Select("lt.tag_id, COUNT(*) AS count").
+		Joins("JOIN shortener_links l ON l.id = lt.shortener_link_id AND l.deleted_at IS NULL").
+		Where("l.user_id = ?", userID).
+		Group("lt.tag_id").
+		Scan(&rows).Error
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	counts := make(map[uuid.UUID]int64, len(rows))
+	for _, row := range rows {
+		counts[row.TagID] = row.Count
+	}
+	return counts, nil
+}
+
+func (r *repository) ReplaceShortenerLinkTags(shortenerLinkID uuid.UUID, tagIDs []uuid.UUID) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("shortener_link_id = ?", shortenerLinkID).Delete(&ShortenerLinkTagModel{}).Error; err != nil {
+			return err
+		}
+		if len(tagIDs) == 0 {
+			return nil
+		}
+		linkTags := make([]ShortenerLinkTagModel, 0, len(tagIDs))
+		for _, tagID := range tagIDs {
+			linkTags = append(linkTags, ShortenerLinkTagModel{ShortenerLinkID: shortenerLinkID, TagID: tagID})
+		}
+		return tx.Create(&linkTags).Error
+	})
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	return nil
+}
+
+func (r *repository) CreateFolder(data *FolderModel) error {
+	err := r.db.Create(data).Error
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	return nil
+}
+
+func (r *repository) GetFolderByID(id uuid.UUID) (*FolderModel, error) {
+	var folder FolderModel
+	err := r.db.Where("id = ?", id).First(&folder).Error
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	return &folder, nil
+}
+
+func (r *repository) GetAllFolders(userID uuid.UUID) ([]*FolderModel, error) {
+	var folders []*FolderModel
+	err := r.db.Where("user_id = ?", userID).Order("LOWER(name)").Find(&folders).Error
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	return folders, nil
+}
+
+func (r *repository) UpdateFolder(data *FolderModel) error {
+	err := r.db.Save(data).Error
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	return nil
+}
+
+// DeleteFolder moves the folder's links, deleted ones included, out of the
+// folder before removing it.
+func (r *repository) DeleteFolder(data *FolderModel) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Model(&ShortenerLinkModel{}).
+			Where("folder_id = ?", data.ID).
+			Update("folder_id", nil).Error; err != nil {
+			return err
+		}
+		return tx.Delete(data).Error
+	})
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	return nil
+}
+
+// CountShortenerLinksByFolder returns the number of live links in each of the
+// user's folders. Empty folders are absent from the map.
+func (r *repository) CountShortenerLinksByFolder(userID uuid.UUID) (map[uuid.UUID]int64, error) {
+	var rows []struct {
+		FolderID uuid.UUID
+		Count    int64
+	}
+	err := r.db.Model(&ShortenerLinkModel{}).
+		Select("folder_id, COUNT(*) AS count").
+		Where("user_id = ? AND folder_id IS NOT NULL", userID).
+		Group("folder_id").
+		Scan(&rows).Error
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	counts := make(map[uuid.UUID]int64, len(rows))
+	for _, row := range rows {
+		counts[row.FolderID] = row.Count
+	}
+	return counts, nil
+}
+
+// CreateShortenerLinkRevision stores data as the next version of its link.
+// The link row is locked while the version is picked, so concurrent edits get
+// consecutive versions. Deleted links are locked too, as deletion itself is
+// recorded.
+func (r *repository) CreateShortenerLinkRevision(data *ShortenerLinkRevisionModel) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var shortenerLink ShortenerLinkModel
+		err := tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id").Where("id = ?", data.ShortenerLinkID).First(&shortenerLink).Error
+		if err != nil {
+			return err
+		}
+
+		var latest int
+		err = tx.Model(&ShortenerLinkRevisionModel{}).
+			Select("COALESCE(MAX(version), 0)").
+			Where("shortener_link_id = ?", data.ShortenerLinkID).
+			Scan(&latest).Error
+		if err != nil {
+			return err
+		}
+
+		data.Version = latest + 1
+		return tx.Create(data).Error
+	})
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	return nil
+}
+
+func (r *repository) GetShortenerLinkRevisions(shortenerLinkID uuid.UUID) ([]*ShortenerLinkRevisionModel, error) {
+	var revisions []*ShortenerLinkRevisionModel
+	err := r.db.Where("shortener_link_id = ?", shortenerLinkID).Order("version DESC").Find(&revisions).Error
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	return revisions, nil
+}
+
+func (r *repository) GetShortenerLinkRevision(shortenerLinkID uuid.UUID, version int) (*ShortenerLinkRevisionModel, error) {
+	var revision ShortenerLinkRevisionModel
+	err := r.db.Where("shortener_link_id = ? AND version = ?", shortenerLinkID, version).First(&revision).Error
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	return &revision, nil
+}
+
+func (r *repository) GetDeletedShortenerLinkByID(id uuid.UUID) (*ShortenerLinkModel, error) {
+	var shortenerLink ShortenerLinkModel
+	err := r.db.Unscoped().Preload("Tags", tagsByName).
+		Where("id = ? AND deleted_at IS NOT NULL", id).First(&shortenerLink).Error
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	return &shortenerLink, nil
+}
+
+// GetDeletedShortenerLinks returns the user's soft deleted links, most
+// recently deleted first.
+func (r *repository) GetDeletedShortenerLinks(userID uuid.UUID) ([]*ShortenerLinkModel, error) {
+	var shortenerLinks []*ShortenerLinkModel
+	err := r.db.Unscoped().Preload("Tags", tagsByName).
+		Where("user_id = ? AND deleted_at IS NOT NULL", userID).
+		Order("deleted_at DESC").Find(&shortenerLinks).Error
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	return shortenerLinks, nil
+}
+
+// UndeleteShortenerLink clears deleted_at. It fails with
+// gorm.ErrDuplicatedKey when another live link took the code in the meantime.
+func (r *repository) UndeleteShortenerLink(data *ShortenerLinkModel) error {
+	err := r.db.Unscoped().Model(&ShortenerLinkModel{}).
+		Where("id = ?", data.ID).
+		UpdateColumn("deleted_at", nil).Error
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	data.DeletedAt = gorm.DeletedAt{}
+	return nil
+}
+
+func (r *repository) CreateDomain(data *DomainModel) error {
+	err := r.db.Create(data).Error
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	return nil
+}
+
+func (r *repository) GetDomainByID(id uuid.UUID) (*DomainModel, error) {
+	var domain DomainModel
+	err := r.db.Where("id = ?", id).First(&domain).Error
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	return &domain, nil
+}
+
+func (r *repository) GetAllDomains(userID uuid.UUID) ([]*DomainModel, error) {
+	var domains []*DomainModel
+	err := r.db.Where("user_id = ?", userID).Order("hostname").Find(&domains).Error
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	return domains, nil
+}
+
+func (r *repository) GetVerifiedDomainByHostname(hostname string) (*DomainModel, error) {
+	var domain DomainModel
+	err := r.db.Where("hostname = ? AND verified_at IS NOT NULL", hostname).First(&domain).Error
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	return &domain, nil
+}
+
+func (r *repository) GetVerifiedDomainHostnames() ([]string, error) {
+	var hostnames []string
+	err := r.db.Model(&DomainModel{}).Where("verified_at IS NOT NULL").Pluck("hostname", &hostnames).Error
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	return hostnames, nil
+}
+
+// VerifyDomain stores data.VerifiedAt. It fails with gorm.ErrDuplicatedKey
+// when another account has already verified the hostname.
+func (r *repository) VerifyDomain(data *DomainModel) error {
+	err := r.db.Model(data).Update("verified_at", data.VerifiedAt).Error
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	return nil
+}
+
+func (r *repository) DeleteDomain(data *DomainModel) error {
+	err := r.db.Delete(data).Error
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	return nil
+}
+
+// CountShortenerLinksByDomain returns the number of live links on each of
+// the user's domains. Domains without links are absent from the map.
+func (r *repository) CountShortenerLinksByDomain(userID uuid.UUID) (map[uuid.UUID]int64, error) {
+	var rows []struct {
+		DomainID uuid.UUID
+		Count    int64
+	}
+	err := r.db.Model(&ShortenerLinkModel{}).
+		Select("domain_id, COUNT(*) AS count").
+		Where("user_id = ? AND domain_id IS NOT NULL", userID).
+		Group("domain_id").
+		Scan(&rows).Error
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	counts := make(map[uuid.UUID]int64, len(rows))
+	for _, row := range rows {
+		counts[row.DomainID] = row.Count
+	}
+	return counts, nil
+}
+
+func (r *repository) CreateBioPage(data *BioPageModel) error {
+	err := r.db.Omit(clause.Associations).Create(data).Error
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	return nil
+}
+
+func (r *repository) GetBioPageByID(id uuid.UUID) (*BioPageModel, error) {
+	var bioPage BioPageModel
+	err := r.db.Scopes(preloadBioPageItems).Where("id = ?", id).First(&bioPage).Error
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	return &bioPage, nil
+}
+
+// GetBioPageBySlug matches the slug ignoring case.
+func (r *repository) GetBioPageBySlug(slug string) (*BioPageModel, error) {
+	var bioPage BioPageModel
+	err := r.db.Scopes(preloadBioPageItems).Where("LOWER(slug) = ?", strings.ToLower(slug)).First(&bioPage).Error
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	return &bioPage, nil
+}
+
+func (r *repository) GetAllBioPages(userID uuid.UUID) ([]*BioPageModel, error) {
+	var bioPages []*BioPageModel
+	err := r.db.Scopes(preloadBioPageItems).Where("user_id = ?", userID).Order("LOWER(slug)").Find(&bioPages).Error
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	return bioPages, nil
+}
+
+func (r *repository) UpdateBioPage(data *BioPageModel) error {
+	err := r.db.Omit(clause.Associations).Save(data).Error
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	return nil
+}
+
+func (r *repository) DeleteBioPage(data *BioPageModel) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("bio_page_id = ?", data.ID).Delete(&BioPageItemModel{}).Error; err != nil {
+			return err
+		}
+		return tx.Omit(clause.Associations).Delete(data).Error
+	})
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	return nil
+}
+
+// ReplaceBioPageItems saves the given items and soft deletes any other item
+// of the page.
+func (r *repository) ReplaceBioPageItems(bioPageID uuid.UUID, items []*BioPageItemModel) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		keep := make([]uuid.UUID, 0, len(items))
+		for _, item := range items {
+			keep = append(keep, item.ID)
+		}
+
+		remove := tx.Where("bio_page_id = ?", bioPageID)
+		if len(keep) > 0 {
+			remove = remove.Where("id NOT IN ?", keep)
+		}
+		if err := remove.Delete(&BioPageItemModel{}).Error; err != nil {
+			return err
+		}
+
+		for _, item := range items {
+			if err := tx.Omit(clause.Associations).Save(item).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	return nil
+}
+
+// IncrementBioPageItemClickCount reports false when the item is not on the
+// page.
+func (r *repository) IncrementBioPageItemClickCount(bioPageID, itemID uuid.UUID) (bool, error) {
+	result := r.db.Model(&BioPageItemModel{}).
+		Where("id = ? AND bio_page_id = ?", itemID, bioPageID).
+		UpdateColumn("click_count", gorm.Expr("click_count + 1"))
+	if result.Error != nil {
+		log.Println(result.Error)
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// preloadBioPageItems loads the items of bio pages in order, with their links.
+// Deleted links load as nil.
+func preloadBioPageItems(db *gorm.DB) *gorm.DB {
+	return db.Preload("Items", func(db *gorm.DB) *gorm.DB {
+		return db.Order("position")
+	}).Preload("Items.ShortenerLink").Preload("Items.ShortenerLink.Domain")
+}
+
+func tagsByName(db *gorm.DB) *gorm.DB {
+	return db.Order("LOWER(name)")
+}
+
+func byPosition(db *gorm.DB) *gorm.DB {
+	return db.Order("position")
+}
+
+// filterByTags is the query.FilterScope of the tag= list filter. Tags are
+// matched by name, ignoring case.
+func filterByTags(db *gorm.DB, names []string, matchAll bool) *gorm.DB {
+	lowered := make([]string, 0, len(names))
+	for _, name := range names {
+		lowered = append(lowered, strings.ToLower(name))
+	}
+
+	tagged := db.Session(&gorm.Session{NewDB: true}).Table("shortener_link_tags AS lt").
+		Select("lt.shortener_link_id").
+		Joins("JOIN tags t ON t.id = lt.tag_id AND t.deleted_at IS NULL").
+		Where("LOWER(t.name) IN ?", lowered)
+	if matchAll {
+		tagged = tagged.Group("lt.shortener_link_id").
+			Having("COUNT(DISTINCT LOWER(t.name)) = ?", len(uniqueStrings(lowered)))
+	}
+	return db.Where("shortener_links.id IN (?)", tagged)
+}
+
+// filterByFolder is the query.FilterScope of the folder= list filter. The
+// value none selects links outside any folder.
+func filterByFolder(db *gorm.DB, folderIDs []string, _ bool) *gorm.DB {
+	ids := make([]string, 0, len(folderIDs))
+	withoutFolder := false
+	for _, id := range folderIDs {
+		if id == FolderNone {
+			withoutFolder = true
+			continue
+		}
+		ids = append(ids, id)
+	}
+
+	switch {
+	case withoutFolder && len(ids) > 0:
+		return db.Where("folder_id IS NULL OR folder_id IN ?", ids)
+	case withoutFolder:
+		return db.Where("folder_id IS NULL")
+	default:
+		return db.Where("folder_id IN ?", ids)
+	}
+}
+
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	unique := make([]string, 0, len(values))
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			unique = append(unique, value)
+		}
+	}
+	return unique
+}
+
+func (r *repository) CreateAppScheme(data *AppSchemeModel) error {
+	err := r.db.Create(data).Error
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	return nil
+}
+
+func (r *repository) GetAppSchemeByID(id uuid.UUID) (*AppSchemeModel, error) {
+	var appScheme AppSchemeModel
+	err := r.db.Where("id = ?", id).First(&appScheme).Error
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	return &appScheme, nil
+}
+
+func (r *repository) GetAllAppSchemes(userID uuid.UUID) ([]*AppSchemeModel, error) {
+	var appSchemes []*AppSchemeModel
+	err := r.db.Where("user_id = ?", userID).Order("scheme ASC").Find(&appSchemes).Error
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	return appSchemes, nil
+}
+
+// HasAppScheme reports whether the user registered the scheme, which must be
+// lower case.
+func (r *repository) HasAppScheme(userID uuid.UUID, scheme string) (bool, error) {
+	var count int64
+	err := r.db.Model(&AppSchemeModel{}).Where("user_id = ? AND scheme = ?", userID, scheme).Count(&count).Error
+	if err != nil {
+		log.Println(err)
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (r *repository) DeleteAppScheme(data *AppSchemeModel) error {
+	err := r.db.Delete(data).Error
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package shortlink
+
+import (
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/xcurvnubaim/njajal-gin-golang/internal/pkg/query"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func dryRunDB(t *testing.T) *gorm.DB {
+	conn, _, err := sqlmock.New()
+	assert.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: conn}), &gorm.Config{DryRun: true})
+	assert.NoError(t, err)
+	return db
+}
+
+func TestShortenerLinkFilterScopes(t *testing.T) {
+	tests := []struct {
+		name     string
+		filters  map[string][]string
+		match    string
+		expected string
+	}{
+		{
+			name:     "Any tag",
+			filters:  map[string][]string{"tag": {"Work", "q3"}},
+			expected: `SELECT * FROM "shortener_links" WHERE shortener_links.id IN (SELECT lt.shortener_link_id FROM shortener_link_tags AS lt JOIN tags t ON t.id = lt.tag_id AND t.deleted_at IS NULL WHERE LOWER(t.name) IN ($1,$2)) AND "shortener_links"."deleted_at" IS NULL`,
+		},
+		{
+			name:     "All tags",
+			filters:  map[string][]string{"tag": {"work", "q3", "WORK"}},
+			match:    query.MatchAll,
+			expected: `SELECT * FROM "shortener_links" WHERE shortener_links.id IN (SELECT lt.shortener_link_id FROM shortener_link_tags AS lt JOIN tags t ON t.id = lt.tag_id AND t.deleted_at IS NULL WHERE LOWER(t.name) IN ($1,$2,$3) GROUP BY "lt"."shortener_link_id" HAVING COUNT(DISTINCT LOWER(t.name)) = $4) AND "shortener_links"."deleted_at" IS NULL`,
+		},
+		{
+			name:     "Folder or no folder",
+			filters:  map[string][]string{"folder": {FolderNone, "6f1c2a52-3b43-4a5e-9a36-0e8f1f7d2a10"}},
+			expected: `SELECT * FROM "shortener_links" WHERE (folder_id IS NULL OR folder_id IN ($1)) AND "shortener_links"."deleted_at" IS NULL`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filters := make(map[string]string)
+			for key, values := range tt.filters {
+				filters[key] = values[0]
+			}
+			qp := &query.QueryParams{Filters: &filters, FilterValues: tt.filters, Match: tt.match}
+			shortenerLinkScope(nil, qp)
+
+			stmt := dryRunDB(t).Model(&ShortenerLinkModel{}).
+				Scopes(qp.ApplyCountQuery).
+				Find(&[]ShortenerLinkModel{}).Statement
+			assert.Equal(t, tt.expected, stmt.SQL.String())
+		})
+	}
+}
+
+func TestRepository_UpdateShortenerLinkKeepsCounters(t *testing.T) {
+	db := dryRunDB(t).Session(&gorm.Session{SkipDefaultTransaction: true})
+	var sql string
+	assert.NoError(t, db.Callback().Update().After("gorm:update").Register("capture", func(tx *gorm.DB) {
+		sql = tx.Statement.SQL.String()
+	}))
+
+	link := &ShortenerLinkModel{OriginalURL: "https://example.com/", ShortenerURL: "abc", ClickCount: 3}
+	link.ID = uuid.New()
+
+	assert.NoError(t, NewRepository(db).UpdateShortenerLink(link))
+	assert.Contains(t, sql, `"original_url"=`)
+	assert.NotContains(t, sql, "click_count")
+	assert.NotContains(t, sql, "is_expired")
+	assert.NotContains(t, sql, "health_")
+
+	assert.NoError(t, NewRepository(db).UpdateShortenerLink(link, "is_expired"))
+	assert.Contains(t, sql, `"is_expired"=`)
+	assert.NotContains(t, sql, "click_count")
+}
@@ -0,0 +1,412 @@
+package shortlink
+
+import (
+	"html/template"
+	"io"
+)
+
+type previewPageData struct {
+	Preview
+	// Interactive is set on /:code+ pages, which people open to see where a
+	// link goes before following it.
+	Interactive bool
+}
+
+// DisplayTitle falls back to the destination when no title was set.
+func (d previewPageData) DisplayTitle() string {
+	if d.Title != "" {
+		return d.Title
+	}
+	return d.DestinationURL
+}
+
+type publicStatsPageData struct {
+	*GetPublicShortenerLinkStatsResponseDTO
+	Bars []publicStatsBar
+}
+
+// publicStatsBar is one day of the chart. Height is a percentage of the
+// busiest day.
+type publicStatsBar struct {
+	Date   string
+	Clicks int64
+	Height int
+}
+
+func newPublicStatsPageData(stats *GetPublicShortenerLinkStatsResponseDTO) publicStatsPageData {
+	var busiest int64
+	for _, day := range stats.Daily {
+		busiest = max(busiest, day.Clicks)
+	}
+
+	bars := make([]publicStatsBar, 0, len(stats.Daily))
+	for _, day := range stats.Daily {
+		bar := publicStatsBar{Date: day.Date, Clicks: day.Clicks}
+		if busiest > 0 {
+			bar.Height = int(day.Clicks * 100 / busiest)
+		}
+		bars = append(bars, bar)
+	}
+
+	return publicStatsPageData{GetPublicShortenerLinkStatsResponseDTO: stats, Bars: bars}
+}
+
+// deepLinkPageData is the interstitial that tries to open the app. AppURL
+// is a web link or uses an app scheme the link owner registered.
+type deepLinkPageData struct {
+	AppURL          template.URL
+	FallbackURL     string
+	FallbackDelayMs int64
+}
+
+type unlockPageData struct {
+	ShortenerURL string
+	Error        string
+}
+
+var unlockPageTemplate = template.Must(template.New("unlock").Parse(`
+	<!DOCTYPE html>
+	<html lang="en">
+	<head>
+		<meta charset="UTF-8">
+		<meta name="viewport" content="width=device-width, initial-scale=1.0">
+		<meta name="robots" content="noindex">
+		<title>Protected Link</title>
+		<style>
+			body {
+				font-family: Arial, sans-serif;
+				margin: 0;
+				padding: 0;
+				background-color: #f4f4f4;
+				color: #333;
+			}
+			.unlock-container {
+				max-width: 400px;
+				margin: 80px auto;
+				background-color: #ffffff;
+				border-radius: 8px;
+				box-shadow: 0 4px 8px rgba(0, 0, 0, 0.1);
+				padding: 24px;
+			}
+			input[type=password] {
+				width: 100%;
+				padding: 10px;
+				margin: 12px 0;
+				box-sizing: border-box;
+			}
+			button {
+				width: 100%;
+				padding: 10px;
+				border: none;
+				border-radius: 4px;
+				background-color: #4CAF50;
+				color: #ffffff;
+				cursor: pointer;
+			}
+			.error {
+				color: #c62828;
+			}
+		</style>
+	</head>
+	<body>
+		<div class="unlock-container">
+			<h1>This link is protected</h1>
+			<p>Enter the password to continue.</p>
+			{{if .Error}}<p class="error">{{.Error}}</p>{{end}}
+			<form method="POST" action="/{{.ShortenerURL}}/unlock">
+				<input type="password" name="password" placeholder="Password" required autofocus>
+				<button type="submit">Unlock</button>
+			</form>
+		</div>
+	</body>
+	</html>
+`))
+
+var previewPageTemplate = template.Must(template.New("preview").Parse(`
+	<!DOCTYPE html>
+	<html lang="en">
+	<head>
+		<meta charset="UTF-8">
+		<meta name="viewport" content="width=device-width, initial-scale=1.0">
+		<meta name="robots" content="noindex">
+		<title>{{.DisplayTitle}}</title>
+		{{if .Description}}<meta name="description" content="{{.Description}}">{{end}}
+		<meta property="og:type" content="website">
+		<meta property="og:url" content="{{.ShortURL}}">
+		<meta property="og:title" content="{{.DisplayTitle}}">
+		{{if .Description}}<meta property="og:description" content="{{.Description}}">{{end}}
+		{{if .ImageURL}}<meta property="og:image" content="{{.ImageURL}}">{{end}}
+		<meta name="twitter:card" content="{{if .ImageURL}}summary_large_image{{else}}summary{{end}}">
+		<meta name="twitter:title" content="{{.DisplayTitle}}">
+		{{if .Description}}<meta name="twitter:description" content="{{.Description}}">{{end}}
+		{{if .ImageURL}}<meta name="twitter:image" content="{{.ImageURL}}">{{end}}
+		<style>
+			body {
+				font-family: Arial, sans-serif;
+				margin: 0;
+				padding: 0;
+				background-color: #f4f4f4;
+				color: #333;
+			}
+			.preview-container {
+				max-width: 520px;
+				margin: 80px auto;
+				background-color: #ffffff;
+				border-radius: 8px;
+				box-shadow: 0 4px 8px rgba(0, 0, 0, 0.1);
+				padding: 24px;
+			}
+			img {
+				max-width: 100%;
+				border-radius: 4px;
+			}
+			.destination {
+				word-break: break-all;
+				color: #666;
+			}
+			.button {
+				display: block;
+				padding: 10px;
+				border-radius: 4px;
+				background-color: #4CAF50;
+				color: #ffffff;
+				text-align: center;
+				text-decoration: none;
+			}
+		</style>
+	</head>
+	<body>
+		<div class="preview-container">
+			{{if .ImageURL}}<img src="{{.ImageURL}}" alt="">{{end}}
+			<h1>{{.DisplayTitle}}</h1>
+			{{if .Description}}<p>{{.Description}}</p>{{end}}
+			{{if .Interactive}}
+			<p class="destination">This link goes to {{.DestinationURL}}</p>
+			<a class="button" href="{{.ShortURL}}" rel="nofollow">Continue</a>
+			{{end}}
+		</div>
+	</body>
+	</html>
+`))
+
+var publicStatsPageTemplate = template.Must(template.New("stats").Parse(`
+	<!DOCTYPE html>
+	<html lang="en">
+	<head>
+		<meta charset="UTF-8">
+		<meta name="viewport" content="width=device-width, initial-scale=1.0">
+		<title>Stats for {{.ShortURL}}</title>
+		<style>
+			body {
+				font-family: Arial, sans-serif;
+				margin: 0;
+				padding: 0;
+				background-color: #f4f4f4;
+				color: #333;
+			}
+			.stats-container {
+				max-width: 720px;
+				margin: 80px auto;
+				background-color: #ffffff;
+				border-radius: 8px;
+				box-shadow: 0 4px 8px rgba(0, 0, 0, 0.1);
+				padding: 24px;
+			}
+			.short-url {
+				word-break: break-all;
+			}
+			.totals {
+				display: flex;
+				gap: 24px;
+			}
+			.total strong {
+				display: block;
+				font-size: 32px;
+			}
+			.chart {
+				display: flex;
+				align-items: flex-end;
+				gap: 2px;
+				height: 160px;
+				margin: 24px 0 8px;
+				border-bottom: 1px solid #ccc;
+			}
+			.bar {
+				flex: 1;
+				min-height: 1px;
+				background-color: #4CAF50;
+			}
+			.range {
+				display: flex;
+				justify-content: space-between;
+				color: #666;
+				font-size: 12px;
+			}
+		</style>
+	</head>
+	<body>
+		<div class="stats-container">
+			<h1 class="short-url">{{.ShortURL}}</h1>
+			<div class="totals">
+				<p class="total"><strong>{{.TotalClicks}}</strong> clicks</p>
+				<p class="total"><strong>{{.UniqueVisitors}}</strong> unique visitors</p>
+			</div>
+			<div class="chart">
+				{{range .Bars}}<div class="bar" style="height: {{.Height}}%" title="{{.Date}}: {{.Clicks}} clicks"></div>{{end}}
+			</div>
+			<div class="range"><span>{{.From}}</span><span>{{.To}}</span></div>
+		</div>
+	</body>
+	</html>
+`))
+
+var bioPageTemplate = template.Must(template.New("bio").Parse(`
+	<!DOCTYPE html>
+	<html lang="en">
+	<head>
+		<meta charset="UTF-8">
+		<meta name="viewport" content="width=device-width, initial-scale=1.0">
+		<title>{{.Title}}</title>
+		{{if .Description}}<meta name="description" content="{{.Description}}">{{end}}
+		<meta property="og:type" content="profile">
+		<meta property="og:title" content="{{.Title}}">
+		{{if .Description}}<meta property="og:description" content="{{.Description}}">{{end}}
+		{{if .AvatarURL}}<meta property="og:image" content="{{.AvatarURL}}">{{end}}
+		<style>
+			body {
+				font-family: Arial, sans-serif;
+				margin: 0;
+				padding: 0;
+				background-color: #f4f4f4;
+				color: #333;
+			}
+			body.theme-dark {
+				background-color: #121212;
+				color: #eee;
+			}
+			.bio-container {
+				max-width: 520px;
+				margin: 80px auto;
+				padding: 24px;
+				text-align: center;
+			}
+			.avatar {
+				width: 96px;
+				height: 96px;
+				border-radius: 50%;
+				object-fit: cover;
+			}
+			.links {
+				list-style: none;
+				padding: 0;
+			}
+			.button {
+				display: block;
+				margin: 12px 0;
+				padding: 14px;
+				border-radius: 8px;
+				background-color: #4CAF50;
+				color: #ffffff;
+				text-decoration: none;
+				word-break: break-word;
+			}
+			.theme-dark .button {
+				background-color: #2e7d32;
+			}
+		</style>
+	</head>
+	<body class="theme-{{.Theme}}">
+		<div class="bio-container">
+			{{if .AvatarURL}}<img class="avatar" src="{{.AvatarURL}}" alt="">{{end}}
+			<h1>{{.Title}}</h1>
+			{{if .Description}}<p>{{.Description}}</p>{{end}}
+			<ul class="links">
+				{{range .Links}}<li><a class="button" href="{{.URL}}" rel="nofollow">{{.Label}}</a></li>{{end}}
+			</ul>
+		</div>
+	</body>
+	</html>
+`))
+
+var deepLinkPageTemplate = template.Must(template.New("deep_link").Parse(`
+	<!DOCTYPE html>
+	<html lang="en">
+	<head>
+		<meta charset="UTF-8">
+		<meta name="viewport" content="width=device-width, initial-scale=1.0">
+		<meta name="robots" content="noindex">
+		<title>Opening the app</title>
+		<style>
+			body {
+				font-family: Arial, sans-serif;
+				margin: 0;
+				padding: 0;
+				background-color: #f4f4f4;
+				color: #333;
+			}
+			.deep-link-container {
+				max-width: 400px;
+				margin: 100px auto;
+				background-color: #ffffff;
+				border-radius: 8px;
+				box-shadow: 0 4px 8px rgba(0, 0, 0, 0.1);
+				padding: 20px;
+				text-align: center;
+			}
+			.button {
+				display: block;
+				padding: 10px;
+				border-radius: 4px;
+				background-color: #4CAF50;
+				color: #ffffff;
+				text-decoration: none;
+			}
+			.fallback {
+				color: #666;
+			}
+		</style>
+	</head>
+	<body>
+		<div class="deep-link-container">
+			<h2>Opening the app&hellip;</h2>
+			<a class="button" href="{{.AppURL}}">Open the app</a>
+			<p><a class="fallback" href="{{.FallbackURL}}" rel="nofollow">Continue without the app</a></p>
+		</div>
+		<script>
+			(function () {
+				// The page is hidden once the app opens; only visitors still
+				// looking at it are sent on.
+				var timer = setTimeout(function () {
+					window.location.replace({{.FallbackURL}});
+				}, {{.FallbackDelayMs}});
+				document.addEventListener("visibilitychange", function () {
+					if (document.hidden) {
+						clearTimeout(timer);
+					}
+				});
+				window.location.href = {{.AppURL}};
+			})();
+		</script>
+	</body>
+	</html>
+`))
+
+func renderDeepLinkPage(w io.Writer, data deepLinkPageData) error {
+	return deepLinkPageTemplate.Execute(w, data)
+}
+
+func renderBioPage(w io.Writer, data *BioPage) error {
+	return bioPageTemplate.Execute(w, data)
+}
+
+func renderPublicStatsPage(w io.Writer, data publicStatsPageData) error {
+	return publicStatsPageTemplate.Execute(w, data)
+}
+
+func renderPreviewPage(w io.Writer, data previewPageData) error {
+	return previewPageTemplate.Execute(w, data)
+}
+
+func renderUnlockPage(w io.Writer, data unlockPageData) error {
+	return unlockPageTemplate.Execute(w, data)
+}
@@ -0,0 +1,107 @@
+package shortlink
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderPreviewPage(t *testing.T) {
+	var buf bytes.Buffer
+	err := renderPreviewPage(&buf, previewPageData{Preview: Preview{
+		ShortURL:       "https://s.example/launch",
+		DestinationURL: "https://example.com/launch",
+		Title:          `Launch "day"`,
+		Description:    "<b>Big</b> news",
+		ImageURL:       "https://example.com/card.png",
+	}})
+	assert.NoError(t, err)
+
+	page := buf.String()
+	assert.Contains(t, page, `<meta property="og:title" content="Launch &#34;day&#34;">`)
+	assert.Contains(t, page, `<meta property="og:description" content="&lt;b&gt;Big&lt;/b&gt; news">`)
+	assert.Contains(t, page, `<meta property="og:image" content="https://example.com/card.png">`)
+	assert.Contains(t, page, `<meta name="twitter:card" content="summary_large_image">`)
+	assert.NotContains(t, page, "This link goes to")
+}
+
+func TestRenderPreviewPage_Interactive(t *testing.T) {
+	var buf bytes.Buffer
+	err := renderPreviewPage(&buf, previewPageData{
+		Preview: Preview{
+			ShortURL:       "https://s.example/launch",
+			DestinationURL: "https://example.com/launch",
+		},
+		Interactive: true,
+	})
+	assert.NoError(t, err)
+
+	page := buf.String()
+	assert.Contains(t, page, "<title>https://example.com/launch</title>")
+	assert.Contains(t, page, `<meta name="twitter:card" content="summary">`)
+	assert.Contains(t, page, "This link goes to https://example.com/launch")
+	assert.Contains(t, page, `href="https://s.example/launch"`)
+}
+
+func TestRenderPublicStatsPage(t *testing.T) {
+	var buf bytes.Buffer
+	err := renderPublicStatsPage(&buf, newPublicStatsPageData(&GetPublicShortenerLinkStatsResponseDTO{
+		ShortURL:       "https://s.example/launch",
+		TotalClicks:    12,
+		UniqueVisitors: 7,
+		From:           "2026-03-30",
+		To:             "2026-03-31",
+		Daily: []DailyClicksDTO{
+			{Date: "2026-03-30", Clicks: 3},
+			{Date: "2026-03-31", Clicks: 12},
+		},
+	}))
+	assert.NoError(t, err)
+
+	page := buf.String()
+	assert.Contains(t, page, "<title>Stats for https://s.example/launch</title>")
+	assert.Contains(t, page, "<strong>12</strong> clicks")
+	assert.Contains(t, page, "<strong>7</strong> unique visitors")
+	assert.Contains(t, page, `style="height: 25%" title="2026-03-30: 3 clicks"`)
+	assert.Contains(t, page, `style="height: 100%" title="2026-03-31: 12 clicks"`)
+}
+
+func TestRenderBioPage(t *testing.T) {
+	var buf bytes.Buffer
+	err := renderBioPage(&buf, &BioPage{
+		Slug:        "jane",
+		Title:       "Jane <Doe>",
+		Description: "Maker of things",
+		AvatarURL:   "https://example.com/jane.png",
+		Theme:       BioThemeDark,
+		Links: []BioPageLink{
+			{URL: "/@jane/4f1c2a8e-0b7d-4a53-9d3e-2f1e8c6b7a90", Label: "Blog"},
+			{URL: "/@jane/9b8a7c6d-5e4f-4a3b-8c2d-1e0f9a8b7c6d", Label: "Shop & more"},
+		},
+	})
+	assert.NoError(t, err)
+
+	page := buf.String()
+	assert.Contains(t, page, "<title>Jane &lt;Doe&gt;</title>")
+	assert.Contains(t, page, `<body class="theme-dark">`)
+	assert.Contains(t, page, `<img class="avatar" src="https://example.com/jane.png" alt="">`)
+	assert.Contains(t, page, `href="/@jane/4f1c2a8e-0b7d-4a53-9d3e-2f1e8c6b7a90" rel="nofollow">Blog</a>`)
+	assert.Contains(t, page, "Shop &amp; more</a>")
+}
+
+func TestRenderDeepLinkPage(t *testing.T) {
+	var buf bytes.Buffer
+	err := renderDeepLinkPage(&buf, deepLinkPageData{
+		AppURL:          "myapp://launch?ref=sms&x=</script>",
+		FallbackURL:     "https://apps.apple.com/app/id123?a=1&b=2",
+		FallbackDelayMs: 1500,
+	})
+	assert.NoError(t, err)
+
+	page := buf.String()
+	assert.Contains(t, page, `href="myapp://launch?ref=sms&amp;x=%3c/script%3e">Open the app</a>`)
+	assert.Contains(t, page, `href="https://apps.apple.com/app/id123?a=1&amp;b=2" rel="nofollow"`)
+	assert.Contains(t, page, `window.location.href = "myapp://launch?ref=sms\u0026x=\u003c/script\u003e";`)
+	assert.Contains(t, page, `window.location.replace("https://apps.apple.com/app/id123?a=1\u0026b=2");`)
+}
@@ -0,0 +1,149 @@
+package shortlink
+
+import (
+	"reflect"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// LinkSnapshot is the state of a link's editable settings as stored in a
+// revision. Passwords are never copied, only whether one was set.
+type LinkSnapshot struct {
+	OriginalURL        string     `json:"original_url"`
+	ShortenerURL       string     `json:"shortener_url"`
+	ActiveFrom         *time.Time `json:"active_from"`
+	ExpiresAt          *time.Time `json:"expires_at"`
+	MaxClicks          *int       `json:"max_clicks"`
+	IsProtected        bool       `json:"is_protected"`
+	RedirectStatus     int        `json:"redirect_status"`
+	ForwardQuery       bool       `json:"forward_query"`
+	StatsPublic        bool       `json:"stats_public"`
+	CampaignID         *uuid.UUID `json:"campaign_id"`
+	FolderID           *uuid.UUID `json:"folder_id"`
+	Tags               []string   `json:"tags"`
+	PreviewTitle       string     `json:"preview_title"`
+	PreviewDescription string     `json:"preview_description"`
+	PreviewImageURL    string     `json:"preview_image_url"`
+	DeepLink           DeepLink   `json:"deep_link"`
+	// Rules and Variants are nil in revisions recorded before they were
+	// tracked.
+	Rules    []ShortenerLinkRuleDTO    `json:"rules"`
+	Variants []ShortenerLinkVariantDTO `json:"variants"`
+}
+
+// FieldChange is one setting that differs between two snapshots. Old and New
+// hold the values as they are rendered in API responses.
+type FieldChange struct {
+	Field string
+	Old   any
+	New   any
+}
+
+func NewLinkSnapshot(shortenerLink *ShortenerLinkModel) LinkSnapshot {
+	return LinkSnapshot{
+		OriginalURL:        shortenerLink.OriginalURL,
+		ShortenerURL:       shortenerLink.ShortenerURL,
+		ActiveFrom:         shortenerLink.ActiveFrom,
+		ExpiresAt:          shortenerLink.ExpiresAt,
+		MaxClicks:          shortenerLink.MaxClicks,
+		IsProtected:        shortenerLink.IsProtected(),
+		RedirectStatus:     shortenerLink.RedirectStatus,
+		ForwardQuery:       shortenerLink.ForwardQuery,
+		StatsPublic:        shortenerLink.StatsPublic,
+		CampaignID:         shortenerLink.CampaignID,
+		FolderID:           shortenerLink.FolderID,
+		Tags:               tagNames(shortenerLink.Tags),
+		PreviewTitle:       shortenerLink.PreviewTitle,
+		PreviewDescription: shortenerLink.PreviewDescription,
+		PreviewImageURL:    shortenerLink.PreviewImageURL,
+		DeepLink:           shortenerLink.DeepLink,
+		Rules:              snapshotRules(shortenerLink.Rules),
+		Variants:           snapshotVariants(shortenerLink.Variants),
+	}
+}
+
+func snapshotRules(rules []ShortenerLinkRuleModel) []ShortenerLinkRuleDTO {
+	data := make([]ShortenerLinkRuleDTO, 0, len(rules))
+	for _, rule := range rules {
+		data = append(data, ShortenerLinkRuleDTO{
+			Type:           rule.Type,
+			Values:         rule.Values,
+			DestinationURL: rule.DestinationURL,
+		})
+	}
+	return data
+}
+
+func snapshotVariants(variants []ShortenerLinkVariantModel) []ShortenerLinkVariantDTO {
+	data := make([]ShortenerLinkVariantDTO, 0, len(variants))
+	for _, variant := range variants {
+		id := variant.ID.String()
+		data = append(data, ShortenerLinkVariantDTO{
+			ID:             &id,
+			Name:           variant.Name,
+			DestinationURL: variant.DestinationURL,
+			Weight:         variant.Weight,
+		})
+	}
+	return data
+}
+
+// Changes lists the settings that differ from previous, in a fixed order.
+// The first revision of a link has no previous snapshot and no changes.
+func (s *LinkSnapshot) Changes(previous *LinkSnapshot) []FieldChange {
+	changes := make([]FieldChange, 0)
+	if previous == nil {
+		return changes
+	}
+
+	oldFields := previous.fields()
+	for i, field := range s.fields() {
+		if oldFields[i].New == nil {
+			// The previous revision did not track this setting yet.
+			continue
+		}
+		if !reflect.DeepEqual(oldFields[i].New, field.New) {
+			changes = append(changes, FieldChange{Field: field.Field, Old: oldFields[i].New, New: field.New})
+		}
+	}
+	return changes
+}
+
+// fields returns every setting in New, formatted for comparison and display.
+func (s *LinkSnapshot) fields() []FieldChange {
+	tags := s.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+
+	return []FieldChange{
+		{Field: "original_url", New: s.OriginalURL},
+		{Field: "shortener_url", New: s.ShortenerURL},
+		{Field: "active_from", New: formatTime(s.ActiveFrom)},
+		{Field: "expires_at", New: formatTime(s.ExpiresAt)},
+		{Field: "max_clicks", New: s.MaxClicks},
+		{Field: "is_protected", New: s.IsProtected},
+		{Field: "redirect_status", New: s.RedirectStatus},
+		{Field: "forward_query", New: s.ForwardQuery},
+		{Field: "stats_public", New: s.StatsPublic},
+		{Field: "campaign_id", New: formatUUID(s.CampaignID)},
+		{Field: "folder_id", New: formatUUID(s.FolderID)},
+		{Field: "tags", New: tags},
+		{Field: "preview_title", New: s.PreviewTitle},
+		{Field: "preview_description", New: s.PreviewDescription},
+		{Field: "preview_image_url", New: s.PreviewImageURL},
+		{Field: "deep_link", New: toDeepLinkDTO(s.DeepLink)},
+		{Field: "rules", New: trackedSlice(s.Rules)},
+		{Field: "variants", New: trackedSlice(s.Variants)},
+	}
+}
+
+// trackedSlice returns a nil interface for settings a revision did not track,
+// which Changes skips over.
+func trackedSlice[T any](values []T) any {
+	if values == nil {
+		return nil
+	}
+	return values
+}
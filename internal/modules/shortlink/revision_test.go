@@ -0,0 +1,165 @@
+package shortlink
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	CustomValidator "github.com/xcurvnubaim/njajal-gin-golang/internal/pkg/validator"
+	"gorm.io/gorm"
+)
+
+type revisionRepositoryStub struct {
+	IRepository
+	link      *ShortenerLinkModel
+	revision  *ShortenerLinkRevisionModel
+	domain    *DomainModel
+	updated   bool
+	undeleted bool
+}
+
+func (s *revisionRepositoryStub) GetShortenerLinkByID(id uuid.UUID) (*ShortenerLinkModel, error) {
+	return s.link, nil
+}
+
+func (s *revisionRepositoryStub) GetShortenerLinkRevision(shortenerLinkID uuid.UUID, version int) (*ShortenerLinkRevisionModel, error) {
+	return s.revision, nil
+}
+
+func (s *revisionRepositoryStub) UpdateShortenerLink(data *ShortenerLinkModel, resetColumns ...string) error {
+	s.updated = true
+	return nil
+}
+
+func (s *revisionRepositoryStub) GetDeletedShortenerLinkByID(id uuid.UUID) (*ShortenerLinkModel, error) {
+	return s.link, nil
+}
+
+func (s *revisionRepositoryStub) UndeleteShortenerLink(data *ShortenerLinkModel) error {
+	s.undeleted = true
+	return nil
+}
+
+func (s *revisionRepositoryStub) CreateShortenerLinkRevision(data *ShortenerLinkRevisionModel) error {
+	return nil
+}
+
+func (s *revisionRepositoryStub) GetDomainByID(id uuid.UUID) (*DomainModel, error) {
+	if s.domain == nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return s.domain, nil
+}
+
+func TestLinkSnapshot_Changes(t *testing.T) {
+	expiresAt := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	link := NewShortenerLink(uuid.New(), "https://example.com", "abc")
+	link.ExpiresAt = &expiresAt
+	link.Tags = []TagModel{{Name: "launch"}}
+	before := NewLinkSnapshot(link)
+
+	assert.Empty(t, before.Changes(nil))
+
+	// Snapshots are read back from JSON, which must not show up as changes.
+	var stored LinkSnapshot
+	raw, err := json.Marshal(before)
+	assert.NoError(t, err)
+	assert.NoError(t, json.Unmarshal(raw, &stored))
+	assert.Empty(t, stored.Changes(&before))
+
+	link.OriginalURL = "https://example.org"
+	link.PasswordHash = "hash"
+	link.Tags = nil
+	after := NewLinkSnapshot(link)
+
+	assert.Equal(t, []FieldChange{
+		{Field: "original_url", Old: "https://example.com", New: "https://example.org"},
+		{Field: "is_protected", Old: false, New: true},
+		{Field: "tags", Old: []string{"launch"}, New: []string{}},
+	}, after.Changes(&stored))
+}
+
+func TestLinkSnapshot_ChangesRulesAndVariants(t *testing.T) {
+	link := NewShortenerLink(uuid.New(), "https://example.com", "abc")
+	before := NewLinkSnapshot(link)
+
+	link.Rules = []ShortenerLinkRuleModel{{Type: "platform", Values: []string{"ios"}, DestinationURL: "https://apps.apple.com"}}
+	after := NewLinkSnapshot(link)
+
+	assert.Equal(t, []FieldChange{
+		{Field: "rules", Old: []ShortenerLinkRuleDTO{}, New: []ShortenerLinkRuleDTO{
+			{Type: "platform", Values: []string{"ios"}, DestinationURL: "https://apps.apple.com"},
+		}},
+	}, after.Changes(&before))
+
+	// Revisions recorded before rules and variants were tracked have none.
+	before.Rules = nil
+	before.Variants = nil
+	assert.Empty(t, after.Changes(&before))
+}
+
+func TestRestoreShortenerLinkVersion_ChecksAlias(t *testing.T) {
+	policy := CustomValidator.NewAliasPolicy(CustomValidator.AliasMinLength, CustomValidator.AliasMaxLength, []string{"launch"}, nil)
+	assert.NoError(t, CustomValidator.RegisterAliasValidation(binding.Validator.Engine().(*validator.Validate), policy))
+
+	userID := uuid.New()
+	link := NewShortenerLink(userID, "https://example.com/", "summer")
+	old := NewShortenerLink(userID, "https://example.com/", "launch")
+	revision := NewShortenerLinkRevision(old, userID, RevisionActionUpdate)
+	revision.Snapshot = NewLinkSnapshot(old)
+
+	repo := &revisionRepositoryStub{link: link, revision: revision}
+	uc := NewuseCase(repo, nil, nil, nil, nil, nil, nil, nil, nil)
+
+	_, errApi := uc.RestoreShortenerLinkVersion(userID, link.ID, 1)
+	assert.Equal(t, 400, errApi.Code())
+	assert.Contains(t, errApi.Error(), "ShortenerURL")
+	assert.False(t, repo.updated)
+	assert.Equal(t, "summer", link.ShortenerURL)
+}
+
+func TestRestoreShortenerLinkVersion_ChecksDeepLink(t *testing.T) {
+	userID := uuid.New()
+	link := NewShortenerLink(userID, "https://example.com/", "summer")
+	old := NewShortenerLink(userID, "https://example.com/", "summer")
+	old.DeepLink = DeepLink{AndroidAppURL: "javascript:alert(1)"}
+	revision := NewShortenerLinkRevision(old, userID, RevisionActionUpdate)
+	revision.Snapshot = NewLinkSnapshot(old)
+
+	repo := &revisionRepositoryStub{link: link, revision: revision}
+	uc := NewuseCase(repo, nil, nil, nil, nil, nil, nil, nil, nil)
+
+	_, errApi := uc.RestoreShortenerLinkVersion(userID, link.ID, 1)
+	assert.Equal(t, 400, errApi.Code())
+	assert.Contains(t, errApi.Error(), "deep_link.android_app_url")
+	assert.False(t, repo.updated)
+}
+
+func TestUndeleteShortenerLink_ChecksDomain(t *testing.T) {
+	userID := uuid.New()
+	domainID := uuid.New()
+	link := NewShortenerLink(userID, "https://example.com/", "summer")
+	link.DomainID = &domainID
+
+	// The domain was deleted after the link.
+	repo := &revisionRepositoryStub{link: link}
+	uc := NewuseCase(repo, nil, nil, nil, nil, nil, nil, nil, nil)
+	_, errApi := uc.UndeleteShortenerLink(userID, link.ID)
+	assert.Equal(t, 400, errApi.Code())
+	assert.False(t, repo.undeleted)
+
+	repo.domain = &DomainModel{Hostname: "go.brand.example"}
+	_, errApi = uc.UndeleteShortenerLink(userID, link.ID)
+	assert.Equal(t, 400, errApi.Code())
+	assert.False(t, repo.undeleted)
+
+	now := time.Now()
+	repo.domain.VerifiedAt = &now
+	_, errApi = uc.UndeleteShortenerLink(userID, link.ID)
+	assert.Nil(t, errApi)
+	assert.True(t, repo.undeleted)
+}
@@ -0,0 +1,72 @@
+package shortlink
+
+import (
+	"log"
+	"sync"
+	"time"
+)
+
+// expirySweeper periodically flags links whose expiry date or click limit has
+// passed, so listings can hide them without evaluating each row. Each newly
+// expired link emits EventLinkExpired.
+type expirySweeper struct {
+	repository IRepository
+	emitter    EventEmitter
+	interval   time.Duration
+	done       chan struct{}
+	wg         sync.WaitGroup
+}
+
+func NewExpirySweeper(repository IRepository, emitter EventEmitter, interval time.Duration) *expirySweeper {
+	return &expirySweeper{
+		repository: repository,
+		emitter:    emitter,
+		interval:   interval,
+		done:       make(chan struct{}),
+	}
+}
+
+func (s *expirySweeper) Start() {
+	s.wg.Add(1)
+	go s.run()
+}
+
+func (s *expirySweeper) Stop() {
+	close(s.done)
+	s.wg.Wait()
+}
+
+func (s *expirySweeper) run() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	s.sweep()
+	for {
+		select {
+		case <-ticker.C:
+			s.sweep()
+		case <-s.done:
+			return
+		}
+	}
+}
+
+func (s *expirySweeper) sweep() {
+	shortenerLinks, err := s.repository.MarkExpiredShortenerLinks(time.Now())
+	if err != nil {
+		log.Println("Failed to mark expired shortener links:", err)
+		return
+	}
+	if len(shortenerLinks) == 0 {
+		return
+	}
+
+	log.Println("Marked", len(shortenerLinks), "shortener links as expired")
+	if s.emitter != nil {
+		for _, shortenerLink := range shortenerLinks {
+			s.emitter.Emit(shortenerLink.UserID, EventLinkExpired, toGetShortenerLink(shortenerLink))
+		}
+	}
+}
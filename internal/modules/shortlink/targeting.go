@@ -0,0 +1,174 @@
+package shortlink
+
+import (
+	"errors"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/xcurvnubaim/njajal-gin-golang/internal/pkg/geoip"
+	"github.com/xcurvnubaim/njajal-gin-golang/internal/pkg/useragent"
+)
+
+var (
+	ErrRuleInvalidPlatform = errors.New("platform values must be ios, android, mobile or desktop")
+	ErrRuleInvalidLanguage = errors.New("language values must be language tags such as en or pt-BR")
+	ErrRuleInvalidCountry  = errors.New("country values must be ISO 3166-1 alpha-2 codes such as US")
+)
+
+var (
+	languageTagPattern = regexp.MustCompile(`^[a-z]{2,3}(-[a-z0-9]{2,8})*$`)
+	countryCodePattern = regexp.MustCompile(`^[A-Z]{2}$`)
+)
+
+var rulePlatforms = map[string]bool{
+	PlatformIOS:     true,
+	PlatformAndroid: true,
+	PlatformMobile:  true,
+	PlatformDesktop: true,
+}
+
+// targetingContext holds the request attributes rules are matched against.
+// The country lookup is deferred until a country rule is evaluated.
+type targetingContext struct {
+	platforms map[string]bool
+	language  string
+	geo       geoip.CountryResolver
+	ip        string
+	country   *string
+}
+
+func newTargetingContext(visit *Visit, geo geoip.CountryResolver) *targetingContext {
+	return &targetingContext{
+		platforms: visitorPlatforms(visit.UserAgent),
+		language:  primaryLanguage(visit.AcceptLanguage),
+		geo:       geo,
+		ip:        visit.IP,
+	}
+}
+
+// matchRule returns the first rule matching the visit, or nil when the link's
+// own destination should be used.
+func matchRule(rules []ShortenerLinkRuleModel, ctx *targetingContext) *ShortenerLinkRuleModel {
+	for i := range rules {
+		if ctx.matches(&rules[i]) {
+			return &rules[i]
+		}
+	}
+	return nil
+}
+
+func (ctx *targetingContext) matches(rule *ShortenerLinkRuleModel) bool {
+	for _, value := range rule.Values {
+		switch rule.Type {
+		case RuleTypePlatform:
+			if ctx.platforms[value] {
+				return true
+			}
+		case RuleTypeLanguage:
+			// "pt" matches any Portuguese variant, "pt-br" only Brazilian.
+			if ctx.language == value || strings.HasPrefix(ctx.language, value+"-") {
+				return true
+			}
+		case RuleTypeCountry:
+			if country := ctx.resolveCountry(); country != "" && country == value {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (ctx *targetingContext) resolveCountry() string {
+	if ctx.country == nil {
+		country := ""
+		if ctx.geo != nil {
+			country = strings.ToUpper(ctx.geo.Country(ctx.ip))
+		}
+		ctx.country = &country
+	}
+	return *ctx.country
+}
+
+func visitorPlatforms(ua string) map[string]bool {
+	parsed := useragent.Parse(ua)
+	platforms := make(map[string]bool, 2)
+	switch parsed.OS {
+	case "iOS":
+		platforms[PlatformIOS] = true
+	case "Android":
+		platforms[PlatformAndroid] = true
+	}
+	switch parsed.Device {
+	case useragent.DeviceMobile, useragent.DeviceTablet:
+		platforms[PlatformMobile] = true
+	case useragent.DeviceDesktop:
+		platforms[PlatformDesktop] = true
+	}
+	return platforms
+}
+
+// primaryLanguage returns the lower-cased tag with the highest quality in an
+// Accept-Language header, keeping header order between equal weights.
+func primaryLanguage(acceptLanguage string) string {
+	type weighted struct {
+		tag string
+		q   float64
+	}
+
+	var languages []weighted
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || tag == "*" {
+			continue
+		}
+
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q > 0 {
+			languages = append(languages, weighted{tag, q})
+		}
+	}
+	if len(languages) == 0 {
+		return ""
+	}
+
+	sort.SliceStable(languages, func(i, j int) bool { return languages[i].q > languages[j].q })
+	return languages[0].tag
+}
+
+// normalizeRuleValues validates rule values and converts them to the form
+// used when matching.
+func normalizeRuleValues(ruleType string, values []string) ([]string, error) {
+	normalized := make([]string, 0, len(values))
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		switch ruleType {
+		case RuleTypePlatform:
+			value = strings.ToLower(value)
+			if !rulePlatforms[value] {
+				return nil, ErrRuleInvalidPlatform
+			}
+		case RuleTypeLanguage:
+			value = strings.ToLower(strings.ReplaceAll(value, "_", "-"))
+			if !languageTagPattern.MatchString(value) {
+				return nil, ErrRuleInvalidLanguage
+			}
+		case RuleTypeCountry:
+			value = strings.ToUpper(value)
+			if !countryCodePattern.MatchString(value) {
+				return nil, ErrRuleInvalidCountry
+			}
+		}
+		normalized = append(normalized, value)
+	}
+	return normalized, nil
+}
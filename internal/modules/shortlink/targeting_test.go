@@ -0,0 +1,80 @@
+package shortlink
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type countryResolverStub map[string]string
+
+func (s countryResolverStub) Country(ip string) string {
+	return s[ip]
+}
+
+const (
+	iPhoneUA  = "Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.0 Mobile/15E148 Safari/604.1"
+	androidUA = "Mozilla/5.0 (Linux; Android 14; Pixel 8) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0 Mobile Safari/537.36"
+	desktopUA = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0 Safari/537.36"
+)
+
+func TestMatchRule(t *testing.T) {
+	rules := []ShortenerLinkRuleModel{
+		{Type: RuleTypeCountry, Values: []string{"ID"}, DestinationURL: "https://example.id"},
+		{Type: RuleTypePlatform, Values: []string{PlatformIOS}, DestinationURL: "https://apps.apple.com/app"},
+		{Type: RuleTypePlatform, Values: []string{PlatformAndroid}, DestinationURL: "https://play.google.com/app"},
+		{Type: RuleTypeLanguage, Values: []string{"pt"}, DestinationURL: "https://example.com/pt"},
+	}
+	geo := countryResolverStub{"203.0.113.7": "ID"}
+
+	tests := []struct {
+		name     string
+		visit    Visit
+		expected string
+	}{
+		{name: "Country wins by order", visit: Visit{UserAgent: iPhoneUA, IP: "203.0.113.7"}, expected: "https://example.id"},
+		{name: "iOS", visit: Visit{UserAgent: iPhoneUA, IP: "198.51.100.1"}, expected: "https://apps.apple.com/app"},
+		{name: "Android", visit: Visit{UserAgent: androidUA}, expected: "https://play.google.com/app"},
+		{name: "Language variant", visit: Visit{UserAgent: desktopUA, AcceptLanguage: "en;q=0.5, pt-BR"}, expected: "https://example.com/pt"},
+		{name: "Secondary language ignored", visit: Visit{UserAgent: desktopUA, AcceptLanguage: "en-US,pt;q=0.9"}, expected: ""},
+		{name: "No match", visit: Visit{UserAgent: desktopUA}, expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := matchRule(rules, newTargetingContext(&tt.visit, geo))
+			if tt.expected == "" {
+				assert.Nil(t, rule)
+				return
+			}
+			assert.NotNil(t, rule)
+			assert.Equal(t, tt.expected, rule.DestinationURL)
+		})
+	}
+}
+
+func TestMatchRule_WithoutGeoIP(t *testing.T) {
+	rules := []ShortenerLinkRuleModel{{Type: RuleTypeCountry, Values: []string{"ID"}, DestinationURL: "https://example.id"}}
+	assert.Nil(t, matchRule(rules, newTargetingContext(&Visit{IP: "203.0.113.7"}, nil)))
+}
+
+func TestNormalizeRuleValues(t *testing.T) {
+	values, err := normalizeRuleValues(RuleTypePlatform, []string{"iOS", " Desktop "})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"ios", "desktop"}, values)
+
+	values, err = normalizeRuleValues(RuleTypeLanguage, []string{"pt_BR", "EN"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"pt-br", "en"}, values)
+
+	values, err = normalizeRuleValues(RuleTypeCountry, []string{"id"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"ID"}, values)
+
+	_, err = normalizeRuleValues(RuleTypePlatform, []string{"blackberry"})
+	assert.Equal(t, ErrRuleInvalidPlatform, err)
+	_, err = normalizeRuleValues(RuleTypeLanguage, []string{"english!"})
+	assert.Equal(t, ErrRuleInvalidLanguage, err)
+	_, err = normalizeRuleValues(RuleTypeCountry, []string{"IDN"})
+	assert.Equal(t, ErrRuleInvalidCountry, err)
+}
@@ -0,0 +1,51 @@
+package shortlink
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xcurvnubaim/njajal-gin-golang/internal/configs"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func hashPassword(password string) (string, error) {
+	bytes, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	return string(bytes), err
+}
+
+func verifyPassword(hashedPassword, password string) bool {
+	err := bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password))
+	return err == nil
+}
+
+// signUnlockToken issues the value of the unlock cookie as "<expiry>.<hmac>".
+// The signature covers the password hash, so changing the password revokes
+// every cookie issued before.
+func signUnlockToken(shortenerLink *ShortenerLinkModel, expiresAt time.Time) string {
+	exp := strconv.FormatInt(expiresAt.Unix(), 10)
+	return exp + "." + unlockSignature(shortenerLink, exp)
+}
+
+func verifyUnlockToken(shortenerLink *ShortenerLinkModel, token string, now time.Time) bool {
+	exp, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return false
+	}
+
+	expUnix, err := strconv.ParseInt(exp, 10, 64)
+	if err != nil || now.Unix() > expUnix {
+		return false
+	}
+
+	return hmac.Equal([]byte(signature), []byte(unlockSignature(shortenerLink, exp)))
+}
+
+func unlockSignature(shortenerLink *ShortenerLinkModel, exp string) string {
+	mac := hmac.New(sha256.New, []byte(configs.Config.JWT_SECRET))
+	mac.Write([]byte(shortenerLink.ID.String() + "|" + exp + "|" + shortenerLink.PasswordHash))
+	return hex.EncodeToString(mac.Sum(nil))
+}
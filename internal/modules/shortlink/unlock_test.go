@@ -0,0 +1,47 @@
+package shortlink
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnlockToken(t *testing.T) {
+	now := time.Now()
+	link := NewShortenerLink(uuid.New(), "https://example.com", "abc")
+	link.PasswordHash = "hash-1"
+
+	token := signUnlockToken(link, now.Add(time.Minute))
+
+	t.Run("Valid token", func(t *testing.T) {
+		assert.True(t, verifyUnlockToken(link, token, now))
+	})
+
+	t.Run("Expired token", func(t *testing.T) {
+		assert.False(t, verifyUnlockToken(link, token, now.Add(2*time.Minute)))
+	})
+
+	t.Run("Tampered expiry", func(t *testing.T) {
+		forged := signUnlockToken(link, now.Add(time.Minute))
+		forged = "9999999999" + forged[len(forged)-65:]
+		assert.False(t, verifyUnlockToken(link, forged, now))
+	})
+
+	t.Run("Other link", func(t *testing.T) {
+		other := NewShortenerLink(uuid.New(), "https://example.com", "def")
+		other.PasswordHash = link.PasswordHash
+		assert.False(t, verifyUnlockToken(other, token, now))
+	})
+
+	t.Run("Password changed", func(t *testing.T) {
+		changed := *link
+		changed.PasswordHash = "hash-2"
+		assert.False(t, verifyUnlockToken(&changed, token, now))
+	})
+
+	t.Run("Malformed token", func(t *testing.T) {
+		assert.False(t, verifyUnlockToken(link, "garbage", now))
+	})
+}
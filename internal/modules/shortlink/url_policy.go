@@ -0,0 +1,348 @@
+package shortlink
+
+import (
+	"bufio"
+	"errors"
+	"log"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/xcurvnubaim/njajal-gin-golang/internal/pkg/safehttp"
+)
+
+var (
+	ErrURLInvalid         = errors.New("Destination URL is invalid")
+	ErrURLScheme          = errors.New("Destination URL scheme is not allowed")
+	ErrURLPrivateHost     = errors.New("Destination URL points to a private or local address")
+	ErrURLBlockedDomain   = errors.New("Destination URL domain is blocked")
+	ErrURLSelfReferencing = errors.New("Destination URL cannot point to the short link domain")
+)
+
+var blockedHostSuffixes = []string{".localhost", ".local", ".internal", ".lan", ".home.arpa"}
+
+type URLPolicyOptions struct {
+	AllowedSchemes []string
+	// OwnHosts are the hosts short links are served from; links to them
+	// would redirect back into the shortener.
+	OwnHosts       []string
+	BlocklistFile  string
+	ReloadInterval time.Duration
+}
+
+// URLPolicy normalizes destination URLs and rejects unsafe ones. Blocked
+// domains come from an optional local file and from the blocked_domains
+// table; both are reloaded periodically so edits apply without a restart.
+type URLPolicy struct {
+	repository     IRepository
+	allowedSchemes map[string]struct{}
+	ownHosts       map[string]struct{}
+	blocklistFile  string
+	reloadInterval time.Duration
+
+	mu           sync.RWMutex
+	fileModTime  time.Time
+	fileDomains  map[string]struct{}
+	tableDomains map[string]struct{}
+	// brandedHosts are the verified branded domains, which serve short
+	// links just like ownHosts.
+	brandedHosts map[string]struct{}
+
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+func NewURLPolicy(repository IRepository, opts URLPolicyOptions) *URLPolicy {
+	schemes := opts.AllowedSchemes
+	if len(schemes) == 0 {
+		schemes = []string{"http", "https"}
+	}
+
+	p := &URLPolicy{
+		repository:     repository,
+		allowedSchemes: make(map[string]struct{}, len(schemes)),
+		ownHosts:       make(map[string]struct{}, len(opts.OwnHosts)),
+		blocklistFile:  opts.BlocklistFile,
+		reloadInterval: opts.ReloadInterval,
+		fileDomains:    map[string]struct{}{},
+		tableDomains:   map[string]struct{}{},
+		brandedHosts:   map[string]struct{}{},
+		done:           make(chan struct{}),
+	}
+	for _, scheme := range schemes {
+		p.allowedSchemes[strings.ToLower(scheme)] = struct{}{}
+	}
+	for _, host := range opts.OwnHosts {
+		if host = normalizeDomain(host); host != "" {
+			p.ownHosts[host] = struct{}{}
+		}
+	}
+	return p
+}
+
+func (p *URLPolicy) Start() {
+	p.Reload()
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+
+		ticker := time.NewTicker(p.reloadInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				p.Reload()
+			case <-p.done:
+				return
+			}
+		}
+	}()
+}
+
+func (p *URLPolicy) Stop() {
+	close(p.done)
+	p.wg.Wait()
+}
+
+// Reload re-reads the blocklist file when it changed on disk and refreshes
+// the blocked and branded domains managed through the API.
+func (p *URLPolicy) Reload() {
+	if p.blocklistFile != "" {
+		if err := p.reloadFile(); err != nil {
+			log.Println("Failed to reload domain blocklist file:", err)
+		}
+	}
+
+	if p.repository != nil {
+		blockedDomains, err := p.repository.GetAllBlockedDomains()
+		if err != nil {
+			log.Println("Failed to reload blocked domains:", err)
+			return
+		}
+		domains := make(map[string]struct{}, len(blockedDomains))
+		for _, blockedDomain := range blockedDomains {
+			domains[normalizeDomain(blockedDomain.Domain)] = struct{}{}
+		}
+
+		p.mu.Lock()
+		p.tableDomains = domains
+		p.mu.Unlock()
+
+		hostnames, err := p.repository.GetVerifiedDomainHostnames()
+		if err != nil {
+			log.Println("Failed to reload branded domains:", err)
+			return
+		}
+		brandedHosts := make(map[string]struct{}, len(hostnames))
+		for _, hostname := range hostnames {
+			brandedHosts[normalizeDomain(hostname)] = struct{}{}
+		}
+
+		p.mu.Lock()
+		p.brandedHosts = brandedHosts
+		p.mu.Unlock()
+	}
+}
+
+func (p *URLPolicy) reloadFile() error {
+	info, err := os.Stat(p.blocklistFile)
+	if err != nil {
+		return err
+	}
+
+	p.mu.RLock()
+	unchanged := info.ModTime().Equal(p.fileModTime)
+	p.mu.RUnlock()
+	if unchanged {
+		return nil
+	}
+
+	file, err := os.Open(p.blocklistFile)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	domains := make(map[string]struct{})
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		domains[normalizeDomain(line)] = struct{}{}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	p.fileDomains = domains
+	p.fileModTime = info.ModTime()
+	p.mu.Unlock()
+
+	log.Println("Loaded", len(domains), "blocked domains from", p.blocklistFile)
+	return nil
+}
+
+// Check returns the normalized form of rawURL, or the reason it is rejected.
+func (p *URLPolicy) Check(rawURL string) (string, error) {
+	parsed, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || parsed.Host == "" {
+		return "", ErrURLInvalid
+	}
+
+	parsed.Scheme = strings.ToLower(parsed.Scheme)
+	if _, ok := p.allowedSchemes[parsed.Scheme]; !ok {
+		return "", ErrURLScheme
+	}
+	if parsed.User != nil {
+		// user:pass@host is mostly used to disguise the real destination.
+		return "", ErrURLInvalid
+	}
+
+	host := normalizeDomain(parsed.Hostname())
+	if host == "" {
+		return "", ErrURLInvalid
+	}
+	if ip := parseIPv4Shorthand(host); ip != nil {
+		// Store shorthand such as 0x5db8.55330 in its usual form.
+		host = ip.String()
+	}
+	if isPrivateHost(host) {
+		return "", ErrURLPrivateHost
+	}
+	if _, ok := p.ownHosts[host]; ok || p.isBrandedHost(host) {
+		return "", ErrURLSelfReferencing
+	}
+	if p.isBlocked(host) {
+		return "", ErrURLBlockedDomain
+	}
+
+	port := parsed.Port()
+	if (parsed.Scheme == "http" && port == "80") || (parsed.Scheme == "https" && port == "443") {
+		port = ""
+	}
+	parsed.Host = host
+	if strings.Contains(host, ":") {
+		parsed.Host = "[" + host + "]"
+	}
+	if port != "" {
+		parsed.Host += ":" + port
+	}
+	if parsed.Path == "" {
+		parsed.Path = "/"
+	}
+
+	return parsed.String(), nil
+}
+
+func (p *URLPolicy) isBrandedHost(host string) bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	_, ok := p.brandedHosts[host]
+	return ok
+}
+
+// IsBlockedURL reports whether the host of rawURL is on a blocklist.
+func (p *URLPolicy) IsBlockedURL(rawURL string) bool {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	return p.isBlocked(normalizeDomain(parsed.Hostname()))
+}
+
+func (p *URLPolicy) isBlocked(host string) bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	// Walk up the labels so blocking example.com also blocks a.example.com.
+	for candidate := host; candidate != ""; {
+		if _, ok := p.fileDomains[candidate]; ok {
+			return true
+		}
+		if _, ok := p.tableDomains[candidate]; ok {
+			return true
+		}
+		_, parent, found := strings.Cut(candidate, ".")
+		if !found {
+			break
+		}
+		candidate = parent
+	}
+	return false
+}
+
+func isPrivateHost(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	for _, suffix := range blockedHostSuffixes {
+		if strings.HasSuffix(host, suffix) {
+			return true
+		}
+	}
+
+	ip := parseIPv4Shorthand(host)
+	if ip == nil {
+		ip = net.ParseIP(host)
+	}
+	return ip != nil && !safehttp.IsPublicIP(ip)
+}
+
+// parseIPv4Shorthand parses the IPv4 forms browsers accept besides the
+// dotted quad: fewer than four parts (127.1), and hex or octal parts
+// (0x7f.1, 0177.0.0.1, 2130706433). It returns nil for anything else.
+func parseIPv4Shorthand(host string) net.IP {
+	parts := strings.Split(strings.TrimSuffix(host, "."), ".")
+	if len(parts) > 4 {
+		return nil
+	}
+
+	var value uint64
+	for i, part := range parts {
+		n, err := parseIPv4Part(part)
+		if err != nil {
+			return nil
+		}
+		// The last part fills all the bytes the earlier parts left.
+		bits := 8
+		if i == len(parts)-1 {
+			bits = 8 * (5 - len(parts))
+		}
+		if n >= 1<<bits {
+			return nil
+		}
+		value = value<<bits | n
+	}
+
+	return net.IPv4(byte(value>>24), byte(value>>16), byte(value>>8), byte(value))
+}
+
+func parseIPv4Part(part string) (uint64, error) {
+	switch {
+	case part == "":
+		return 0, strconv.ErrSyntax
+	case strings.HasPrefix(part, "0x") || strings.HasPrefix(part, "0X"):
+		if len(part) == 2 {
+			return 0, nil
+		}
+		return strconv.ParseUint(part[2:], 16, 32)
+	case len(part) > 1 && part[0] == '0':
+		return strconv.ParseUint(part[1:], 8, 32)
+	default:
+		return strconv.ParseUint(part, 10, 32)
+	}
+}
+
+func normalizeDomain(domain string) string {
+	domain = strings.ToLower(strings.TrimSpace(domain))
+	domain = strings.TrimPrefix(domain, "*.")
+	return strings.TrimSuffix(domain, ".")
+}
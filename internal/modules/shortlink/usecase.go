@@ -1,90 +1,560 @@
 package shortlink
 
 import (
-	"crypto/rand"
-	"encoding/base64"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
 	"log"
+	"net"
+	"net/url"
 	"strings"
+	"time"
 
+	"github.com/gin-gonic/gin/binding"
+	"github.com/google/uuid"
+	"github.com/xcurvnubaim/njajal-gin-golang/internal/configs"
 	"github.com/xcurvnubaim/njajal-gin-golang/internal/modules/common"
+	"github.com/xcurvnubaim/njajal-gin-golang/internal/pkg/cache"
 	"github.com/xcurvnubaim/njajal-gin-golang/internal/pkg/e"
+	"github.com/xcurvnubaim/njajal-gin-golang/internal/pkg/geoip"
+	"github.com/xcurvnubaim/njajal-gin-golang/internal/pkg/qr"
 	"github.com/xcurvnubaim/njajal-gin-golang/internal/pkg/query"
+	"github.com/xcurvnubaim/njajal-gin-golang/internal/pkg/shortcode"
+	"github.com/xcurvnubaim/njajal-gin-golang/internal/pkg/useragent"
+	CustomValidator "github.com/xcurvnubaim/njajal-gin-golang/internal/pkg/validator"
+	"gorm.io/gorm"
 )
 
 type IUseCase interface {
-	CreateShortenerLink(data *CreateShortenerLinkRequestDTO) (*CreateShortenerLinkResponseDTO, e.ApiError)
-	GetOriginalURL(shortenerURL string) (*string, e.ApiError)
-	GetAllShortenerLink(queryParam *query.QueryParams) (*common.PaginationResponseDTO[GetAllShortenerLinksResponseDTO], e.ApiError)
+	CreateShortenerLink(userID uuid.UUID, data *CreateShortenerLinkRequestDTO) (*CreateShortenerLinkResponseDTO, e.ApiError)
+	GetOriginalURL(host, shortenerURL string, visit *Visit) (*Redirect, e.ApiError)
+	GetShortenerLinkPreview(host, shortenerURL string, unlockToken string) (*Preview, bool, e.ApiError)
+	GetAllShortenerLink(userID *uuid.UUID, queryParam *query.QueryParams) (*common.PaginationResponseDTO[GetAllShortenerLinksResponseDTO], e.ApiError)
+	BulkCreateShortenerLink(userID uuid.UUID, rows []BulkCreateShortenerLinkRowDTO) ([]BulkCreateShortenerLinkResultDTO, e.ApiError)
+	ExportShortenerLink(userID uuid.UUID, queryParam *query.QueryParams, fn func(GetShortenerLink) error) e.ApiError
+	UpdateShortenerLink(userID, id uuid.UUID, data *UpdateShortenerLinkRequestDTO) (*GetShortenerLink, e.ApiError)
+	GetShortenerLinkRules(userID, id uuid.UUID) (*GetShortenerLinkRulesResponseDTO, e.ApiError)
+	ReplaceShortenerLinkRules(userID, id uuid.UUID, data *ReplaceShortenerLinkRulesRequestDTO) (*GetShortenerLinkRulesResponseDTO, e.ApiError)
+	GetShortenerLinkVariants(userID, id uuid.UUID) (*GetShortenerLinkVariantsResponseDTO, e.ApiError)
+	ReplaceShortenerLinkVariants(userID, id uuid.UUID, data *ReplaceShortenerLinkVariantsRequestDTO) (*GetShortenerLinkVariantsResponseDTO, e.ApiError)
+	DeleteShortenerLink(userID, id uuid.UUID) e.ApiError
+	GetShortenerLinkHistory(userID, id uuid.UUID) (*GetShortenerLinkHistoryResponseDTO, e.ApiError)
+	RestoreShortenerLinkVersion(userID, id uuid.UUID, version int) (*GetShortenerLink, e.ApiError)
+	GetDeletedShortenerLinks(userID uuid.UUID) (*GetAllShortenerLinksResponseDTO, e.ApiError)
+	UndeleteShortenerLink(userID, id uuid.UUID) (*GetShortenerLink, e.ApiError)
+	GetShortenerLinkStats(userID, id uuid.UUID, data *GetShortenerLinkStatsRequestDTO) (*GetShortenerLinkStatsResponseDTO, e.ApiError)
+	GetPublicShortenerLinkStats(host, shortenerURL string) (*GetPublicShortenerLinkStatsResponseDTO, e.ApiError)
+	UnlockShortenerLink(host, shortenerURL string, data *UnlockShortenerLinkRequestDTO) (string, e.ApiError)
+	GetShortenerLinkQRCode(userID uuid.UUID, idOrShortenerURL string, data *GetShortenerLinkQRCodeRequestDTO) (*QRCode, e.ApiError)
+	GetCacheStats() (*GetCacheStatsResponseDTO, e.ApiError)
+	GetAllBlockedDomains() (*GetAllBlockedDomainsResponseDTO, e.ApiError)
+	CreateBlockedDomain(userID uuid.UUID, data *CreateBlockedDomainRequestDTO) (*GetBlockedDomain, e.ApiError)
+	DeleteBlockedDomain(id uuid.UUID) e.ApiError
+	CreateCampaign(userID uuid.UUID, data *CreateCampaignRequestDTO) (*GetCampaign, e.ApiError)
+	GetAllCampaigns(userID uuid.UUID) (*GetAllCampaignsResponseDTO, e.ApiError)
+	GetCampaign(userID, id uuid.UUID) (*GetCampaign, e.ApiError)
+	UpdateCampaign(userID, id uuid.UUID, data *UpdateCampaignRequestDTO) (*GetCampaign, e.ApiError)
+	DeleteCampaign(userID, id uuid.UUID) e.ApiError
+	GetCampaignStats(userID, id uuid.UUID, data *GetShortenerLinkStatsRequestDTO) (*GetCampaignStatsResponseDTO, e.ApiError)
+	CreateTag(userID uuid.UUID, data *CreateTagRequestDTO) (*GetTag, e.ApiError)
+	GetAllTags(userID uuid.UUID) (*GetAllTagsResponseDTO, e.ApiError)
+	UpdateTag(userID, id uuid.UUID, data *UpdateTagRequestDTO) (*GetTag, e.ApiError)
+	DeleteTag(userID, id uuid.UUID) e.ApiError
+	CreateFolder(userID uuid.UUID, data *CreateFolderRequestDTO) (*GetFolder, e.ApiError)
+	GetAllFolders(userID uuid.UUID) (*GetAllFoldersResponseDTO, e.ApiError)
+	UpdateFolder(userID, id uuid.UUID, data *UpdateFolderRequestDTO) (*GetFolder, e.ApiError)
+	DeleteFolder(userID, id uuid.UUID) e.ApiError
+	CreateDomain(userID uuid.UUID, data *CreateDomainRequestDTO) (*GetDomain, e.ApiError)
+	GetAllDomains(userID uuid.UUID) (*GetAllDomainsResponseDTO, e.ApiError)
+	GetDomain(userID, id uuid.UUID) (*GetDomain, e.ApiError)
+	VerifyDomain(userID, id uuid.UUID) (*GetDomain, e.ApiError)
+	DeleteDomain(userID, id uuid.UUID) e.ApiError
+	CreateBioPage(userID uuid.UUID, data *CreateBioPageRequestDTO) (*GetBioPage, e.ApiError)
+	GetAllBioPages(userID uuid.UUID) (*GetAllBioPagesResponseDTO, e.ApiError)
+	GetBioPage(userID, id uuid.UUID) (*GetBioPage, e.ApiError)
+	UpdateBioPage(userID, id uuid.UUID, data *UpdateBioPageRequestDTO) (*GetBioPage, e.ApiError)
+	DeleteBioPage(userID, id uuid.UUID) e.ApiError
+	ReplaceBioPageItems(userID, id uuid.UUID, data *ReplaceBioPageItemsRequestDTO) (*GetBioPage, e.ApiError)
+	GetPublicBioPage(slug string) (*BioPage, e.ApiError)
+	TrackBioPageItemClick(slug string, itemID uuid.UUID) (string, e.ApiError)
+	CreateAppScheme(userID uuid.UUID, data *CreateAppSchemeRequestDTO) (*GetAppScheme, e.ApiError)
+	GetAllAppSchemes(userID uuid.UUID) (*GetAllAppSchemesResponseDTO, e.ApiError)
+	DeleteAppScheme(userID, id uuid.UUID) e.ApiError
 }
 
 type useCase struct {
 	repository IRepository
+	recorder   IClickRecorder
+	counter    ClickCounter
+	generator  shortcode.CodeGenerator
+	urlPolicy  *URLPolicy
+	qrLogo     image.Image
+	geo        geoip.CountryResolver
+	verifier   *DomainVerifier
+	emitter    EventEmitter
+	// publicStats caches public stats pages by link ID.
+	publicStats *cache.LRU[uuid.UUID, *GetPublicShortenerLinkStatsResponseDTO]
 }
 
-func NewuseCase(repository IRepository) *useCase {
-	return &useCase{repository}
+func NewuseCase(repository IRepository, recorder IClickRecorder, counter ClickCounter, generator shortcode.CodeGenerator, urlPolicy *URLPolicy, qrLogo image.Image, geo geoip.CountryResolver, verifier *DomainVerifier, emitter EventEmitter) *useCase {
+	publicStats := cache.NewLRU[uuid.UUID, *GetPublicShortenerLinkStatsResponseDTO](DefaultPublicStatsCacheSize)
+	return &useCase{repository, recorder, counter, generator, urlPolicy, qrLogo, geo, verifier, emitter, publicStats}
 }
 
-func (uc *useCase) CreateShortenerLink(data *CreateShortenerLinkRequestDTO) (*CreateShortenerLinkResponseDTO, e.ApiError) {
-	if data.ShortenerURL == "" {
-		var maxRetry = 5
-		for i := 0; i < maxRetry; i++ {
-			data.ShortenerURL = uc.GenerateRandomShortenerURL(6)
-			check, _ := uc.repository.GetShortenerLinkByShortenerURL(data.ShortenerURL)
-			if check == nil {
-				break
-			}
-			if i == maxRetry-1 {
-				log.Println("Max retry generate shorten url reached")
-				return nil, e.NewApiError(500, "Failed to generate shortener URL")
-			}
+func (uc *useCase) CreateShortenerLink(userID uuid.UUID, data *CreateShortenerLinkRequestDTO) (*CreateShortenerLinkResponseDTO, e.ApiError) {
+	originalURL, errApi := uc.checkOriginalURL(data.OriginalURL)
+	if errApi != nil {
+		return nil, errApi
+	}
+
+	shortenerLinkModel := NewShortenerLink(userID, originalURL, data.ShortenerURL)
+	shortenerLinkModel.ActiveFrom = data.ActiveFrom
+	shortenerLinkModel.ExpiresAt = data.ExpiresAt
+	shortenerLinkModel.MaxClicks = data.MaxClicks
+	shortenerLinkModel.ForwardQuery = data.ForwardQuery
+	shortenerLinkModel.StatsPublic = data.StatsPublic
+	shortenerLinkModel.PreviewTitle = data.PreviewTitle
+	shortenerLinkModel.PreviewDescription = data.PreviewDescription
+	shortenerLinkModel.PreviewImageURL = data.PreviewImageURL
+	if data.DeepLink != nil {
+		if shortenerLinkModel.DeepLink, errApi = uc.checkDeepLink(userID, data.DeepLink); errApi != nil {
+			return nil, errApi
 		}
-	} else {
-		check, _ := uc.repository.GetShortenerLinkByShortenerURL(data.ShortenerURL)
-		if check != nil {
-			return nil, e.NewApiError(400, "Shortener URL already exists")
+	}
+	if data.RedirectStatus != nil {
+		shortenerLinkModel.RedirectStatus = *data.RedirectStatus
+	}
+	if errApi := validateSchedule(shortenerLinkModel); errApi != nil {
+		return nil, errApi
+	}
+
+	if data.CampaignID != nil {
+		campaignID, errApi := uc.getOwnedCampaignID(userID, *data.CampaignID)
+		if errApi != nil {
+			return nil, errApi
 		}
+		shortenerLinkModel.CampaignID = campaignID
 	}
 
-	shortenerLinkModel := NewShortenerLink(data.OriginalURL, data.ShortenerURL)
-	err := uc.repository.CreateShortenerLink(shortenerLinkModel)
-	if err != nil {
-		return nil, e.NewApiError(500, err.Error())
+	if data.FolderID != nil {
+		folderID, errApi := uc.getOwnedFolderID(userID, *data.FolderID)
+		if errApi != nil {
+			return nil, errApi
+		}
+		shortenerLinkModel.FolderID = folderID
+	}
+
+	var domain *DomainModel
+	if data.DomainID != nil {
+		if domain, errApi = uc.getOwnedVerifiedDomain(userID, *data.DomainID); errApi != nil {
+			return nil, errApi
+		}
+		shortenerLinkModel.DomainID = &domain.ID
 	}
 
+	tags, errApi := uc.resolveTags(userID, data.Tags)
+	if errApi != nil {
+		return nil, errApi
+	}
+
+	if data.Password != nil && *data.Password != "" {
+		hashedPassword, err := hashPassword(*data.Password)
+		if err != nil {
+			log.Println(err.Error())
+			return nil, e.NewApiError(500, fmt.Sprintf("Internal Server Error (%d)", e.ERROR_BCRYPT_HASH_FAILED))
+		}
+		shortenerLinkModel.PasswordHash = hashedPassword
+	}
+
+	if errApi := uc.insertShortenerLink(uc.repository.CreateShortenerLink, shortenerLinkModel, data.ShortenerURL == ""); errApi != nil {
+		return nil, errApi
+	}
+	// Set after the insert so GORM does not try to save the domain too.
+	shortenerLinkModel.Domain = domain
+
+	if len(tags) > 0 {
+		if err := uc.repository.ReplaceShortenerLinkTags(shortenerLinkModel.ID, tagIDs(tags)); err != nil {
+			return nil, e.NewApiError(500, err.Error())
+		}
+		shortenerLinkModel.Tags = tags
+	}
+
+	uc.recordRevision(NewShortenerLinkRevision(shortenerLinkModel, userID, RevisionActionCreate))
+	uc.emit(userID, EventLinkCreated, toGetShortenerLink(shortenerLinkModel))
+
 	return &CreateShortenerLinkResponseDTO{
-		OriginalURL:  shortenerLinkModel.OriginalURL,
-		ShortenerURL: shortenerLinkModel.ShortenerURL,
+		OriginalURL:        shortenerLinkModel.OriginalURL,
+		ShortenerURL:       shortenerLinkModel.ShortenerURL,
+		ActiveFrom:         formatTime(shortenerLinkModel.ActiveFrom),
+		ExpiresAt:          formatTime(shortenerLinkModel.ExpiresAt),
+		MaxClicks:          shortenerLinkModel.MaxClicks,
+		IsProtected:        shortenerLinkModel.IsProtected(),
+		RedirectStatus:     shortenerLinkModel.RedirectStatus,
+		ForwardQuery:       shortenerLinkModel.ForwardQuery,
+		StatsPublic:        shortenerLinkModel.StatsPublic,
+		CampaignID:         formatUUID(shortenerLinkModel.CampaignID),
+		FolderID:           formatUUID(shortenerLinkModel.FolderID),
+		DomainID:           formatUUID(shortenerLinkModel.DomainID),
+		ShortURL:           shortURL(shortenerLinkModel),
+		Tags:               tagNames(tags),
+		PreviewTitle:       shortenerLinkModel.PreviewTitle,
+		PreviewDescription: shortenerLinkModel.PreviewDescription,
+		PreviewImageURL:    shortenerLinkModel.PreviewImageURL,
+		DeepLink:           toDeepLinkDTO(shortenerLinkModel.DeepLink),
 	}, nil
 }
 
-func (uc *useCase) GenerateRandomShortenerURL(length int) string {
-	// Generate random bytes
-	bytes := make([]byte, length)
-	_, err := rand.Read(bytes)
+func (uc *useCase) checkOriginalURL(originalURL string) (string, e.ApiError) {
+	if uc.urlPolicy == nil {
+		return originalURL, nil
+	}
+
+	normalized, err := uc.urlPolicy.Check(originalURL)
+	if err != nil {
+		return "", e.NewApiError(400, "Destination URL is not allowed: "+err.Error())
+	}
+	return normalized, nil
+}
+
+func validateSchedule(shortenerLink *ShortenerLinkModel) e.ApiError {
+	if shortenerLink.ExpiresAt == nil {
+		return nil
+	}
+
+	if !shortenerLink.ExpiresAt.After(time.Now()) {
+		return e.NewApiError(400, "expires_at must be in the future")
+	}
+
+	if shortenerLink.ActiveFrom != nil && !shortenerLink.ExpiresAt.After(*shortenerLink.ActiveFrom) {
+		return e.NewApiError(400, "expires_at must be after active_from")
+	}
+
+	return nil
+}
+
+// applySchedule sets or clears the schedule fields given in data and reports
+// whether any of them was part of the request.
+func applySchedule(shortenerLink *ShortenerLinkModel, data *UpdateShortenerLinkRequestDTO) (bool, e.ApiError) {
+	if (data.ActiveFrom != nil && data.ClearActiveFrom) ||
+		(data.ExpiresAt != nil && data.ClearExpiresAt) ||
+		(data.MaxClicks != nil && data.ClearMaxClicks) {
+		return false, e.NewApiError(400, "A schedule field cannot be set and cleared at once")
+	}
+
+	changed := false
+	if data.ActiveFrom != nil || data.ClearActiveFrom {
+		shortenerLink.ActiveFrom = data.ActiveFrom
+		changed = true
+	}
+	if data.ExpiresAt != nil || data.ClearExpiresAt {
+		shortenerLink.ExpiresAt = data.ExpiresAt
+		changed = true
+	}
+	if data.MaxClicks != nil || data.ClearMaxClicks {
+		shortenerLink.MaxClicks = data.MaxClicks
+		changed = true
+	}
+	if !changed {
+		return false, nil
+	}
+
+	return true, validateSchedule(shortenerLink)
+}
+
+// insertShortenerLink relies on the unique index on shortener_url instead of
+// checking availability first, so concurrent requests cannot claim the same
+// code. Generated codes are retried on conflict; custom aliases are not.
+func (uc *useCase) insertShortenerLink(create func(*ShortenerLinkModel) error, shortenerLink *ShortenerLinkModel, generate bool) e.ApiError {
+	for attempt := 1; ; attempt++ {
+		if generate {
+			code, err := uc.generator.Generate()
+			if err != nil {
+				log.Println(err)
+				return e.NewApiError(500, "Failed to generate shortener URL")
+			}
+			shortenerLink.ShortenerURL = code
+		}
+
+		err := create(shortenerLink)
+		if err == nil {
+			return nil
+		}
+		if !errors.Is(err, gorm.ErrDuplicatedKey) {
+			return e.NewApiError(500, err.Error())
+		}
+		if !generate {
+			return e.NewApiError(400, "Shortener URL already exists")
+		}
+		if attempt == maxGenerateAttempts {
+			log.Println("Max retry generate shorten url reached")
+			return e.NewApiError(500, "Failed to generate shortener URL")
+		}
+	}
+}
+
+// BulkCreateShortenerLink creates every row inside one transaction. Each row
+// runs in its own savepoint so a rejected row is reported in its result
+// without rolling back the others; unexpected database errors abort the batch.
+func (uc *useCase) BulkCreateShortenerLink(userID uuid.UUID, rows []BulkCreateShortenerLinkRowDTO) ([]BulkCreateShortenerLinkResultDTO, e.ApiError) {
+	var results []BulkCreateShortenerLinkResultDTO
+	var created []*ShortenerLinkModel
+	var errAbort e.ApiError
+
+	err := uc.repository.Transaction(func(tx IRepository) error {
+		create := func(shortenerLink *ShortenerLinkModel) error {
+			return tx.Transaction(func(rowTx IRepository) error {
+				return rowTx.CreateShortenerLink(shortenerLink)
+			})
+		}
+
+		results = make([]BulkCreateShortenerLinkResultDTO, 0, len(rows))
+		created = make([]*ShortenerLinkModel, 0, len(rows))
+		for _, row := range rows {
+			result := BulkCreateShortenerLinkResultDTO{OriginalURL: row.OriginalURL}
+
+			shortenerLink, errApi := uc.newBulkShortenerLink(userID, &row)
+			if errApi == nil {
+				errApi = uc.insertShortenerLink(create, shortenerLink, row.ShortenerURL == "")
+			}
+			if errApi != nil {
+				if errApi.Code() >= 500 {
+					errAbort = errApi
+					return errApi
+				}
+				errMsg := errApi.Error()
+				result.Error = &errMsg
+			} else {
+				result.OriginalURL = shortenerLink.OriginalURL
+				result.ShortenerURL = shortenerLink.ShortenerURL
+				created = append(created, shortenerLink)
+			}
+
+			results = append(results, result)
+		}
+		return nil
+	})
+	if errAbort != nil {
+		return nil, errAbort
+	}
 	if err != nil {
-		panic(err)
+		return nil, e.NewApiError(500, err.Error())
+	}
+
+	for _, shortenerLink := range created {
+		uc.recordRevision(NewShortenerLinkRevision(shortenerLink, userID, RevisionActionCreate))
+		uc.emit(userID, EventLinkCreated, toGetShortenerLink(shortenerLink))
 	}
 
-	// Encode bytes to a base64 string and clean it for URL use
-	shortURL := base64.URLEncoding.EncodeToString(bytes)
-	shortURL = strings.TrimRight(shortURL, "=") // Remove padding
-	shortURL = shortURL[:length]                // Trim to the desired length
+	return results, nil
+}
+
+func (uc *useCase) newBulkShortenerLink(userID uuid.UUID, row *BulkCreateShortenerLinkRowDTO) (*ShortenerLinkModel, e.ApiError) {
+	originalURL, errApi := uc.checkOriginalURL(row.OriginalURL)
+	if errApi != nil {
+		return nil, errApi
+	}
+
+	shortenerLink := NewShortenerLink(userID, originalURL, row.ShortenerURL)
+	shortenerLink.ExpiresAt = row.ExpiresAt
+	if errApi := validateSchedule(shortenerLink); errApi != nil {
+		return nil, errApi
+	}
 
-	return shortURL
+	return shortenerLink, nil
 }
 
-func (uc *useCase) GetOriginalURL(shortenerURL string) (*string, e.ApiError) {
-	shortenerLink, err := uc.repository.GetShortenerLinkByShortenerURL(shortenerURL)
+// GetOriginalURL resolves a short code requested on host to its destination.
+// When visit is not nil the click is queued on the recorder for analytics,
+// unless the visit asks to skip it.
+func (uc *useCase) GetOriginalURL(host, shortenerURL string, visit *Visit) (*Redirect, e.ApiError) {
+	shortenerLink, err := uc.getShortenerLinkByHost(host, shortenerURL)
 	if err != nil {
 		return nil, e.NewApiError(400, "Shortener URL not found")
 	}
 
-	return &shortenerLink.OriginalURL, nil
+	now := time.Now()
+	if err := shortenerLink.CheckAvailability(now); err != nil {
+		return nil, e.NewApiError(410, err.Error())
+	}
+
+	if shortenerLink.IsProtected() && (visit == nil || !verifyUnlockToken(shortenerLink, visit.UnlockToken, now)) {
+		return nil, e.NewApiError(401, ErrLinkLocked.Error())
+	}
+
+	destination := shortenerLink.OriginalURL
+	var variant *ShortenerLinkVariantModel
+	if visit != nil {
+		// Targeting rules take precedence; a split test only applies to
+		// visitors no rule picked out.
+		var rule *ShortenerLinkRuleModel
+		if len(shortenerLink.Rules) > 0 {
+			rule = matchRule(shortenerLink.Rules, newTargetingContext(visit, uc.geo))
+		}
+		if rule != nil {
+			destination = rule.DestinationURL
+		} else if variant = pickVariant(shortenerLink.Variants, visit.VariantID, randomIntN); variant != nil {
+			destination = variant.DestinationURL
+		}
+		if shortenerLink.ForwardQuery {
+			destination = mergeQuery(destination, visit.Query)
+		}
+	}
+	if shortenerLink.Campaign != nil {
+		// Campaign UTM values are defaults: the destination and the
+		// forwarded query win over them.
+		destination = mergeQuery(destination, shortenerLink.Campaign.UTMParams())
+	}
+
+	// Domains blocked after the link was saved stop redirecting too.
+	if uc.urlPolicy != nil && uc.urlPolicy.IsBlockedURL(destination) {
+		return nil, e.NewApiError(410, ErrLinkBlocked.Error())
+	}
+
+	if visit != nil && !visit.SkipClick {
+		// Limited links need an atomic check in the database; the rest are
+		// counted off the hot path and flushed periodically.
+		if shortenerLink.MaxClicks != nil {
+			consumed, err := uc.repository.IncrementShortenerLinkClickCount(shortenerLink)
+			if err != nil {
+				return nil, e.NewApiError(500, err.Error())
+			}
+			if !consumed {
+				return nil, e.NewApiError(410, ErrLinkExhausted.Error())
+			}
+		} else if err := uc.counter.IncrBy(shortenerLink.ID, 1); err != nil {
+			log.Println("Failed to increment click counter:", err)
+		}
+
+		uc.recordClick(shortenerLink, visit, variant)
+	}
+
+	status := shortenerLink.RedirectStatus
+	if status == 0 {
+		// Cached entries written before redirect_status existed.
+		status = DefaultRedirectStatus
+	}
+
+	redirect := &Redirect{
+		URL:    destination,
+		Status: status,
+		MaxAge: shortenerLink.RedirectMaxAge(now),
+	}
+	if variant != nil {
+		redirect.VariantID = variant.ID.String()
+	}
+	if visit != nil && shortenerLink.DeepLink.IsSet() {
+		appURL, storeURL := shortenerLink.DeepLink.For(visitorPlatforms(visit.UserAgent))
+		if appURL != "" && uc.isAllowedAppURL(shortenerLink.UserID, appURL) {
+			redirect.AppURL = appURL
+			if storeURL != "" {
+				redirect.URL = storeURL
+			}
+		}
+	}
+	return redirect, nil
+}
+
+// GetShortenerLinkPreview returns the card of a link for unfurlers and the
+// preview page, along with whether the owner set any preview metadata. The
+// lookup does not count as a click.
+func (uc *useCase) GetShortenerLinkPreview(host, shortenerURL string, unlockToken string) (*Preview, bool, e.ApiError) {
+	shortenerLink, err := uc.getShortenerLinkByHost(host, shortenerURL)
+	if err != nil {
+		return nil, false, e.NewApiError(400, "Shortener URL not found")
+	}
+
+	now := time.Now()
+	if err := shortenerLink.CheckAvailability(now); err != nil {
+		return nil, false, e.NewApiError(410, err.Error())
+	}
+
+	// The destination of a protected link stays hidden until it is unlocked.
+	if shortenerLink.IsProtected() && !verifyUnlockToken(shortenerLink, unlockToken, now) {
+		return nil, false, e.NewApiError(401, ErrLinkLocked.Error())
+	}
+
+	preview := &Preview{
+		ShortURL:       shortURL(shortenerLink),
+		DestinationURL: shortenerLink.OriginalURL,
+		Title:          shortenerLink.PreviewTitle,
+		Description:    shortenerLink.PreviewDescription,
+		ImageURL:       shortenerLink.PreviewImageURL,
+	}
+	return preview, shortenerLink.HasPreview(), nil
+}
+
+// UnlockShortenerLink checks the password of a protected link and returns a
+// signed token to be stored in the unlock cookie.
+func (uc *useCase) UnlockShortenerLink(host, shortenerURL string, data *UnlockShortenerLinkRequestDTO) (string, e.ApiError) {
+	shortenerLink, err := uc.getShortenerLinkByHost(host, shortenerURL)
+	if err != nil {
+		return "", e.NewApiError(404, "Shortener URL not found")
+	}
+
+	if !shortenerLink.IsProtected() {
+		return "", e.NewApiError(400, "Shortener link is not password protected")
+	}
+
+	if !verifyPassword(shortenerLink.PasswordHash, data.Password) {
+		return "", e.NewApiError(401, "Password is incorrect")
+	}
+
+	return signUnlockToken(shortenerLink, time.Now().Add(UnlockTokenTTL)), nil
+}
+
+// getShortenerLinkByHost looks a code up on the branded domain the request
+// was made to. Requests to BASE_URL, or to any host that is not a verified
+// domain, resolve among the links without a domain.
+func (uc *useCase) getShortenerLinkByHost(host, shortenerURL string) (*ShortenerLinkModel, error) {
+	var domainID *uuid.UUID
+	if hostname := requestHostname(host); hostname != "" && hostname != baseHostname() {
+		domain, err := uc.repository.GetVerifiedDomainByHostname(hostname)
+		if err == nil {
+			domainID = &domain.ID
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+	}
+	return uc.repository.GetShortenerLinkByShortenerURL(domainID, shortenerURL)
+}
+
+func (uc *useCase) recordClick(shortenerLink *ShortenerLinkModel, visit *Visit, variant *ShortenerLinkVariantModel) {
+	ua := useragent.Parse(visit.UserAgent)
+	click := NewShortenerLinkClick(
+		shortenerLink.ID,
+		time.Now(),
+		visit.Referrer,
+		visit.UserAgent,
+		hashIP(visit.IP),
+		ua.Device,
+		ua.Browser,
+		ua.OS,
+	)
+	if variant != nil {
+		click.VariantID = &variant.ID
+	}
+	uc.recorder.Record(click)
+	uc.emit(shortenerLink.UserID, EventLinkClicked, newClickEvent(shortenerLink, click))
+}
+
+// hashIP keeps unique visitor counts possible without storing raw addresses.
+func hashIP(ip string) string {
+	if ip == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(configs.Config.IP_HASH_SALT + ip))
+	return hex.EncodeToString(sum[:])
 }
 
-func (uc *useCase) GetAllShortenerLink(queryParam *query.QueryParams) (*common.PaginationResponseDTO[GetAllShortenerLinksResponseDTO], e.ApiError) {
-	shortenerLinks, err := uc.repository.GetAllShortenerLink(queryParam.ApplyQuery)
+// GetAllShortenerLink lists the links owned by userID, or every link when userID is nil.
+func (uc *useCase) GetAllShortenerLink(userID *uuid.UUID, queryParam *query.QueryParams) (*common.PaginationResponseDTO[GetAllShortenerLinksResponseDTO], e.ApiError) {
+	scopeOwner := shortenerLinkScope(userID, queryParam)
+
+	shortenerLinks, err := uc.repository.GetAllShortenerLink(func(db *gorm.DB) *gorm.DB {
+		return queryParam.ApplyQuery(scopeOwner(db)).Preload("Tags", tagsByName)
+	})
 	if err != nil {
 		return nil, e.NewApiError(500, err.Error())
 	}
@@ -92,15 +562,12 @@ func (uc *useCase) GetAllShortenerLink(queryParam *query.QueryParams) (*common.P
 	var response common.PaginationResponseDTO[GetAllShortenerLinksResponseDTO]
 	data := make([]GetShortenerLink, 0)
 	for _, shortenerLink := range shortenerLinks {
-		data = append(data, GetShortenerLink{
-			ID: 		 shortenerLink.ID.String(),
-			OriginalURL:  shortenerLink.OriginalURL,
-			ShortenerURL: shortenerLink.ShortenerURL,
-			CreatedAt:    shortenerLink.CreatedAt.Format("2006-01-02 15:04:05"),
-		})
+		data = append(data, toGetShortenerLink(shortenerLink))
 	}
 
-	totalCount, err := uc.repository.CountShortenerLink(queryParam.ApplyQuery)
+	totalCount, err := uc.repository.CountShortenerLink(func(db *gorm.DB) *gorm.DB {
+		return queryParam.ApplyCountQuery(scopeOwner(db))
+	})
 	if err != nil {
 		return nil, e.NewApiError(500, err.Error())
 	}
@@ -112,4 +579,1577 @@ func (uc *useCase) GetAllShortenerLink(queryParam *query.QueryParams) (*common.P
 	response.Meta = queryParam.NewPaginationMeta(int(totalCount))
 
 	return &response, nil
-}
\ No newline at end of file
+}
+
+// ExportShortenerLink passes every link matching the list filters to fn,
+// ignoring pagination. Rows are streamed from the database one at a time.
+func (uc *useCase) ExportShortenerLink(userID uuid.UUID, queryParam *query.QueryParams, fn func(GetShortenerLink) error) e.ApiError {
+	scopeOwner := shortenerLinkScope(&userID, queryParam)
+
+	err := uc.repository.EachShortenerLink(func(db *gorm.DB) *gorm.DB {
+		return queryParam.ApplyQuery(scopeOwner(db))
+	}, func(shortenerLink *ShortenerLinkModel) error {
+		return fn(toGetShortenerLink(shortenerLink))
+	})
+	if err != nil {
+		return e.NewApiError(500, err.Error())
+	}
+
+	return nil
+}
+
+// shortenerLinkScope restricts a listing to userID's links, or every link when
+// userID is nil. Expired links are hidden unless the caller filters on
+// is_expired explicitly. It also wires up the tag= and folder= filters, which
+// need subqueries rather than a column comparison.
+func shortenerLinkScope(userID *uuid.UUID, queryParam *query.QueryParams) func(db *gorm.DB) *gorm.DB {
+	filterExpired := false
+	if queryParam.Filters != nil {
+		_, filterExpired = (*queryParam.Filters)["is_expired"]
+	}
+
+	queryParam.FilterScopes = map[string]query.FilterScope{
+		"tag":    filterByTags,
+		"folder": filterByFolder,
+	}
+
+	return func(db *gorm.DB) *gorm.DB {
+		if userID != nil {
+			db = db.Where("user_id = ?", *userID)
+		}
+		if !filterExpired {
+			db = db.Where("is_expired = ?", false)
+		}
+		return db
+	}
+}
+
+func (uc *useCase) UpdateShortenerLink(userID, id uuid.UUID, data *UpdateShortenerLinkRequestDTO) (*GetShortenerLink, e.ApiError) {
+	shortenerLink, errApi := uc.getOwnedShortenerLink(userID, id)
+	if errApi != nil {
+		return nil, errApi
+	}
+
+	var resetColumns []string
+
+	if data.ShortenerURL != nil {
+		shortenerLink.ShortenerURL = *data.ShortenerURL
+	}
+
+	if data.OriginalURL != nil {
+		originalURL, errApi := uc.checkOriginalURL(*data.OriginalURL)
+		if errApi != nil {
+			return nil, errApi
+		}
+		if originalURL != shortenerLink.OriginalURL {
+			// Queue the new destination for the next health check.
+			shortenerLink.IsBroken = false
+			shortenerLink.HealthCheckedAt = nil
+			resetColumns = append(resetColumns, healthResetColumns...)
+		}
+		shortenerLink.OriginalURL = originalURL
+	}
+
+	if changed, errApi := applySchedule(shortenerLink, data); errApi != nil {
+		return nil, errApi
+	} else if changed {
+		// Let the sweeper decide again whether the new schedule is expired.
+		shortenerLink.IsExpired = false
+		resetColumns = append(resetColumns, "is_expired")
+	}
+
+	if data.RedirectStatus != nil {
+		shortenerLink.RedirectStatus = *data.RedirectStatus
+	}
+
+	if data.ForwardQuery != nil {
+		shortenerLink.ForwardQuery = *data.ForwardQuery
+	}
+
+	if data.StatsPublic != nil {
+		shortenerLink.StatsPublic = *data.StatsPublic
+	}
+
+	if data.CampaignID != nil {
+		shortenerLink.CampaignID = nil
+		if *data.CampaignID != "" {
+			campaignID, errApi := uc.getOwnedCampaignID(userID, *data.CampaignID)
+			if errApi != nil {
+				return nil, errApi
+			}
+			shortenerLink.CampaignID = campaignID
+		}
+	}
+
+	if data.PreviewTitle != nil {
+		shortenerLink.PreviewTitle = *data.PreviewTitle
+	}
+	if data.PreviewDescription != nil {
+		shortenerLink.PreviewDescription = *data.PreviewDescription
+	}
+	if data.PreviewImageURL != nil {
+		shortenerLink.PreviewImageURL = *data.PreviewImageURL
+	}
+
+	if data.DeepLink != nil {
+		if shortenerLink.DeepLink, errApi = uc.checkDeepLink(userID, data.DeepLink); errApi != nil {
+			return nil, errApi
+		}
+	}
+
+	if data.FolderID != nil {
+		shortenerLink.FolderID = nil
+		if *data.FolderID != "" {
+			folderID, errApi := uc.getOwnedFolderID(userID, *data.FolderID)
+			if errApi != nil {
+				return nil, errApi
+			}
+			shortenerLink.FolderID = folderID
+		}
+	}
+
+	var tags []TagModel
+	if data.Tags != nil {
+		if tags, errApi = uc.resolveTags(userID, *data.Tags); errApi != nil {
+			return nil, errApi
+		}
+	}
+
+	if data.Password != nil {
+		shortenerLink.PasswordHash = ""
+		if *data.Password != "" {
+			hashedPassword, err := hashPassword(*data.Password)
+			if err != nil {
+				log.Println(err.Error())
+				return nil, e.NewApiError(500, fmt.Sprintf("Internal Server Error (%d)", e.ERROR_BCRYPT_HASH_FAILED))
+			}
+			shortenerLink.PasswordHash = hashedPassword
+		}
+	}
+
+	if err := uc.repository.UpdateShortenerLink(shortenerLink, resetColumns...); err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, e.NewApiError(400, "Shortener URL already exists")
+		}
+		return nil, e.NewApiError(500, err.Error())
+	}
+
+	if data.Tags != nil {
+		if err := uc.repository.ReplaceShortenerLinkTags(shortenerLink.ID, tagIDs(tags)); err != nil {
+			return nil, e.NewApiError(500, err.Error())
+		}
+		shortenerLink.Tags = tags
+	}
+
+	uc.recordRevision(NewShortenerLinkRevision(shortenerLink, userID, RevisionActionUpdate))
+
+	res := toGetShortenerLink(shortenerLink)
+	uc.emit(userID, EventLinkUpdated, res)
+	return &res, nil
+}
+
+func (uc *useCase) GetShortenerLinkRules(userID, id uuid.UUID) (*GetShortenerLinkRulesResponseDTO, e.ApiError) {
+	shortenerLink, errApi := uc.getOwnedShortenerLink(userID, id)
+	if errApi != nil {
+		return nil, errApi
+	}
+
+	rules, err := uc.repository.GetShortenerLinkRules(shortenerLink.ID)
+	if err != nil {
+		return nil, e.NewApiError(500, err.Error())
+	}
+
+	return toGetShortenerLinkRules(rules), nil
+}
+
+func (uc *useCase) ReplaceShortenerLinkRules(userID, id uuid.UUID, data *ReplaceShortenerLinkRulesRequestDTO) (*GetShortenerLinkRulesResponseDTO, e.ApiError) {
+	shortenerLink, errApi := uc.getOwnedShortenerLink(userID, id)
+	if errApi != nil {
+		return nil, errApi
+	}
+
+	rules := make([]*ShortenerLinkRuleModel, 0, len(data.Rules))
+	for i, rule := range data.Rules {
+		values, err := normalizeRuleValues(rule.Type, rule.Values)
+		if err != nil {
+			return nil, e.NewApiError(400, fmt.Sprintf("rules[%d]: %s", i, err.Error()))
+		}
+		destinationURL, errApi := uc.checkOriginalURL(rule.DestinationURL)
+		if errApi != nil {
+			return nil, e.NewApiError(400, fmt.Sprintf("rules[%d]: %s", i, errApi.Error()))
+		}
+		rules = append(rules, NewShortenerLinkRule(shortenerLink.ID, i, rule.Type, values, destinationURL))
+	}
+
+	if err := uc.repository.ReplaceShortenerLinkRules(shortenerLink.ID, rules); err != nil {
+		return nil, e.NewApiError(500, err.Error())
+	}
+
+	shortenerLink.Rules = make([]ShortenerLinkRuleModel, 0, len(rules))
+	for _, rule := range rules {
+		shortenerLink.Rules = append(shortenerLink.Rules, *rule)
+	}
+	uc.recordRevision(NewShortenerLinkRevision(shortenerLink, userID, RevisionActionUpdate))
+
+	return toGetShortenerLinkRules(rules), nil
+}
+
+func (uc *useCase) GetShortenerLinkVariants(userID, id uuid.UUID) (*GetShortenerLinkVariantsResponseDTO, e.ApiError) {
+	shortenerLink, errApi := uc.getOwnedShortenerLink(userID, id)
+	if errApi != nil {
+		return nil, errApi
+	}
+
+	variants, err := uc.repository.GetShortenerLinkVariants(shortenerLink.ID)
+	if err != nil {
+		return nil, e.NewApiError(500, err.Error())
+	}
+
+	return toGetShortenerLinkVariants(variants), nil
+}
+
+func (uc *useCase) ReplaceShortenerLinkVariants(userID, id uuid.UUID, data *ReplaceShortenerLinkVariantsRequestDTO) (*GetShortenerLinkVariantsResponseDTO, e.ApiError) {
+	shortenerLink, errApi := uc.getOwnedShortenerLink(userID, id)
+	if errApi != nil {
+		return nil, errApi
+	}
+
+	if len(data.Variants) == 1 {
+		return nil, e.NewApiError(400, "A split test needs at least two variants")
+	}
+
+	current, err := uc.repository.GetShortenerLinkVariants(shortenerLink.ID)
+	if err != nil {
+		return nil, e.NewApiError(500, err.Error())
+	}
+	existing := make(map[string]*ShortenerLinkVariantModel, len(current))
+	for _, variant := range current {
+		existing[variant.ID.String()] = variant
+	}
+
+	variants := make([]*ShortenerLinkVariantModel, 0, len(data.Variants))
+	for i, item := range data.Variants {
+		destinationURL, errApi := uc.checkOriginalURL(item.DestinationURL)
+		if errApi != nil {
+			return nil, e.NewApiError(400, fmt.Sprintf("variants[%d]: %s", i, errApi.Error()))
+		}
+
+		variant := NewShortenerLinkVariant(shortenerLink.ID, i, item.Name, destinationURL, item.Weight)
+		if item.ID != nil {
+			previous, ok := existing[*item.ID]
+			if !ok {
+				return nil, e.NewApiError(400, fmt.Sprintf("variants[%d]: unknown variant id", i))
+			}
+			delete(existing, *item.ID)
+			variant.BaseModels = previous.BaseModels
+			variant.UpdatedAt = time.Now()
+		}
+		variants = append(variants, variant)
+	}
+
+	if err := uc.repository.ReplaceShortenerLinkVariants(shortenerLink.ID, variants); err != nil {
+		return nil, e.NewApiError(500, err.Error())
+	}
+
+	shortenerLink.Variants = make([]ShortenerLinkVariantModel, 0, len(variants))
+	for _, variant := range variants {
+		shortenerLink.Variants = append(shortenerLink.Variants, *variant)
+	}
+	uc.recordRevision(NewShortenerLinkRevision(shortenerLink, userID, RevisionActionUpdate))
+
+	return toGetShortenerLinkVariants(variants), nil
+}
+
+func (uc *useCase) DeleteShortenerLink(userID, id uuid.UUID) e.ApiError {
+	shortenerLink, errApi := uc.getOwnedShortenerLink(userID, id)
+	if errApi != nil {
+		return errApi
+	}
+
+	if err := uc.repository.DeleteShortenerLink(shortenerLink); err != nil {
+		return e.NewApiError(500, err.Error())
+	}
+
+	uc.recordRevision(NewShortenerLinkRevision(shortenerLink, userID, RevisionActionDelete))
+	uc.emit(userID, EventLinkDeleted, toGetShortenerLink(shortenerLink))
+
+	return nil
+}
+
+// recordRevision appends a revision to the link history. It runs after the
+// change itself has been saved, so a failure is logged instead of failing a
+// change that already happened.
+func (uc *useCase) recordRevision(revision *ShortenerLinkRevisionModel) {
+	if err := uc.repository.CreateShortenerLinkRevision(revision); err != nil {
+		log.Println("Failed to record revision of shortener link", revision.ShortenerLinkID, err)
+	}
+}
+
+func (uc *useCase) GetShortenerLinkHistory(userID, id uuid.UUID) (*GetShortenerLinkHistoryResponseDTO, e.ApiError) {
+	shortenerLink, errApi := uc.getOwnedShortenerLink(userID, id)
+	if errApi != nil {
+		return nil, errApi
+	}
+
+	revisions, err := uc.repository.GetShortenerLinkRevisions(shortenerLink.ID)
+	if err != nil {
+		return nil, e.NewApiError(500, err.Error())
+	}
+
+	// Revisions are newest first, so the previous version follows each one.
+	data := make([]ShortenerLinkRevisionDTO, 0, len(revisions))
+	for i, revision := range revisions {
+		var previous *LinkSnapshot
+		if i+1 < len(revisions) {
+			previous = &revisions[i+1].Snapshot
+		}
+		data = append(data, toShortenerLinkRevisionDTO(revision, previous))
+	}
+
+	return &GetShortenerLinkHistoryResponseDTO{Revisions: data}, nil
+}
+
+// RestoreShortenerLinkVersion rolls a link back to the settings recorded in
+// one of its revisions. The password is left as it is because revisions do
+// not keep it, and a campaign or folder deleted since then is dropped. Rules
+// and variants are only recorded for the history; restoring keeps the current
+// ones.
+func (uc *useCase) RestoreShortenerLinkVersion(userID, id uuid.UUID, version int) (*GetShortenerLink, e.ApiError) {
+	shortenerLink, errApi := uc.getOwnedShortenerLink(userID, id)
+	if errApi != nil {
+		return nil, errApi
+	}
+
+	revision, err := uc.repository.GetShortenerLinkRevision(shortenerLink.ID, version)
+	if err != nil {
+		return nil, e.NewApiError(404, "Revision not found")
+	}
+	snapshot := revision.Snapshot
+	resetColumns := []string{"is_expired"}
+
+	originalURL, errApi := uc.checkOriginalURL(snapshot.OriginalURL)
+	if errApi != nil {
+		return nil, errApi
+	}
+	if originalURL != shortenerLink.OriginalURL {
+		shortenerLink.IsBroken = false
+		shortenerLink.HealthCheckedAt = nil
+		resetColumns = append(resetColumns, healthResetColumns...)
+	}
+	shortenerLink.OriginalURL = originalURL
+
+	if snapshot.ShortenerURL != shortenerLink.ShortenerURL {
+		// The alias rules may have changed since the revision was recorded.
+		alias := UpdateShortenerLinkRequestDTO{ShortenerURL: &snapshot.ShortenerURL}
+		if err := binding.Validator.ValidateStruct(&alias); err != nil {
+			return nil, e.NewApiError(400, "Short code of the revision is no longer allowed: "+CustomValidator.FormatValidationErrors(err))
+		}
+		shortenerLink.ShortenerURL = snapshot.ShortenerURL
+	}
+
+	shortenerLink.ActiveFrom = snapshot.ActiveFrom
+	shortenerLink.ExpiresAt = snapshot.ExpiresAt
+	shortenerLink.MaxClicks = snapshot.MaxClicks
+	if errApi := validateSchedule(shortenerLink); errApi != nil {
+		return nil, errApi
+	}
+	shortenerLink.IsExpired = false
+
+	shortenerLink.RedirectStatus = snapshot.RedirectStatus
+	shortenerLink.ForwardQuery = snapshot.ForwardQuery
+	shortenerLink.StatsPublic = snapshot.StatsPublic
+	shortenerLink.PreviewTitle = snapshot.PreviewTitle
+	shortenerLink.PreviewDescription = snapshot.PreviewDescription
+	shortenerLink.PreviewImageURL = snapshot.PreviewImageURL
+
+	// Like the destination, the app URLs are checked against the current
+	// policy and app schemes rather than the ones they were saved under.
+	if shortenerLink.DeepLink, errApi = uc.checkDeepLink(userID, &DeepLinkDTO{
+		IOSAppURL:       snapshot.DeepLink.IOSAppURL,
+		IOSStoreURL:     snapshot.DeepLink.IOSStoreURL,
+		AndroidAppURL:   snapshot.DeepLink.AndroidAppURL,
+		AndroidStoreURL: snapshot.DeepLink.AndroidStoreURL,
+	}); errApi != nil {
+		return nil, errApi
+	}
+
+	shortenerLink.CampaignID = nil
+	if snapshot.CampaignID != nil {
+		if campaign, err := uc.repository.GetCampaignByID(*snapshot.CampaignID); err == nil && campaign.IsOwnedBy(userID) {
+			shortenerLink.CampaignID = &campaign.ID
+		}
+	}
+
+	shortenerLink.FolderID = nil
+	if snapshot.FolderID != nil {
+		if folder, err := uc.repository.GetFolderByID(*snapshot.FolderID); err == nil && folder.IsOwnedBy(userID) {
+			shortenerLink.FolderID = &folder.ID
+		}
+	}
+
+	tags, errApi := uc.resolveTags(userID, snapshot.Tags)
+	if errApi != nil {
+		return nil, errApi
+	}
+
+	if err := uc.repository.UpdateShortenerLink(shortenerLink, resetColumns...); err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, e.NewApiError(400, "Shortener URL already exists")
+		}
+		return nil, e.NewApiError(500, err.Error())
+	}
+
+	if err := uc.repository.ReplaceShortenerLinkTags(shortenerLink.ID, tagIDs(tags)); err != nil {
+		return nil, e.NewApiError(500, err.Error())
+	}
+	shortenerLink.Tags = tags
+
+	restored := NewShortenerLinkRevision(shortenerLink, userID, RevisionActionRestore)
+	restored.RestoredFrom = &revision.Version
+	uc.recordRevision(restored)
+
+	res := toGetShortenerLink(shortenerLink)
+	uc.emit(userID, EventLinkUpdated, res)
+	return &res, nil
+}
+
+func (uc *useCase) GetDeletedShortenerLinks(userID uuid.UUID) (*GetAllShortenerLinksResponseDTO, e.ApiError) {
+	shortenerLinks, err := uc.repository.GetDeletedShortenerLinks(userID)
+	if err != nil {
+		return nil, e.NewApiError(500, err.Error())
+	}
+
+	data := make([]GetShortenerLink, 0, len(shortenerLinks))
+	for _, shortenerLink := range shortenerLinks {
+		data = append(data, toGetShortenerLink(shortenerLink))
+	}
+
+	return &GetAllShortenerLinksResponseDTO{ShortenerLink: data}, nil
+}
+
+// UndeleteShortenerLink brings back a soft deleted link with its code, unless
+// another link has claimed that code since or its branded domain is gone.
+func (uc *useCase) UndeleteShortenerLink(userID, id uuid.UUID) (*GetShortenerLink, e.ApiError) {
+	shortenerLink, err := uc.repository.GetDeletedShortenerLinkByID(id)
+	if err != nil {
+		return nil, e.NewApiError(404, "Deleted shortener link not found")
+	}
+
+	if !shortenerLink.IsOwnedBy(userID) {
+		return nil, e.NewApiError(403, "You do not have permission to access this shortener link")
+	}
+
+	// DeleteDomain only counts live links, so the domain of a deleted link
+	// may have been deleted since; the link could not be served from it.
+	if shortenerLink.DomainID != nil {
+		domain, err := uc.repository.GetDomainByID(*shortenerLink.DomainID)
+		if err != nil || !domain.IsVerified() {
+			return nil, e.NewApiError(400, "Domain of the shortener link was deleted or is no longer verified")
+		}
+	}
+
+	if err := uc.repository.UndeleteShortenerLink(shortenerLink); err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, e.NewApiError(400, "Shortener URL is now used by another link")
+		}
+		return nil, e.NewApiError(500, err.Error())
+	}
+
+	uc.recordRevision(NewShortenerLinkRevision(shortenerLink, userID, RevisionActionUndelete))
+
+	res := toGetShortenerLink(shortenerLink)
+	uc.emit(userID, EventLinkUpdated, res)
+	return &res, nil
+}
+
+func (uc *useCase) GetShortenerLinkStats(userID, id uuid.UUID, data *GetShortenerLinkStatsRequestDTO) (*GetShortenerLinkStatsResponseDTO, e.ApiError) {
+	shortenerLink, errApi := uc.getOwnedShortenerLink(userID, id)
+	if errApi != nil {
+		return nil, errApi
+	}
+
+	interval, from, to, errApi := statsRange(data)
+	if errApi != nil {
+		return nil, errApi
+	}
+
+	total, unique, err := uc.repository.CountShortenerLinkClicks(shortenerLink.ID, from, to)
+	if err != nil {
+		return nil, e.NewApiError(500, err.Error())
+	}
+
+	series, err := uc.repository.GetShortenerLinkClickSeries(shortenerLink.ID, interval, from, to)
+	if err != nil {
+		return nil, e.NewApiError(500, err.Error())
+	}
+
+	referrers, err := uc.repository.GetShortenerLinkTopReferrers(shortenerLink.ID, from, to, topReferrersLimit)
+	if err != nil {
+		return nil, e.NewApiError(500, err.Error())
+	}
+
+	variants, err := uc.repository.GetShortenerLinkVariantCounts(shortenerLink.ID, from, to)
+	if err != nil {
+		return nil, e.NewApiError(500, err.Error())
+	}
+
+	referrersDTO := make([]ReferrerCountDTO, 0, len(referrers))
+	for _, referrer := range referrers {
+		referrersDTO = append(referrersDTO, ReferrerCountDTO{
+			Referrer: referrer.Referrer,
+			Clicks:   referrer.Clicks,
+		})
+	}
+
+	var variantClicks int64
+	for _, variant := range variants {
+		variantClicks += variant.Clicks
+	}
+	variantsDTO := make([]VariantStatsDTO, 0, len(variants))
+	for _, variant := range variants {
+		var share float64
+		if variantClicks > 0 {
+			share = float64(variant.Clicks) / float64(variantClicks)
+		}
+		variantsDTO = append(variantsDTO, VariantStatsDTO{
+			VariantID:      variant.VariantID.String(),
+			Name:           variant.Name,
+			DestinationURL: variant.DestinationURL,
+			Weight:         variant.Weight,
+			Archived:       variant.Archived,
+			Clicks:         variant.Clicks,
+			UniqueVisitors: variant.UniqueVisitors,
+			ClickShare:     share,
+		})
+	}
+
+	return &GetShortenerLinkStatsResponseDTO{
+		TotalClicks:    total,
+		UniqueVisitors: unique,
+		Interval:       interval,
+		From:           from.Format(time.RFC3339),
+		To:             to.Format(time.RFC3339),
+		Series:         toClickSeriesDTO(series),
+		TopReferrers:   referrersDTO,
+		Variants:       variantsDTO,
+	}, nil
+}
+
+// statsRange resolves the interval and time range of a stats request, looking
+// back a default span from now when the range is not given.
+func statsRange(data *GetShortenerLinkStatsRequestDTO) (string, time.Time, time.Time, e.ApiError) {
+	interval := data.Interval
+	if interval == "" {
+		interval = StatsIntervalDay
+	}
+
+	to := data.To
+	if to.IsZero() {
+		to = time.Now()
+	}
+	from := data.From
+	if from.IsZero() {
+		from = to.Add(-defaultStatsRange[interval])
+	}
+	if from.After(to) {
+		return "", time.Time{}, time.Time{}, e.NewApiError(400, "from must be before to")
+	}
+
+	return interval, from, to, nil
+}
+
+func toClickSeriesDTO(series []ClickSeriesPoint) []ClickSeriesPointDTO {
+	data := make([]ClickSeriesPointDTO, 0, len(series))
+	for _, point := range series {
+		data = append(data, ClickSeriesPointDTO{
+			Bucket: point.Bucket.Format(time.RFC3339),
+			Clicks: point.Clicks,
+		})
+	}
+	return data
+}
+
+// GetShortenerLinkQRCode renders a QR code pointing at the public short URL.
+// The link can be addressed by its ID or by its short code.
+func (uc *useCase) GetShortenerLinkQRCode(userID uuid.UUID, idOrShortenerURL string, data *GetShortenerLinkQRCodeRequestDTO) (*QRCode, e.ApiError) {
+	var shortenerLink *ShortenerLinkModel
+	if id, err := uuid.Parse(idOrShortenerURL); err == nil {
+		var errApi e.ApiError
+		if shortenerLink, errApi = uc.getOwnedShortenerLink(userID, id); errApi != nil {
+			return nil, errApi
+		}
+	} else {
+		var domainID *uuid.UUID
+		if data.Domain != "" {
+			domain, err := uc.repository.GetVerifiedDomainByHostname(normalizeDomain(data.Domain))
+			if err != nil {
+				return nil, e.NewApiError(404, "Shortener link not found")
+			}
+			domainID = &domain.ID
+		}
+		shortenerLink, err = uc.repository.GetShortenerLinkByShortenerURL(domainID, idOrShortenerURL)
+		if err != nil {
+			return nil, e.NewApiError(404, "Shortener link not found")
+		}
+		if !shortenerLink.IsOwnedBy(userID) {
+			return nil, e.NewApiError(403, "You do not have permission to access this shortener link")
+		}
+	}
+
+	opts := qr.Options{
+		Size:   data.Size,
+		Level:  data.Level,
+		Margin: qr.DefaultMargin,
+	}
+	if data.Margin != nil {
+		opts.Margin = *data.Margin
+	}
+	if data.Foreground != "" {
+		fg, err := qr.ParseColor(data.Foreground)
+		if err != nil {
+			return nil, e.NewApiError(400, "fg: "+err.Error())
+		}
+		opts.Foreground = fg
+	}
+	if data.Background != "" {
+		bg, err := qr.ParseColor(data.Background)
+		if err != nil {
+			return nil, e.NewApiError(400, "bg: "+err.Error())
+		}
+		opts.Background = bg
+	}
+	if data.Logo {
+		if uc.qrLogo == nil {
+			return nil, e.NewApiError(400, "QR code logo is not configured")
+		}
+		opts.Logo = uc.qrLogo
+	}
+
+	format := data.Format
+	if format == "" {
+		format = qr.FormatPNG
+	}
+
+	content := shortURL(shortenerLink)
+	contentType := "image/png"
+	if format == qr.FormatSVG {
+		contentType = "image/svg+xml"
+	}
+
+	// The ETag comes from the inputs, so a matching If-None-Match is
+	// answered without rendering.
+	sum := sha256.Sum256([]byte(shortenerLink.ID.String() + "|" + qr.Fingerprint(content, format, opts)))
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+	if data.IfNoneMatch == etag {
+		return &QRCode{ContentType: contentType, ETag: etag, NotModified: true}, nil
+	}
+
+	encoded, err := qr.Encode(content, format, opts)
+	if err != nil {
+		if errors.Is(err, qr.ErrSizeTooSmall) {
+			return nil, e.NewApiError(400, err.Error())
+		}
+		return nil, e.NewApiError(500, err.Error())
+	}
+
+	return &QRCode{
+		Data:        encoded,
+		ContentType: contentType,
+		ETag:        etag,
+	}, nil
+}
+
+func (uc *useCase) GetCacheStats() (*GetCacheStatsResponseDTO, e.ApiError) {
+	provider, ok := uc.repository.(interface{ CacheStats() CacheStats })
+	if !ok {
+		return nil, e.NewApiError(404, "Link cache is not enabled")
+	}
+
+	stats := provider.CacheStats()
+	var hitRatio float64
+	if total := stats.Hits + stats.Misses; total > 0 {
+		hitRatio = float64(stats.Hits) / float64(total)
+	}
+
+	return &GetCacheStatsResponseDTO{
+		Hits:     stats.Hits,
+		Misses:   stats.Misses,
+		HitRatio: hitRatio,
+	}, nil
+}
+
+func (uc *useCase) GetAllBlockedDomains() (*GetAllBlockedDomainsResponseDTO, e.ApiError) {
+	blockedDomains, err := uc.repository.GetAllBlockedDomains()
+	if err != nil {
+		return nil, e.NewApiError(500, err.Error())
+	}
+
+	data := make([]GetBlockedDomain, 0, len(blockedDomains))
+	for _, blockedDomain := range blockedDomains {
+		data = append(data, toGetBlockedDomain(blockedDomain))
+	}
+
+	return &GetAllBlockedDomainsResponseDTO{BlockedDomains: data}, nil
+}
+
+func (uc *useCase) CreateBlockedDomain(userID uuid.UUID, data *CreateBlockedDomainRequestDTO) (*GetBlockedDomain, e.ApiError) {
+	blockedDomain := NewBlockedDomain(normalizeDomain(data.Domain), data.Reason, userID)
+	if err := uc.repository.CreateBlockedDomain(blockedDomain); err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, e.NewApiError(400, "Domain is already blocked")
+		}
+		return nil, e.NewApiError(500, err.Error())
+	}
+
+	if uc.urlPolicy != nil {
+		uc.urlPolicy.Reload()
+	}
+
+	res := toGetBlockedDomain(blockedDomain)
+	return &res, nil
+}
+
+func (uc *useCase) DeleteBlockedDomain(id uuid.UUID) e.ApiError {
+	if err := uc.repository.DeleteBlockedDomain(id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return e.NewApiError(404, "Blocked domain not found")
+		}
+		return e.NewApiError(500, err.Error())
+	}
+
+	if uc.urlPolicy != nil {
+		uc.urlPolicy.Reload()
+	}
+
+	return nil
+}
+
+func (uc *useCase) CreateCampaign(userID uuid.UUID, data *CreateCampaignRequestDTO) (*GetCampaign, e.ApiError) {
+	campaign := NewCampaign(userID, data.Name)
+	campaign.UTMSource = data.UTMSource
+	campaign.UTMMedium = data.UTMMedium
+	campaign.UTMCampaign = data.UTMCampaign
+	campaign.UTMTerm = data.UTMTerm
+	campaign.UTMContent = data.UTMContent
+
+	if err := uc.repository.CreateCampaign(campaign); err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, e.NewApiError(400, "Campaign already exists")
+		}
+		return nil, e.NewApiError(500, err.Error())
+	}
+
+	res := toGetCampaign(campaign, 0)
+	return &res, nil
+}
+
+func (uc *useCase) GetAllCampaigns(userID uuid.UUID) (*GetAllCampaignsResponseDTO, e.ApiError) {
+	campaigns, err := uc.repository.GetAllCampaigns(userID)
+	if err != nil {
+		return nil, e.NewApiError(500, err.Error())
+	}
+
+	linkCounts, err := uc.repository.CountShortenerLinksByCampaign(userID)
+	if err != nil {
+		return nil, e.NewApiError(500, err.Error())
+	}
+
+	data := make([]GetCampaign, 0, len(campaigns))
+	for _, campaign := range campaigns {
+		data = append(data, toGetCampaign(campaign, linkCounts[campaign.ID]))
+	}
+
+	return &GetAllCampaignsResponseDTO{Campaigns: data}, nil
+}
+
+func (uc *useCase) GetCampaign(userID, id uuid.UUID) (*GetCampaign, e.ApiError) {
+	campaign, errApi := uc.getOwnedCampaign(userID, id)
+	if errApi != nil {
+		return nil, errApi
+	}
+
+	linkCounts, err := uc.repository.CountShortenerLinksByCampaign(userID)
+	if err != nil {
+		return nil, e.NewApiError(500, err.Error())
+	}
+
+	res := toGetCampaign(campaign, linkCounts[campaign.ID])
+	return &res, nil
+}
+
+func (uc *useCase) UpdateCampaign(userID, id uuid.UUID, data *UpdateCampaignRequestDTO) (*GetCampaign, e.ApiError) {
+	campaign, errApi := uc.getOwnedCampaign(userID, id)
+	if errApi != nil {
+		return nil, errApi
+	}
+
+	if data.Name != nil {
+		campaign.Name = *data.Name
+	}
+	if data.UTMSource != nil {
+		campaign.UTMSource = *data.UTMSource
+	}
+	if data.UTMMedium != nil {
+		campaign.UTMMedium = *data.UTMMedium
+	}
+	if data.UTMCampaign != nil {
+		campaign.UTMCampaign = *data.UTMCampaign
+	}
+	if data.UTMTerm != nil {
+		campaign.UTMTerm = *data.UTMTerm
+	}
+	if data.UTMContent != nil {
+		campaign.UTMContent = *data.UTMContent
+	}
+
+	if err := uc.repository.UpdateCampaign(campaign); err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, e.NewApiError(400, "Campaign already exists")
+		}
+		return nil, e.NewApiError(500, err.Error())
+	}
+
+	linkCounts, err := uc.repository.CountShortenerLinksByCampaign(userID)
+	if err != nil {
+		return nil, e.NewApiError(500, err.Error())
+	}
+
+	res := toGetCampaign(campaign, linkCounts[campaign.ID])
+	return &res, nil
+}
+
+// DeleteCampaign removes the campaign and detaches its links; the links
+// themselves are kept.
+func (uc *useCase) DeleteCampaign(userID, id uuid.UUID) e.ApiError {
+	campaign, errApi := uc.getOwnedCampaign(userID, id)
+	if errApi != nil {
+		return errApi
+	}
+
+	if err := uc.repository.DeleteCampaign(campaign); err != nil {
+		return e.NewApiError(500, err.Error())
+	}
+
+	return nil
+}
+
+// GetCampaignStats rolls up the clicks of every link in the campaign,
+// including links that have since been deleted.
+func (uc *useCase) GetCampaignStats(userID, id uuid.UUID, data *GetShortenerLinkStatsRequestDTO) (*GetCampaignStatsResponseDTO, e.ApiError) {
+	campaign, errApi := uc.getOwnedCampaign(userID, id)
+	if errApi != nil {
+		return nil, errApi
+	}
+
+	interval, from, to, errApi := statsRange(data)
+	if errApi != nil {
+		return nil, errApi
+	}
+
+	total, unique, err := uc.repository.CountCampaignClicks(campaign.ID, from, to)
+	if err != nil {
+		return nil, e.NewApiError(500, err.Error())
+	}
+
+	series, err := uc.repository.GetCampaignClickSeries(campaign.ID, interval, from, to)
+	if err != nil {
+		return nil, e.NewApiError(500, err.Error())
+	}
+
+	links, err := uc.repository.GetCampaignLinkCounts(campaign.ID, from, to)
+	if err != nil {
+		return nil, e.NewApiError(500, err.Error())
+	}
+
+	linksDTO := make([]CampaignLinkStatsDTO, 0, len(links))
+	for _, link := range links {
+		linksDTO = append(linksDTO, CampaignLinkStatsDTO{
+			ID:             link.ShortenerLinkID.String(),
+			ShortenerURL:   link.ShortenerURL,
+			OriginalURL:    link.OriginalURL,
+			Clicks:         link.Clicks,
+			UniqueVisitors: link.UniqueVisitors,
+		})
+	}
+
+	return &GetCampaignStatsResponseDTO{
+		TotalClicks:    total,
+		UniqueVisitors: unique,
+		Interval:       interval,
+		From:           from.Format(time.RFC3339),
+		To:             to.Format(time.RFC3339),
+		Series:         toClickSeriesDTO(series),
+		Links:          linksDTO,
+	}, nil
+}
+
+func (uc *useCase) CreateTag(userID uuid.UUID, data *CreateTagRequestDTO) (*GetTag, e.ApiError) {
+	tag := NewTag(userID, strings.TrimSpace(data.Name))
+	if tag.Name == "" {
+		return nil, e.NewApiError(400, "Tag name must not be blank")
+	}
+
+	if err := uc.repository.CreateTag(tag); err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, e.NewApiError(400, "Tag already exists")
+		}
+		return nil, e.NewApiError(500, err.Error())
+	}
+
+	res := toGetTag(tag, 0)
+	return &res, nil
+}
+
+// GetAllTags lists the user's tags with the number of links carrying each.
+func (uc *useCase) GetAllTags(userID uuid.UUID) (*GetAllTagsResponseDTO, e.ApiError) {
+	tags, err := uc.repository.GetAllTags(userID)
+	if err != nil {
+		return nil, e.NewApiError(500, err.Error())
+	}
+
+	linkCounts, err := uc.repository.CountShortenerLinksByTag(userID)
+	if err != nil {
+		return nil, e.NewApiError(500, err.Error())
+	}
+
+	data := make([]GetTag, 0, len(tags))
+	for _, tag := range tags {
+		data = append(data, toGetTag(tag, linkCounts[tag.ID]))
+	}
+
+	return &GetAllTagsResponseDTO{Tags: data}, nil
+}
+
+// UpdateTag renames a tag; links keep it under the new name.
+func (uc *useCase) UpdateTag(userID, id uuid.UUID, data *UpdateTagRequestDTO) (*GetTag, e.ApiError) {
+	tag, errApi := uc.getOwnedTag(userID, id)
+	if errApi != nil {
+		return nil, errApi
+	}
+
+	tag.Name = strings.TrimSpace(data.Name)
+	if tag.Name == "" {
+		return nil, e.NewApiError(400, "Tag name must not be blank")
+	}
+
+	if err := uc.repository.UpdateTag(tag); err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, e.NewApiError(400, "Tag already exists")
+		}
+		return nil, e.NewApiError(500, err.Error())
+	}
+
+	linkCounts, err := uc.repository.CountShortenerLinksByTag(userID)
+	if err != nil {
+		return nil, e.NewApiError(500, err.Error())
+	}
+
+	res := toGetTag(tag, linkCounts[tag.ID])
+	return &res, nil
+}
+
+func (uc *useCase) DeleteTag(userID, id uuid.UUID) e.ApiError {
+	tag, errApi := uc.getOwnedTag(userID, id)
+	if errApi != nil {
+		return errApi
+	}
+
+	if err := uc.repository.DeleteTag(tag); err != nil {
+		return e.NewApiError(500, err.Error())
+	}
+
+	return nil
+}
+
+func (uc *useCase) CreateFolder(userID uuid.UUID, data *CreateFolderRequestDTO) (*GetFolder, e.ApiError) {
+	folder := NewFolder(userID, strings.TrimSpace(data.Name))
+	if folder.Name == "" {
+		return nil, e.NewApiError(400, "Folder name must not be blank")
+	}
+
+	if err := uc.repository.CreateFolder(folder); err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, e.NewApiError(400, "Folder already exists")
+		}
+		return nil, e.NewApiError(500, err.Error())
+	}
+
+	res := toGetFolder(folder, 0)
+	return &res, nil
+}
+
+func (uc *useCase) GetAllFolders(userID uuid.UUID) (*GetAllFoldersResponseDTO, e.ApiError) {
+	folders, err := uc.repository.GetAllFolders(userID)
+	if err != nil {
+		return nil, e.NewApiError(500, err.Error())
+	}
+
+	linkCounts, err := uc.repository.CountShortenerLinksByFolder(userID)
+	if err != nil {
+		return nil, e.NewApiError(500, err.Error())
+	}
+
+	data := make([]GetFolder, 0, len(folders))
+	for _, folder := range folders {
+		data = append(data, toGetFolder(folder, linkCounts[folder.ID]))
+	}
+
+	return &GetAllFoldersResponseDTO{Folders: data}, nil
+}
+
+func (uc *useCase) UpdateFolder(userID, id uuid.UUID, data *UpdateFolderRequestDTO) (*GetFolder, e.ApiError) {
+	folder, errApi := uc.getOwnedFolder(userID, id)
+	if errApi != nil {
+		return nil, errApi
+	}
+
+	folder.Name = strings.TrimSpace(data.Name)
+	if folder.Name == "" {
+		return nil, e.NewApiError(400, "Folder name must not be blank")
+	}
+
+	if err := uc.repository.UpdateFolder(folder); err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, e.NewApiError(400, "Folder already exists")
+		}
+		return nil, e.NewApiError(500, err.Error())
+	}
+
+	linkCounts, err := uc.repository.CountShortenerLinksByFolder(userID)
+	if err != nil {
+		return nil, e.NewApiError(500, err.Error())
+	}
+
+	res := toGetFolder(folder, linkCounts[folder.ID])
+	return &res, nil
+}
+
+// DeleteFolder removes the folder; its links are kept outside any folder.
+func (uc *useCase) DeleteFolder(userID, id uuid.UUID) e.ApiError {
+	folder, errApi := uc.getOwnedFolder(userID, id)
+	if errApi != nil {
+		return errApi
+	}
+
+	if err := uc.repository.DeleteFolder(folder); err != nil {
+		return e.NewApiError(500, err.Error())
+	}
+
+	return nil
+}
+
+func (uc *useCase) CreateDomain(userID uuid.UUID, data *CreateDomainRequestDTO) (*GetDomain, e.ApiError) {
+	hostname := normalizeDomain(data.Hostname)
+	if hostname == baseHostname() || isPrivateHost(hostname) {
+		return nil, e.NewApiError(400, "Domain cannot be used for short links")
+	}
+
+	token, err := newVerificationToken()
+	if err != nil {
+		log.Println(err)
+		return nil, e.NewApiError(500, "Failed to generate verification token")
+	}
+
+	domain := NewDomain(userID, hostname, token)
+	if err := uc.repository.CreateDomain(domain); err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, e.NewApiError(400, "Domain already exists")
+		}
+		return nil, e.NewApiError(500, err.Error())
+	}
+
+	res := toGetDomain(domain, 0)
+	return &res, nil
+}
+
+func (uc *useCase) GetAllDomains(userID uuid.UUID) (*GetAllDomainsResponseDTO, e.ApiError) {
+	domains, err := uc.repository.GetAllDomains(userID)
+	if err != nil {
+		return nil, e.NewApiError(500, err.Error())
+	}
+
+	linkCounts, err := uc.repository.CountShortenerLinksByDomain(userID)
+	if err != nil {
+		return nil, e.NewApiError(500, err.Error())
+	}
+
+	data := make([]GetDomain, 0, len(domains))
+	for _, domain := range domains {
+		data = append(data, toGetDomain(domain, linkCounts[domain.ID]))
+	}
+
+	return &GetAllDomainsResponseDTO{Domains: data}, nil
+}
+
+func (uc *useCase) GetDomain(userID, id uuid.UUID) (*GetDomain, e.ApiError) {
+	domain, errApi := uc.getOwnedDomain(userID, id)
+	if errApi != nil {
+		return nil, errApi
+	}
+
+	linkCounts, err := uc.repository.CountShortenerLinksByDomain(userID)
+	if err != nil {
+		return nil, e.NewApiError(500, err.Error())
+	}
+
+	res := toGetDomain(domain, linkCounts[domain.ID])
+	return &res, nil
+}
+
+// VerifyDomain looks up the verification TXT record of the domain and, once
+// it is found, lets links be created on the domain.
+func (uc *useCase) VerifyDomain(userID, id uuid.UUID) (*GetDomain, e.ApiError) {
+	domain, errApi := uc.getOwnedDomain(userID, id)
+	if errApi != nil {
+		return nil, errApi
+	}
+
+	if !domain.IsVerified() {
+		if err := uc.verifier.Verify(domain); err != nil {
+			if errors.Is(err, ErrDomainRecordNotFound) {
+				return nil, e.NewApiError(400, fmt.Sprintf("%s: add a TXT record on %s with the value %s", err.Error(), domain.VerificationRecordName(), domain.VerificationRecordValue()))
+			}
+			log.Println(err)
+			return nil, e.NewApiError(502, "Failed to look up the verification record")
+		}
+
+		now := time.Now()
+		domain.VerifiedAt = &now
+		if err := uc.repository.VerifyDomain(domain); err != nil {
+			if errors.Is(err, gorm.ErrDuplicatedKey) {
+				return nil, e.NewApiError(400, "Domain is already verified by another account")
+			}
+			return nil, e.NewApiError(500, err.Error())
+		}
+	}
+
+	linkCounts, err := uc.repository.CountShortenerLinksByDomain(userID)
+	if err != nil {
+		return nil, e.NewApiError(500, err.Error())
+	}
+
+	res := toGetDomain(domain, linkCounts[domain.ID])
+	return &res, nil
+}
+
+// DeleteDomain refuses to delete a domain that still serves links, since
+// they could no longer be reached.
+func (uc *useCase) DeleteDomain(userID, id uuid.UUID) e.ApiError {
+	domain, errApi := uc.getOwnedDomain(userID, id)
+	if errApi != nil {
+		return errApi
+	}
+
+	linkCounts, err := uc.repository.CountShortenerLinksByDomain(userID)
+	if err != nil {
+		return e.NewApiError(500, err.Error())
+	}
+	if linkCounts[domain.ID] > 0 {
+		return e.NewApiError(400, "Domain still has shortener links")
+	}
+
+	if err := uc.repository.DeleteDomain(domain); err != nil {
+		return e.NewApiError(500, err.Error())
+	}
+
+	return nil
+}
+
+func (uc *useCase) getOwnedShortenerLink(userID, id uuid.UUID) (*ShortenerLinkModel, e.ApiError) {
+	shortenerLink, err := uc.repository.GetShortenerLinkByID(id)
+	if err != nil {
+		return nil, e.NewApiError(404, "Shortener link not found")
+	}
+
+	if !shortenerLink.IsOwnedBy(userID) {
+		return nil, e.NewApiError(403, "You do not have permission to access this shortener link")
+	}
+
+	return shortenerLink, nil
+}
+
+func (uc *useCase) getOwnedCampaign(userID, id uuid.UUID) (*CampaignModel, e.ApiError) {
+	campaign, err := uc.repository.GetCampaignByID(id)
+	if err != nil {
+		return nil, e.NewApiError(404, "Campaign not found")
+	}
+
+	if !campaign.IsOwnedBy(userID) {
+		return nil, e.NewApiError(403, "You do not have permission to access this campaign")
+	}
+
+	return campaign, nil
+}
+
+func (uc *useCase) getOwnedTag(userID, id uuid.UUID) (*TagModel, e.ApiError) {
+	tag, err := uc.repository.GetTagByID(id)
+	if err != nil {
+		return nil, e.NewApiError(404, "Tag not found")
+	}
+
+	if !tag.IsOwnedBy(userID) {
+		return nil, e.NewApiError(403, "You do not have permission to access this tag")
+	}
+
+	return tag, nil
+}
+
+func (uc *useCase) getOwnedFolder(userID, id uuid.UUID) (*FolderModel, e.ApiError) {
+	folder, err := uc.repository.GetFolderByID(id)
+	if err != nil {
+		return nil, e.NewApiError(404, "Folder not found")
+	}
+
+	if !folder.IsOwnedBy(userID) {
+		return nil, e.NewApiError(403, "You do not have permission to access this folder")
+	}
+
+	return folder, nil
+}
+
+func (uc *useCase) getOwnedDomain(userID, id uuid.UUID) (*DomainModel, e.ApiError) {
+	domain, err := uc.repository.GetDomainByID(id)
+	if err != nil {
+		return nil, e.NewApiError(404, "Domain not found")
+	}
+
+	if !domain.IsOwnedBy(userID) {
+		return nil, e.NewApiError(403, "You do not have permission to access this domain")
+	}
+
+	return domain, nil
+}
+
+// getOwnedVerifiedDomain resolves the domain_id of a link request, which must
+// name one of the user's verified domains.
+func (uc *useCase) getOwnedVerifiedDomain(userID uuid.UUID, rawID string) (*DomainModel, e.ApiError) {
+	id, err := uuid.Parse(rawID)
+	if err != nil {
+		return nil, e.NewApiError(400, "Invalid domain ID")
+	}
+
+	domain, err := uc.repository.GetDomainByID(id)
+	if err != nil || !domain.IsOwnedBy(userID) {
+		return nil, e.NewApiError(400, "Domain not found")
+	}
+
+	if !domain.IsVerified() {
+		return nil, e.NewApiError(400, "Domain is not verified")
+	}
+
+	return domain, nil
+}
+
+// getOwnedFolderID resolves the folder_id of a link request, which must name
+// one of the user's folders.
+func (uc *useCase) getOwnedFolderID(userID uuid.UUID, rawID string) (*uuid.UUID, e.ApiError) {
+	id, err := uuid.Parse(rawID)
+	if err != nil {
+		return nil, e.NewApiError(400, "Invalid folder ID")
+	}
+
+	folder, err := uc.repository.GetFolderByID(id)
+	if err != nil || !folder.IsOwnedBy(userID) {
+		return nil, e.NewApiError(400, "Folder not found")
+	}
+
+	return &folder.ID, nil
+}
+
+// resolveTags returns the user's tags with the given names, creating the ones
+// that do not exist yet. Names are trimmed and compared ignoring case.
+func (uc *useCase) resolveTags(userID uuid.UUID, names []string) ([]TagModel, e.ApiError) {
+	wanted := make([]string, 0, len(names))
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		key := strings.ToLower(name)
+		if name == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		wanted = append(wanted, name)
+	}
+	if len(wanted) == 0 {
+		return nil, nil
+	}
+
+	existing, err := uc.repository.GetTagsByNames(userID, wanted)
+	if err != nil {
+		return nil, e.NewApiError(500, err.Error())
+	}
+	byName := make(map[string]*TagModel, len(existing))
+	for _, tag := range existing {
+		byName[strings.ToLower(tag.Name)] = tag
+	}
+
+	tags := make([]TagModel, 0, len(wanted))
+	for _, name := range wanted {
+		tag, ok := byName[strings.ToLower(name)]
+		if !ok {
+			tag = NewTag(userID, name)
+			if err := uc.repository.CreateTag(tag); err != nil {
+				if !errors.Is(err, gorm.ErrDuplicatedKey) {
+					return nil, e.NewApiError(500, err.Error())
+				}
+				// Another request created the tag in the meantime.
+				found, err := uc.repository.GetTagsByNames(userID, []string{name})
+				if err != nil || len(found) == 0 {
+					return nil, e.NewApiError(500, "Failed to create tag "+name)
+				}
+				tag = found[0]
+			}
+		}
+		tags = append(tags, *tag)
+	}
+
+	return tags, nil
+}
+
+// getOwnedCampaignID resolves the campaign_id of a link request, which must
+// name one of the user's campaigns.
+func (uc *useCase) getOwnedCampaignID(userID uuid.UUID, rawID string) (*uuid.UUID, e.ApiError) {
+	id, err := uuid.Parse(rawID)
+	if err != nil {
+		return nil, e.NewApiError(400, "Invalid campaign ID")
+	}
+
+	campaign, err := uc.repository.GetCampaignByID(id)
+	if err != nil || !campaign.IsOwnedBy(userID) {
+		return nil, e.NewApiError(400, "Campaign not found")
+	}
+
+	return &campaign.ID, nil
+}
+
+func toGetShortenerLink(shortenerLink *ShortenerLinkModel) GetShortenerLink {
+	return GetShortenerLink{
+		ID:                 shortenerLink.ID.String(),
+		OriginalURL:        shortenerLink.OriginalURL,
+		ShortenerURL:       shortenerLink.ShortenerURL,
+		ActiveFrom:         formatTime(shortenerLink.ActiveFrom),
+		ExpiresAt:          formatTime(shortenerLink.ExpiresAt),
+		MaxClicks:          shortenerLink.MaxClicks,
+		ClickCount:         shortenerLink.ClickCount,
+		IsExpired:          shortenerLink.IsExpired,
+		IsProtected:        shortenerLink.IsProtected(),
+		RedirectStatus:     shortenerLink.RedirectStatus,
+		ForwardQuery:       shortenerLink.ForwardQuery,
+		StatsPublic:        shortenerLink.StatsPublic,
+		CampaignID:         formatUUID(shortenerLink.CampaignID),
+		FolderID:           formatUUID(shortenerLink.FolderID),
+		DomainID:           formatUUID(shortenerLink.DomainID),
+		Tags:               tagNames(shortenerLink.Tags),
+		PreviewTitle:       shortenerLink.PreviewTitle,
+		PreviewDescription: shortenerLink.PreviewDescription,
+		PreviewImageURL:    shortenerLink.PreviewImageURL,
+		DeepLink:           toDeepLinkDTO(shortenerLink.DeepLink),
+		IsBroken:           shortenerLink.IsBroken,
+		Health:             toLinkHealth(shortenerLink),
+		CreatedAt:          shortenerLink.CreatedAt.Format("2006-01-02 15:04:05"),
+		DeletedAt:          formatDeletedAt(shortenerLink.DeletedAt),
+	}
+}
+
+func toShortenerLinkRevisionDTO(revision *ShortenerLinkRevisionModel, previous *LinkSnapshot) ShortenerLinkRevisionDTO {
+	snapshot := revision.Snapshot
+	changes := make([]FieldChangeDTO, 0)
+	for _, change := range snapshot.Changes(previous) {
+		changes = append(changes, FieldChangeDTO{Field: change.Field, Old: change.Old, New: change.New})
+	}
+
+	return ShortenerLinkRevisionDTO{
+		Version:      revision.Version,
+		Action:       revision.Action,
+		UserID:       formatUUID(revision.UserID),
+		RestoredFrom: revision.RestoredFrom,
+		Snapshot: LinkSnapshotDTO{
+			OriginalURL:        snapshot.OriginalURL,
+			ShortenerURL:       snapshot.ShortenerURL,
+			ActiveFrom:         formatTime(snapshot.ActiveFrom),
+			ExpiresAt:          formatTime(snapshot.ExpiresAt),
+			MaxClicks:          snapshot.MaxClicks,
+			IsProtected:        snapshot.IsProtected,
+			RedirectStatus:     snapshot.RedirectStatus,
+			ForwardQuery:       snapshot.ForwardQuery,
+			StatsPublic:        snapshot.StatsPublic,
+			CampaignID:         formatUUID(snapshot.CampaignID),
+			FolderID:           formatUUID(snapshot.FolderID),
+			Tags:               snapshot.Tags,
+			PreviewTitle:       snapshot.PreviewTitle,
+			PreviewDescription: snapshot.PreviewDescription,
+			PreviewImageURL:    snapshot.PreviewImageURL,
+			DeepLink:           toDeepLinkDTO(snapshot.DeepLink),
+			Rules:              snapshot.Rules,
+			Variants:           snapshot.Variants,
+		},
+		Changes:   changes,
+		CreatedAt: revision.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
+
+// shortURL is the public address of a link, served from the root of BASE_URL
+// or of its branded domain. Branded domains use the scheme of BASE_URL.
+func shortURL(shortenerLink *ShortenerLinkModel) string {
+	base := strings.TrimSuffix(configs.Config.BASE_URL, "/")
+	if shortenerLink.Domain != nil {
+		scheme := "https"
+		if baseURL, err := url.Parse(configs.Config.BASE_URL); err == nil && baseURL.Scheme != "" {
+			scheme = baseURL.Scheme
+		}
+		base = scheme + "://" + shortenerLink.Domain.Hostname
+	}
+	return base + "/" + shortenerLink.ShortenerURL
+}
+
+// baseHostname is the host of BASE_URL, which serves the links without a
+// branded domain.
+func baseHostname() string {
+	baseURL, err := url.Parse(configs.Config.BASE_URL)
+	if err != nil {
+		return ""
+	}
+	return normalizeDomain(baseURL.Hostname())
+}
+
+// requestHostname strips the port from a Host header.
+func requestHostname(host string) string {
+	if hostname, _, err := net.SplitHostPort(host); err == nil {
+		host = hostname
+	}
+	return normalizeDomain(host)
+}
+
+func toGetShortenerLinkRules(rules []*ShortenerLinkRuleModel) *GetShortenerLinkRulesResponseDTO {
+	data := make([]ShortenerLinkRuleDTO, 0, len(rules))
+	for _, rule := range rules {
+		data = append(data, ShortenerLinkRuleDTO{
+			Type:           rule.Type,
+			Values:         rule.Values,
+			DestinationURL: rule.DestinationURL,
+		})
+	}
+	return &GetShortenerLinkRulesResponseDTO{Rules: data}
+}
+
+func toGetShortenerLinkVariants(variants []*ShortenerLinkVariantModel) *GetShortenerLinkVariantsResponseDTO {
+	data := make([]ShortenerLinkVariantDTO, 0, len(variants))
+	for _, variant := range variants {
+		id := variant.ID.String()
+		data = append(data, ShortenerLinkVariantDTO{
+			ID:             &id,
+			Name:           variant.Name,
+			DestinationURL: variant.DestinationURL,
+			Weight:         variant.Weight,
+		})
+	}
+	return &GetShortenerLinkVariantsResponseDTO{Variants: data}
+}
+
+func toGetBlockedDomain(blockedDomain *BlockedDomainModel) GetBlockedDomain {
+	return GetBlockedDomain{
+		ID:        blockedDomain.ID.String(),
+		Domain:    blockedDomain.Domain,
+		Reason:    blockedDomain.Reason,
+		CreatedAt: blockedDomain.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
+
+func toGetCampaign(campaign *CampaignModel, linkCount int64) GetCampaign {
+	return GetCampaign{
+		ID:          campaign.ID.String(),
+		Name:        campaign.Name,
+		UTMSource:   campaign.UTMSource,
+		UTMMedium:   campaign.UTMMedium,
+		UTMCampaign: campaign.UTMCampaign,
+		UTMTerm:     campaign.UTMTerm,
+		UTMContent:  campaign.UTMContent,
+		LinkCount:   linkCount,
+		CreatedAt:   campaign.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
+
+func toLinkHealth(shortenerLink *ShortenerLinkModel) *LinkHealthDTO {
+	if shortenerLink.HealthCheckedAt == nil {
+		return nil
+	}
+	return &LinkHealthDTO{
+		StatusCode: shortenerLink.HealthStatusCode,
+		LatencyMs:  shortenerLink.HealthLatencyMs,
+		Error:      shortenerLink.HealthError,
+		CheckedAt:  shortenerLink.HealthCheckedAt.Format("2006-01-02 15:04:05"),
+	}
+}
+
+func toGetDomain(domain *DomainModel, linkCount int64) GetDomain {
+	return GetDomain{
+		ID:         domain.ID.String(),
+		Hostname:   domain.Hostname,
+		IsVerified: domain.IsVerified(),
+		VerifiedAt: formatTime(domain.VerifiedAt),
+		VerificationRecord: DomainVerificationRecordDTO{
+			Type:  "TXT",
+			Name:  domain.VerificationRecordName(),
+			Value: domain.VerificationRecordValue(),
+		},
+		LinkCount: linkCount,
+		CreatedAt: domain.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
+
+func toGetTag(tag *TagModel, linkCount int64) GetTag {
+	return GetTag{
+		ID:        tag.ID.String(),
+		Name:      tag.Name,
+		LinkCount: linkCount,
+		CreatedAt: tag.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
+
+func toGetFolder(folder *FolderModel, linkCount int64) GetFolder {
+	return GetFolder{
+		ID:        folder.ID.String(),
+		Name:      folder.Name,
+		LinkCount: linkCount,
+		CreatedAt: folder.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
+
+func tagIDs(tags []TagModel) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(tags))
+	for _, tag := range tags {
+		ids = append(ids, tag.ID)
+	}
+	return ids
+}
+
+func tagNames(tags []TagModel) []string {
+	names := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		names = append(names, tag.Name)
+	}
+	return names
+}
+
+func formatUUID(id *uuid.UUID) *string {
+	if id == nil {
+		return nil
+	}
+	formatted := id.String()
+	return &formatted
+}
+
+func formatDeletedAt(deletedAt gorm.DeletedAt) *string {
+	if !deletedAt.Valid {
+		return nil
+	}
+	return formatTime(&deletedAt.Time)
+}
+
+func formatTime(t *time.Time) *string {
+	if t == nil {
+		return nil
+	}
+	formatted := t.Format("2006-01-02 15:04:05")
+	return &formatted
+}
@@ -0,0 +1,71 @@
+package shortlink
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplySchedule(t *testing.T) {
+	activeFrom := time.Now().Add(time.Hour)
+	expiresAt := time.Now().Add(48 * time.Hour)
+	maxClicks := 100
+	newExpiresAt := time.Now().Add(72 * time.Hour)
+
+	newLink := func() *ShortenerLinkModel {
+		return &ShortenerLinkModel{ActiveFrom: &activeFrom, ExpiresAt: &expiresAt, MaxClicks: &maxClicks}
+	}
+
+	t.Run("Unchanged", func(t *testing.T) {
+		link := newLink()
+		changed, errApi := applySchedule(link, &UpdateShortenerLinkRequestDTO{})
+		assert.Nil(t, errApi)
+		assert.False(t, changed)
+		assert.Equal(t, &expiresAt, link.ExpiresAt)
+	})
+
+	t.Run("Sets", func(t *testing.T) {
+		link := newLink()
+		changed, errApi := applySchedule(link, &UpdateShortenerLinkRequestDTO{ExpiresAt: &newExpiresAt})
+		assert.Nil(t, errApi)
+		assert.True(t, changed)
+		assert.Equal(t, &newExpiresAt, link.ExpiresAt)
+		assert.Equal(t, &maxClicks, link.MaxClicks)
+	})
+
+	t.Run("Clears", func(t *testing.T) {
+		link := newLink()
+		changed, errApi := applySchedule(link, &UpdateShortenerLinkRequestDTO{
+			ClearActiveFrom: true,
+			ClearExpiresAt:  true,
+			ClearMaxClicks:  true,
+		})
+		assert.Nil(t, errApi)
+		assert.True(t, changed)
+		assert.Nil(t, link.ActiveFrom)
+		assert.Nil(t, link.ExpiresAt)
+		assert.Nil(t, link.MaxClicks)
+	})
+
+	t.Run("Clearing one keeps the others", func(t *testing.T) {
+		link := newLink()
+		changed, errApi := applySchedule(link, &UpdateShortenerLinkRequestDTO{ClearMaxClicks: true})
+		assert.Nil(t, errApi)
+		assert.True(t, changed)
+		assert.Nil(t, link.MaxClicks)
+		assert.Equal(t, &expiresAt, link.ExpiresAt)
+	})
+
+	t.Run("Set and clear at once", func(t *testing.T) {
+		changed, errApi := applySchedule(newLink(), &UpdateShortenerLinkRequestDTO{ExpiresAt: &newExpiresAt, ClearExpiresAt: true})
+		assert.False(t, changed)
+		assert.Equal(t, 400, errApi.Code())
+	})
+
+	t.Run("Validates the result", func(t *testing.T) {
+		past := time.Now().Add(-time.Hour)
+		_, errApi := applySchedule(newLink(), &UpdateShortenerLinkRequestDTO{ExpiresAt: &past})
+		assert.Equal(t, 400, errApi.Code())
+	})
+}
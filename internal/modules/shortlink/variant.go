@@ -0,0 +1,38 @@
+package shortlink
+
+import (
+	"math/rand/v2"
+)
+
+// pickVariant keeps a returning visitor on the variant named by sticky while
+// that variant still exists, and otherwise draws one at random in proportion
+// to the variant weights. intN must return a value in [0, n).
+func pickVariant(variants []ShortenerLinkVariantModel, sticky string, intN func(n int) int) *ShortenerLinkVariantModel {
+	if len(variants) == 0 {
+		return nil
+	}
+
+	total := 0
+	for i := range variants {
+		if sticky != "" && variants[i].ID.String() == sticky {
+			return &variants[i]
+		}
+		total += variants[i].Weight
+	}
+	if total <= 0 {
+		return &variants[0]
+	}
+
+	n := intN(total)
+	for i := range variants {
+		n -= variants[i].Weight
+		if n < 0 {
+			return &variants[i]
+		}
+	}
+	return &variants[len(variants)-1]
+}
+
+func randomIntN(n int) int {
+	return rand.IntN(n)
+}
@@ -0,0 +1,40 @@
+package shortlink
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPickVariant(t *testing.T) {
+	linkID := uuid.New()
+	variants := []ShortenerLinkVariantModel{
+		*NewShortenerLinkVariant(linkID, 0, "A", "https://example.com/a", 3),
+		*NewShortenerLinkVariant(linkID, 1, "B", "https://example.com/b", 1),
+	}
+
+	t.Run("Weighted draw", func(t *testing.T) {
+		for draw, expected := range []string{"A", "A", "A", "B"} {
+			variant := pickVariant(variants, "", func(n int) int {
+				assert.Equal(t, 4, n)
+				return draw
+			})
+			assert.Equal(t, expected, variant.Name)
+		}
+	})
+
+	t.Run("Sticky assignment", func(t *testing.T) {
+		variant := pickVariant(variants, variants[1].ID.String(), func(int) int { return 0 })
+		assert.Equal(t, "B", variant.Name)
+	})
+
+	t.Run("Unknown sticky variant is redrawn", func(t *testing.T) {
+		variant := pickVariant(variants, uuid.NewString(), func(int) int { return 0 })
+		assert.Equal(t, "A", variant.Name)
+	})
+
+	t.Run("No variants", func(t *testing.T) {
+		assert.Nil(t, pickVariant(nil, "", randomIntN))
+	})
+}
@@ -0,0 +1,39 @@
+package webhook
+
+import "time"
+
+const (
+	DeliveryStatusPending   = "pending"
+	DeliveryStatusDelivered = "delivered"
+	DeliveryStatusFailed    = "failed"
+
+	// SecretPrefix marks signing secrets so they are recognisable when leaked.
+	SecretPrefix = "whsec_"
+
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderDelivery  = "X-Webhook-Delivery"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderSignature = "X-Webhook-Signature"
+	// SignatureScheme prefixes the hex encoded HMAC in HeaderSignature.
+	SignatureScheme    = "sha256="
+	DeliveryUserAgent  = "njajal-webhooks/1.0"
+	MaxWebhooksPerUser = 20
+
+	DefaultEmitterBufferSize = 1000
+	// DefaultSubscriptionRefreshInterval is how often the emitter reloads
+	// which users have webhooks.
+	DefaultSubscriptionRefreshInterval = time.Minute
+	DefaultDeliveryInterval            = 5 * time.Second
+	DefaultDeliveryTimeout             = 10 * time.Second
+	DefaultDeliveryConcurrency         = 8
+	DefaultDeliveryBatchSize           = 100
+	// DefaultDeliveryLease is how long a claimed delivery is hidden from other
+	// workers. It must be longer than DefaultDeliveryTimeout.
+	DefaultDeliveryLease  = time.Minute
+	DefaultMaxAttempts    = 8
+	DefaultRetryBaseDelay = 30 * time.Second
+	DefaultRetryMaxDelay  = 6 * time.Hour
+
+	recentDeliveriesLimit  = 50
+	maxDeliveryErrorLength = 255
+)
@@ -0,0 +1,92 @@
+package webhook
+
+import (
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/xcurvnubaim/njajal-gin-golang/internal/modules/common"
+)
+
+// WebhookModel is an endpoint a user registered to receive the events listed
+// in Events.
+type WebhookModel struct {
+	common.BaseModels
+	UserID      uuid.UUID      `gorm:"column:user_id;type:uuid;not null"`
+	URL         string         `gorm:"column:url;not null"`
+	Description string         `gorm:"column:description;default:''"`
+	Secret      string         `gorm:"column:secret;not null"`
+	Events      pq.StringArray `gorm:"column:events;type:text[];not null"`
+	IsActive    bool           `gorm:"column:is_active;not null"`
+}
+
+func (WebhookModel) TableName() string {
+	return "webhooks"
+}
+
+func NewWebhook(userID uuid.UUID, url, description, secret string, events []string) *WebhookModel {
+	return &WebhookModel{
+		BaseModels:  common.NewBaseModels(),
+		UserID:      userID,
+		URL:         url,
+		Description: description,
+		Secret:      secret,
+		Events:      events,
+		IsActive:    true,
+	}
+}
+
+func (m *WebhookModel) IsOwnedBy(userID uuid.UUID) bool {
+	return m.UserID == userID
+}
+
+func (m *WebhookModel) IsSubscribedTo(event string) bool {
+	return slices.Contains(m.Events, event)
+}
+
+// WebhookDeliveryModel is one event sent to one webhook, along with the
+// outcome of its latest attempt.
+type WebhookDeliveryModel struct {
+	common.BaseModels
+	WebhookID uuid.UUID `gorm:"column:webhook_id;type:uuid;not null"`
+	Event     string    `gorm:"column:event;not null"`
+	// Payload is the exact request body, so retries are byte for byte
+	// identical.
+	Payload        string     `gorm:"column:payload;type:jsonb;not null"`
+	Status         string     `gorm:"column:status;not null"`
+	Attempts       int        `gorm:"column:attempts;not null"`
+	NextAttemptAt  *time.Time `gorm:"column:next_attempt_at;default:null"`
+	LastAttemptAt  *time.Time `gorm:"column:last_attempt_at;default:null"`
+	ResponseStatus *int       `gorm:"column:response_status;default:null"`
+	Error          string     `gorm:"column:error;default:''"`
+	DurationMs     *int       `gorm:"column:duration_ms;default:null"`
+	DeliveredAt    *time.Time `gorm:"column:delivered_at;default:null"`
+	// Webhook is only loaded by the delivery worker.
+	Webhook *WebhookModel `gorm:"foreignKey:WebhookID"`
+}
+
+func (WebhookDeliveryModel) TableName() string {
+	return "webhook_deliveries"
+}
+
+// NewWebhookDelivery returns a delivery due now. Its Payload is set by the
+// caller, as the body embeds the delivery ID.
+func NewWebhookDelivery(webhookID uuid.UUID, event string, now time.Time) *WebhookDeliveryModel {
+	return &WebhookDeliveryModel{
+		BaseModels:    common.NewBaseModels(),
+		WebhookID:     webhookID,
+		Event:         event,
+		Status:        DeliveryStatusPending,
+		NextAttemptAt: &now,
+	}
+}
+
+// Requeue resets a delivery so the worker sends it again as soon as possible,
+// with a fresh budget of attempts.
+func (m *WebhookDeliveryModel) Requeue(now time.Time) {
+	m.Status = DeliveryStatusPending
+	m.Attempts = 0
+	m.NextAttemptAt = &now
+	m.DeliveredAt = nil
+}
@@ -0,0 +1,50 @@
+package webhook
+
+type (
+	CreateWebhookRequestDTO struct {
+		URL         string   `json:"url" binding:"required,url,max=2048"`
+		Events      []string `json:"events" binding:"required,min=1,dive,required"`
+		Description string   `json:"description" binding:"max=255"`
+	}
+
+	UpdateWebhookRequestDTO struct {
+		URL         *string   `json:"url" binding:"omitempty,url,max=2048"`
+		Events      *[]string `json:"events" binding:"omitempty,min=1,dive,required"`
+		Description *string   `json:"description" binding:"omitempty,max=255"`
+		IsActive    *bool     `json:"is_active"`
+	}
+
+	GetWebhook struct {
+		ID          string   `json:"id"`
+		URL         string   `json:"url"`
+		Description string   `json:"description"`
+		Events      []string `json:"events"`
+		IsActive    bool     `json:"is_active"`
+		CreatedAt   string   `json:"created_at"`
+		// Secret is only returned when the webhook is created.
+		Secret *string `json:"secret,omitempty"`
+	}
+
+	GetAllWebhooksResponseDTO struct {
+		Webhooks []GetWebhook `json:"webhooks"`
+	}
+
+	GetWebhookDelivery struct {
+		ID             string  `json:"id"`
+		Event          string  `json:"event"`
+		Status         string  `json:"status"`
+		Attempts       int     `json:"attempts"`
+		NextAttemptAt  *string `json:"next_attempt_at"`
+		LastAttemptAt  *string `json:"last_attempt_at"`
+		ResponseStatus *int    `json:"response_status"`
+		Error          string  `json:"error"`
+		DurationMs     *int    `json:"duration_ms"`
+		DeliveredAt    *string `json:"delivered_at"`
+		Payload        string  `json:"payload"`
+		CreatedAt      string  `json:"created_at"`
+	}
+
+	GetWebhookDeliveriesResponseDTO struct {
+		Deliveries []GetWebhookDelivery `json:"deliveries"`
+	}
+)
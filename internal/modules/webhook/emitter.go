@@ -0,0 +1,181 @@
+package webhook
+
+import (
+	"encoding/json"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type event struct {
+	userID     uuid.UUID
+	name       string
+	data       any
+	occurredAt time.Time
+}
+
+// Envelope is the JSON body of every webhook request. ID is the delivery ID
+// and stays the same across retries, so receivers can drop duplicates.
+type Envelope struct {
+	ID         string          `json:"id"`
+	Event      string          `json:"event"`
+	OccurredAt string          `json:"occurred_at"`
+	Data       json.RawMessage `json:"data"`
+}
+
+// emitter turns events into pending deliveries for every webhook subscribed
+// to them. Lookups and inserts happen on a background goroutine so callers,
+// the redirect path included, never wait on the database.
+type emitter struct {
+	repository IRepository
+	queue      chan event
+	done       chan struct{}
+	wg         sync.WaitGroup
+
+	// subscriptions holds the events each user has an active webhook for,
+	// so events nobody listens to, most clicks among them, are dropped
+	// before they take up room in the queue. It is nil until first loaded,
+	// and every event is queued until then.
+	mu            sync.RWMutex
+	subscriptions map[uuid.UUID]map[string]struct{}
+}
+
+func NewEmitter(repository IRepository, bufferSize int) *emitter {
+	return &emitter{
+		repository: repository,
+		queue:      make(chan event, bufferSize),
+		done:       make(chan struct{}),
+	}
+}
+
+func (em *emitter) Start() {
+	em.Refresh()
+	em.wg.Add(1)
+	go em.run()
+}
+
+// Stop queues the deliveries of every buffered event and waits for the
+// goroutine to exit.
+func (em *emitter) Stop() {
+	close(em.done)
+	em.wg.Wait()
+}
+
+// Emit enqueues an event without blocking. When the buffer is full the event
+// is dropped. data is encoded to JSON later, so it must not be changed after
+// the call.
+func (em *emitter) Emit(userID uuid.UUID, name string, data any) {
+	if !em.isSubscribed(userID, name) {
+		return
+	}
+
+	select {
+	case em.queue <- event{userID: userID, name: name, data: data, occurredAt: time.Now()}:
+	default:
+		log.Println("Webhook event buffer is full, dropping", name, "for", userID)
+	}
+}
+
+// Refresh reloads which users have active webhooks for which events. It runs
+// periodically and after webhooks change, so edits on other instances apply
+// within DefaultSubscriptionRefreshInterval.
+func (em *emitter) Refresh() {
+	webhooks, err := em.repository.GetActiveWebhookSubscriptions()
+	if err != nil {
+		log.Println("Failed to reload webhook subscriptions:", err)
+		return
+	}
+
+	subscriptions := make(map[uuid.UUID]map[string]struct{})
+	for _, webhook := range webhooks {
+		events, ok := subscriptions[webhook.UserID]
+		if !ok {
+			events = make(map[string]struct{})
+			subscriptions[webhook.UserID] = events
+		}
+		for _, name := range webhook.Events {
+			events[name] = struct{}{}
+		}
+	}
+
+	em.mu.Lock()
+	em.subscriptions = subscriptions
+	em.mu.Unlock()
+}
+
+func (em *emitter) isSubscribed(userID uuid.UUID, name string) bool {
+	em.mu.RLock()
+	defer em.mu.RUnlock()
+
+	if em.subscriptions == nil {
+		return true
+	}
+	_, ok := em.subscriptions[userID][name]
+	return ok
+}
+
+func (em *emitter) run() {
+	defer em.wg.Done()
+
+	ticker := time.NewTicker(DefaultSubscriptionRefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case ev := <-em.queue:
+			em.dispatch(ev)
+		case <-ticker.C:
+			em.Refresh()
+		case <-em.done:
+			for {
+				select {
+				case ev := <-em.queue:
+					em.dispatch(ev)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+func (em *emitter) dispatch(ev event) {
+	webhooks, err := em.repository.GetSubscribedWebhooks(ev.userID, ev.name)
+	if err != nil {
+		log.Println("Failed to get webhooks subscribed to", ev.name, err)
+		return
+	}
+	if len(webhooks) == 0 {
+		return
+	}
+
+	data, err := json.Marshal(ev.data)
+	if err != nil {
+		log.Println("Failed to encode", ev.name, "event:", err)
+		return
+	}
+
+	now := time.Now()
+	deliveries := make([]*WebhookDeliveryModel, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		delivery := NewWebhookDelivery(webhook.ID, ev.name, now)
+		payload, err := json.Marshal(Envelope{
+			ID:         delivery.ID.String(),
+			Event:      ev.name,
+			OccurredAt: ev.occurredAt.UTC().Format(time.RFC3339),
+			Data:       data,
+		})
+		if err != nil {
+			log.Println("Failed to encode", ev.name, "event:", err)
+			return
+		}
+		delivery.Payload = string(payload)
+		deliveries = append(deliveries, delivery)
+	}
+
+	if err := em.repository.CreateWebhookDeliveries(deliveries); err != nil {
+		log.Println("Failed to queue", len(deliveries), "webhook deliveries:", err)
+	}
+}
@@ -0,0 +1,102 @@
+package webhook
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type emitterRepositoryStub struct {
+	IRepository
+	webhooks   []*WebhookModel
+	deliveries []*WebhookDeliveryModel
+	lookups    int
+}
+
+func (s *emitterRepositoryStub) GetActiveWebhookSubscriptions() ([]*WebhookModel, error) {
+	var webhooks []*WebhookModel
+	for _, webhook := range s.webhooks {
+		if webhook.IsActive {
+			webhooks = append(webhooks, webhook)
+		}
+	}
+	return webhooks, nil
+}
+
+func (s *emitterRepositoryStub) GetSubscribedWebhooks(userID uuid.UUID, event string) ([]*WebhookModel, error) {
+	s.lookups++
+	var webhooks []*WebhookModel
+	for _, webhook := range s.webhooks {
+		if webhook.UserID == userID && webhook.IsActive && webhook.IsSubscribedTo(event) {
+			webhooks = append(webhooks, webhook)
+		}
+	}
+	return webhooks, nil
+}
+
+func (s *emitterRepositoryStub) CreateWebhookDeliveries(data []*WebhookDeliveryModel) error {
+	s.deliveries = append(s.deliveries, data...)
+	return nil
+}
+
+func TestEmitter_QueuesDeliveriesForSubscribers(t *testing.T) {
+	userID := uuid.New()
+	clicks := NewWebhook(userID, "https://example.com/clicks", "", "whsec_a", []string{"link.clicked"})
+	all := NewWebhook(userID, "https://example.com/all", "", "whsec_b", []string{"link.created", "link.clicked"})
+	inactive := NewWebhook(userID, "https://example.com/off", "", "whsec_c", []string{"link.clicked"})
+	inactive.IsActive = false
+	other := NewWebhook(uuid.New(), "https://example.com/other", "", "whsec_d", []string{"link.clicked"})
+
+	repo := &emitterRepositoryStub{webhooks: []*WebhookModel{clicks, all, inactive, other}}
+	emitter := NewEmitter(repo, 10)
+	emitter.Start()
+	emitter.Emit(userID, "link.clicked", map[string]string{"shortener_url": "abc"})
+	emitter.Emit(userID, "link.deleted", map[string]string{"shortener_url": "abc"})
+	emitter.Stop()
+
+	assert.Len(t, repo.deliveries, 2)
+	for i, webhook := range []*WebhookModel{clicks, all} {
+		delivery := repo.deliveries[i]
+		assert.Equal(t, webhook.ID, delivery.WebhookID)
+		assert.Equal(t, DeliveryStatusPending, delivery.Status)
+		assert.NotNil(t, delivery.NextAttemptAt)
+
+		var envelope Envelope
+		assert.NoError(t, json.Unmarshal([]byte(delivery.Payload), &envelope))
+		assert.Equal(t, delivery.ID.String(), envelope.ID)
+		assert.Equal(t, "link.clicked", envelope.Event)
+		assert.JSONEq(t, `{"shortener_url":"abc"}`, string(envelope.Data))
+	}
+}
+
+func TestEmitter_DropsEventsWithoutSubscribers(t *testing.T) {
+	userID := uuid.New()
+	created := NewWebhook(userID, "https://example.com/created", "", "whsec_a", []string{"link.created"})
+	inactive := NewWebhook(userID, "https://example.com/off", "", "whsec_b", []string{"link.clicked"})
+	inactive.IsActive = false
+
+	repo := &emitterRepositoryStub{webhooks: []*WebhookModel{created, inactive}}
+	emitter := NewEmitter(repo, 1)
+	emitter.Refresh()
+
+	// Clicks of users without a click webhook must not fill the buffer.
+	for i := 0; i < 10; i++ {
+		emitter.Emit(userID, "link.clicked", nil)
+		emitter.Emit(uuid.New(), "link.clicked", nil)
+	}
+	emitter.Emit(userID, "link.created", map[string]string{"shortener_url": "abc"})
+
+	emitter.Start()
+	emitter.Stop()
+
+	assert.Equal(t, 1, repo.lookups)
+	assert.Len(t, repo.deliveries, 1)
+	assert.Equal(t, created.ID, repo.deliveries[0].WebhookID)
+}
+
+func TestEmitter_QueuesEverythingBeforeFirstRefresh(t *testing.T) {
+	emitter := NewEmitter(&emitterRepositoryStub{}, 1)
+	assert.True(t, emitter.isSubscribed(uuid.New(), "link.clicked"))
+}
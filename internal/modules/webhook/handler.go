@@ -0,0 +1,227 @@
+package webhook
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/xcurvnubaim/njajal-gin-golang/internal/middleware"
+	"github.com/xcurvnubaim/njajal-gin-golang/internal/pkg/app"
+	CustomValidator "github.com/xcurvnubaim/njajal-gin-golang/internal/pkg/validator"
+)
+
+type Handler struct {
+	useCase IUseCase
+	app     *gin.Engine
+}
+
+func NewHandler(app *gin.Engine, useCase IUseCase, prefixApi string) {
+	handler := &Handler{
+		app:     app,
+		useCase: useCase,
+	}
+
+	handler.Routes(prefixApi)
+}
+
+func (h *Handler) Routes(prefix string) {
+	routes := h.app.Group(prefix)
+	{
+		routes.Use(middleware.AuthenticateJWT())
+		{
+			routes.POST("/", h.CreateWebhook)
+			routes.GET("/", h.GetAllWebhooks)
+			routes.GET("/:id", h.GetWebhook)
+			routes.PATCH("/:id", h.UpdateWebhook)
+			routes.DELETE("/:id", h.DeleteWebhook)
+			routes.GET("/:id/deliveries", h.GetWebhookDeliveries)
+			routes.POST("/:id/deliveries/:delivery/retry", h.RetryWebhookDelivery)
+		}
+	}
+}
+
+func (h *Handler) CreateWebhook(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(401, app.NewErrorResponse("Invalid user ID", nil))
+		return
+	}
+
+	var data CreateWebhookRequestDTO
+	if err := c.ShouldBindJSON(&data); err != nil {
+		var errMessages = CustomValidator.FormatValidationErrors(err)
+		c.JSON(400, app.NewErrorResponse("Validation Error", &errMessages))
+		return
+	}
+
+	res, err := h.useCase.CreateWebhook(userID, &data)
+	if err != nil {
+		errMsg := err.Error()
+		c.JSON(err.Code(), app.NewErrorResponse("Failed to create webhook", &errMsg))
+		return
+	}
+
+	c.JSON(200, app.NewSuccessResponse("Webhook created successfully", res))
+}
+
+func (h *Handler) GetAllWebhooks(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(401, app.NewErrorResponse("Invalid user ID", nil))
+		return
+	}
+
+	res, err := h.useCase.GetAllWebhooks(userID)
+	if err != nil {
+		errMsg := err.Error()
+		c.JSON(err.Code(), app.NewErrorResponse("Failed to get webhooks", &errMsg))
+		return
+	}
+
+	c.JSON(200, app.NewSuccessResponse("Webhooks retrieved successfully", res))
+}
+
+func (h *Handler) GetWebhook(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(401, app.NewErrorResponse("Invalid user ID", nil))
+		return
+	}
+
+	id, errUuid := uuid.Parse(c.Param("id"))
+	if errUuid != nil {
+		c.JSON(400, app.NewErrorResponse("Invalid webhook ID", nil))
+		return
+	}
+
+	res, err := h.useCase.GetWebhook(userID, id)
+	if err != nil {
+		errMsg := err.Error()
+		c.JSON(err.Code(), app.NewErrorResponse("Failed to get webhook", &errMsg))
+		return
+	}
+
+	c.JSON(200, app.NewSuccessResponse("Webhook retrieved successfully", res))
+}
+
+func (h *Handler) UpdateWebhook(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(401, app.NewErrorResponse("Invalid user ID", nil))
+		return
+	}
+
+	id, errUuid := uuid.Parse(c.Param("id"))
+	if errUuid != nil {
+		c.JSON(400, app.NewErrorResponse("Invalid webhook ID", nil))
+		return
+	}
+
+	var data UpdateWebhookRequestDTO
+	if err := c.ShouldBindJSON(&data); err != nil {
+		var errMessages = CustomValidator.FormatValidationErrors(err)
+		c.JSON(400, app.NewErrorResponse("Validation Error", &errMessages))
+		return
+	}
+
+	res, err := h.useCase.UpdateWebhook(userID, id, &data)
+	if err != nil {
+		errMsg := err.Error()
+		c.JSON(err.Code(), app.NewErrorResponse("Failed to update webhook", &errMsg))
+		return
+	}
+
+	c.JSON(200, app.NewSuccessResponse("Webhook updated successfully", res))
+}
+
+func (h *Handler) DeleteWebhook(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(401, app.NewErrorResponse("Invalid user ID", nil))
+		return
+	}
+
+	id, errUuid := uuid.Parse(c.Param("id"))
+	if errUuid != nil {
+		c.JSON(400, app.NewErrorResponse("Invalid webhook ID", nil))
+		return
+	}
+
+	if err := h.useCase.DeleteWebhook(userID, id); err != nil {
+		errMsg := err.Error()
+		c.JSON(err.Code(), app.NewErrorResponse("Failed to delete webhook", &errMsg))
+		return
+	}
+
+	c.JSON(200, app.NewSuccessResponse[any]("Webhook deleted successfully", nil))
+}
+
+func (h *Handler) GetWebhookDeliveries(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(401, app.NewErrorResponse("Invalid user ID", nil))
+		return
+	}
+
+	id, errUuid := uuid.Parse(c.Param("id"))
+	if errUuid != nil {
+		c.JSON(400, app.NewErrorResponse("Invalid webhook ID", nil))
+		return
+	}
+
+	res, err := h.useCase.GetWebhookDeliveries(userID, id)
+	if err != nil {
+		errMsg := err.Error()
+		c.JSON(err.Code(), app.NewErrorResponse("Failed to get webhook deliveries", &errMsg))
+		return
+	}
+
+	c.JSON(200, app.NewSuccessResponse("Webhook deliveries retrieved successfully", res))
+}
+
+func (h *Handler) RetryWebhookDelivery(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(401, app.NewErrorResponse("Invalid user ID", nil))
+		return
+	}
+
+	id, errUuid := uuid.Parse(c.Param("id"))
+	if errUuid != nil {
+		c.JSON(400, app.NewErrorResponse("Invalid webhook ID", nil))
+		return
+	}
+
+	deliveryID, errUuid := uuid.Parse(c.Param("delivery"))
+	if errUuid != nil {
+		c.JSON(400, app.NewErrorResponse("Invalid delivery ID", nil))
+		return
+	}
+
+	res, err := h.useCase.RetryWebhookDelivery(userID, id, deliveryID)
+	if err != nil {
+		errMsg := err.Error()
+		c.JSON(err.Code(), app.NewErrorResponse("Failed to retry webhook delivery", &errMsg))
+		return
+	}
+
+	c.JSON(200, app.NewSuccessResponse("Webhook delivery queued successfully", res))
+}
+
+// getUserID reads the user_id claim set by middleware.AuthenticateJWT.
+func getUserID(c *gin.Context) (uuid.UUID, bool) {
+	userID, exist := c.Get("user_id")
+	if !exist {
+		return uuid.Nil, false
+	}
+
+	userIDStr, ok := userID.(string)
+	if !ok {
+		return uuid.Nil, false
+	}
+
+	parsedID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return uuid.Nil, false
+	}
+
+	return parsedID, true
+}
@@ -0,0 +1,193 @@
+package webhook
+
+import (
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type IRepository interface {
+	CreateWebhook(data *WebhookModel) error
+	GetWebhookByID(id uuid.UUID) (*WebhookModel, error)
+	GetAllWebhooks(userID uuid.UUID) ([]*WebhookModel, error)
+	CountWebhooks(userID uuid.UUID) (int64, error)
+	GetSubscribedWebhooks(userID uuid.UUID, event string) ([]*WebhookModel, error)
+	GetActiveWebhookSubscriptions() ([]*WebhookModel, error)
+	UpdateWebhook(data *WebhookModel) error
+	DeleteWebhook(data *WebhookModel) error
+	CreateWebhookDeliveries(data []*WebhookDeliveryModel) error
+	GetWebhookDeliveryByID(id uuid.UUID) (*WebhookDeliveryModel, error)
+	GetRecentWebhookDeliveries(webhookID uuid.UUID, limit int) ([]*WebhookDeliveryModel, error)
+	ClaimDueWebhookDeliveries(now, leaseUntil time.Time, limit int) ([]*WebhookDeliveryModel, error)
+	UpdateWebhookDelivery(data *WebhookDeliveryModel) error
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) *repository {
+	return &repository{db}
+}
+
+func (r *repository) CreateWebhook(data *WebhookModel) error {
+	err := r.db.Create(data).Error
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	return nil
+}
+
+func (r *repository) GetWebhookByID(id uuid.UUID) (*WebhookModel, error) {
+	var webhook WebhookModel
+	err := r.db.Where("id = ?", id).First(&webhook).Error
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	return &webhook, nil
+}
+
+func (r *repository) GetAllWebhooks(userID uuid.UUID) ([]*WebhookModel, error) {
+	var webhooks []*WebhookModel
+	err := r.db.Where("user_id = ?", userID).Order("created_at").Find(&webhooks).Error
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	return webhooks, nil
+}
+
+func (r *repository) CountWebhooks(userID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.Model(&WebhookModel{}).Where("user_id = ?", userID).Count(&count).Error
+	if err != nil {
+		log.Println(err)
+		return 0, err
+	}
+	return count, nil
+}
+
+// GetSubscribedWebhooks returns the active webhooks of the user that listen
+// to event.
+func (r *repository) GetSubscribedWebhooks(userID uuid.UUID, event string) ([]*WebhookModel, error) {
+	var webhooks []*WebhookModel
+	err := r.db.Where("user_id = ? AND is_active = ? AND ? = ANY(events)", userID, true, event).Find(&webhooks).Error
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	return webhooks, nil
+}
+
+// GetActiveWebhookSubscriptions returns the owner and events of every active
+// webhook.
+func (r *repository) GetActiveWebhookSubscriptions() ([]*WebhookModel, error) {
+	var webhooks []*WebhookModel
+	err := r.db.Select("user_id", "events").Where("is_active = ?", true).Find(&webhooks).Error
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	return webhooks, nil
+}
+
+func (r *repository) UpdateWebhook(data *WebhookModel) error {
+	err := r.db.Save(data).Error
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	return nil
+}
+
+func (r *repository) DeleteWebhook(data *WebhookModel) error {
+	err := r.db.Delete(data).Error
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	return nil
+}
+
+func (r *repository) CreateWebhookDeliveries(data []*WebhookDeliveryModel) error {
+	err := r.db.Omit(clause.Associations).CreateInBatches(data, 100).Error
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	return nil
+}
+
+func (r *repository) GetWebhookDeliveryByID(id uuid.UUID) (*WebhookDeliveryModel, error) {
+	var delivery WebhookDeliveryModel
+	err := r.db.Where("id = ?", id).First(&delivery).Error
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+// GetRecentWebhookDeliveries returns the latest deliveries of a webhook,
+// newest first.
+func (r *repository) GetRecentWebhookDeliveries(webhookID uuid.UUID, limit int) ([]*WebhookDeliveryModel, error) {
+	var deliveries []*WebhookDeliveryModel
+	err := r.db.Where("webhook_id = ?", webhookID).Order("created_at DESC").Limit(limit).Find(&deliveries).Error
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// ClaimDueWebhookDeliveries picks pending deliveries of active webhooks that
+// are due and pushes their next attempt to leaseUntil, so no other worker
+// picks them while they are being sent. A worker that dies mid-delivery only
+// delays the delivery until the lease runs out. The claimed deliveries are
+// returned with their webhook.
+func (r *repository) ClaimDueWebhookDeliveries(now, leaseUntil time.Time, limit int) ([]*WebhookDeliveryModel, error) {
+	var ids []uuid.UUID
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&WebhookDeliveryModel{}).
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", DeliveryStatusPending, now).
+			Where("webhook_id IN (?)", tx.Model(&WebhookModel{}).Select("id").Where("is_active = ?", true)).
+			Order("next_attempt_at").Limit(limit).
+			Pluck("id", &ids).Error
+		if err != nil || len(ids) == 0 {
+			return err
+		}
+
+		return tx.Model(&WebhookDeliveryModel{}).Where("id IN ?", ids).UpdateColumn("next_attempt_at", leaseUntil).Error
+	})
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	var deliveries []*WebhookDeliveryModel
+	err = r.db.Preload("Webhook", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+		Where("id IN ?", ids).Order("created_at").Find(&deliveries).Error
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+func (r *repository) UpdateWebhookDelivery(data *WebhookDeliveryModel) error {
+	err := r.db.Omit(clause.Associations).Save(data).Error
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrSignatureInvalid = errors.New("Webhook signature is invalid")
+	ErrTimestampExpired = errors.New("Webhook timestamp is outside the tolerance")
+)
+
+// Sign returns the HeaderSignature value of body sent at timestamp, a Unix
+// time in seconds. The timestamp is part of the signed message so a captured
+// request cannot be replayed later under a new timestamp.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return SignatureScheme + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks the signature and timestamp headers of a received webhook.
+// Timestamps further than tolerance from now are rejected.
+func Verify(secret, signature, timestamp string, body []byte, tolerance time.Duration, now time.Time) error {
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrSignatureInvalid
+	}
+
+	if diff := now.Sub(time.Unix(ts, 0)); diff > tolerance || diff < -tolerance {
+		return ErrTimestampExpired
+	}
+
+	if !strings.HasPrefix(signature, SignatureScheme) {
+		return ErrSignatureInvalid
+	}
+	if !hmac.Equal([]byte(signature), []byte(Sign(secret, ts, body))) {
+		return ErrSignatureInvalid
+	}
+	return nil
+}
+
+func newSecret() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return SecretPrefix + hex.EncodeToString(secret), nil
+}
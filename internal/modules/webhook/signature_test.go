@@ -0,0 +1,52 @@
+package webhook
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSign(t *testing.T) {
+	body := []byte(`{"event":"link.created"}`)
+
+	signature := Sign("whsec_test", 1700000000, body)
+	assert.True(t, strings.HasPrefix(signature, SignatureScheme))
+	assert.Equal(t, signature, Sign("whsec_test", 1700000000, body))
+	assert.NotEqual(t, signature, Sign("whsec_other", 1700000000, body))
+	assert.NotEqual(t, signature, Sign("whsec_test", 1700000001, body))
+	assert.NotEqual(t, signature, Sign("whsec_test", 1700000000, []byte(`{"event":"link.deleted"}`)))
+}
+
+func TestVerify(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	body := []byte(`{"event":"link.created"}`)
+	signature := Sign("whsec_test", now.Unix(), body)
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+
+	tests := []struct {
+		name      string
+		signature string
+		timestamp string
+		body      []byte
+		now       time.Time
+		err       error
+	}{
+		{name: "Valid", signature: signature, timestamp: timestamp, body: body, now: now},
+		{name: "Within tolerance", signature: signature, timestamp: timestamp, body: body, now: now.Add(4 * time.Minute)},
+		{name: "Tampered body", signature: signature, timestamp: timestamp, body: []byte(`{}`), now: now, err: ErrSignatureInvalid},
+		{name: "Replayed timestamp", signature: signature, timestamp: "1700000060", body: body, now: now, err: ErrSignatureInvalid},
+		{name: "Missing scheme", signature: strings.TrimPrefix(signature, SignatureScheme), timestamp: timestamp, body: body, now: now, err: ErrSignatureInvalid},
+		{name: "Malformed timestamp", signature: signature, timestamp: "yesterday", body: body, now: now, err: ErrSignatureInvalid},
+		{name: "Too old", signature: signature, timestamp: timestamp, body: body, now: now.Add(10 * time.Minute), err: ErrTimestampExpired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Verify("whsec_test", tt.signature, tt.timestamp, tt.body, 5*time.Minute, tt.now)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
@@ -0,0 +1,285 @@
+package webhook
+
+import (
+	"fmt"
+	"log"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/xcurvnubaim/njajal-gin-golang/internal/modules/shortlink"
+	"github.com/xcurvnubaim/njajal-gin-golang/internal/pkg/e"
+)
+
+type IUseCase interface {
+	CreateWebhook(userID uuid.UUID, data *CreateWebhookRequestDTO) (*GetWebhook, e.ApiError)
+	GetAllWebhooks(userID uuid.UUID) (*GetAllWebhooksResponseDTO, e.ApiError)
+	GetWebhook(userID, id uuid.UUID) (*GetWebhook, e.ApiError)
+	UpdateWebhook(userID, id uuid.UUID, data *UpdateWebhookRequestDTO) (*GetWebhook, e.ApiError)
+	DeleteWebhook(userID, id uuid.UUID) e.ApiError
+	GetWebhookDeliveries(userID, id uuid.UUID) (*GetWebhookDeliveriesResponseDTO, e.ApiError)
+	RetryWebhookDelivery(userID, id, deliveryID uuid.UUID) (*GetWebhookDelivery, e.ApiError)
+}
+
+// URLChecker normalizes a webhook URL and rejects the ones the server must
+// not call, such as private hosts. *shortlink.URLPolicy satisfies it.
+type URLChecker interface {
+	Check(rawURL string) (string, error)
+}
+
+// SubscriptionRefresher reloads which users have webhooks once they change.
+// The emitter returned by NewEmitter satisfies it.
+type SubscriptionRefresher interface {
+	Refresh()
+}
+
+type useCase struct {
+	repository    IRepository
+	urlChecker    URLChecker
+	subscriptions SubscriptionRefresher
+}
+
+func NewuseCase(repository IRepository, urlChecker URLChecker, subscriptions SubscriptionRefresher) *useCase {
+	return &useCase{repository, urlChecker, subscriptions}
+}
+
+func (uc *useCase) CreateWebhook(userID uuid.UUID, data *CreateWebhookRequestDTO) (*GetWebhook, e.ApiError) {
+	url, errApi := uc.checkURL(data.URL)
+	if errApi != nil {
+		return nil, errApi
+	}
+
+	events, errApi := normalizeEvents(data.Events)
+	if errApi != nil {
+		return nil, errApi
+	}
+
+	count, err := uc.repository.CountWebhooks(userID)
+	if err != nil {
+		return nil, e.NewApiError(500, err.Error())
+	}
+	if count >= MaxWebhooksPerUser {
+		return nil, e.NewApiError(400, fmt.Sprintf("A user can have at most %d webhooks", MaxWebhooksPerUser))
+	}
+
+	secret, err := newSecret()
+	if err != nil {
+		log.Println(err)
+		return nil, e.NewApiError(500, "Failed to generate webhook secret")
+	}
+
+	webhook := NewWebhook(userID, url, data.Description, secret, events)
+	if err := uc.repository.CreateWebhook(webhook); err != nil {
+		return nil, e.NewApiError(500, err.Error())
+	}
+	uc.refreshSubscriptions()
+
+	// The secret is shown once; receivers need it to verify signatures.
+	res := toGetWebhook(webhook)
+	res.Secret = &webhook.Secret
+	return &res, nil
+}
+
+func (uc *useCase) GetAllWebhooks(userID uuid.UUID) (*GetAllWebhooksResponseDTO, e.ApiError) {
+	webhooks, err := uc.repository.GetAllWebhooks(userID)
+	if err != nil {
+		return nil, e.NewApiError(500, err.Error())
+	}
+
+	data := make([]GetWebhook, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		data = append(data, toGetWebhook(webhook))
+	}
+
+	return &GetAllWebhooksResponseDTO{Webhooks: data}, nil
+}
+
+func (uc *useCase) GetWebhook(userID, id uuid.UUID) (*GetWebhook, e.ApiError) {
+	webhook, errApi := uc.getOwnedWebhook(userID, id)
+	if errApi != nil {
+		return nil, errApi
+	}
+
+	res := toGetWebhook(webhook)
+	return &res, nil
+}
+
+func (uc *useCase) UpdateWebhook(userID, id uuid.UUID, data *UpdateWebhookRequestDTO) (*GetWebhook, e.ApiError) {
+	webhook, errApi := uc.getOwnedWebhook(userID, id)
+	if errApi != nil {
+		return nil, errApi
+	}
+
+	if data.URL != nil {
+		url, errApi := uc.checkURL(*data.URL)
+		if errApi != nil {
+			return nil, errApi
+		}
+		webhook.URL = url
+	}
+
+	if data.Events != nil {
+		events, errApi := normalizeEvents(*data.Events)
+		if errApi != nil {
+			return nil, errApi
+		}
+		webhook.Events = events
+	}
+
+	if data.Description != nil {
+		webhook.Description = *data.Description
+	}
+
+	if data.IsActive != nil {
+		webhook.IsActive = *data.IsActive
+	}
+
+	if err := uc.repository.UpdateWebhook(webhook); err != nil {
+		return nil, e.NewApiError(500, err.Error())
+	}
+	uc.refreshSubscriptions()
+
+	res := toGetWebhook(webhook)
+	return &res, nil
+}
+
+func (uc *useCase) DeleteWebhook(userID, id uuid.UUID) e.ApiError {
+	webhook, errApi := uc.getOwnedWebhook(userID, id)
+	if errApi != nil {
+		return errApi
+	}
+
+	if err := uc.repository.DeleteWebhook(webhook); err != nil {
+		return e.NewApiError(500, err.Error())
+	}
+	uc.refreshSubscriptions()
+
+	return nil
+}
+
+// GetWebhookDeliveries returns the delivery log of a webhook, newest first.
+func (uc *useCase) GetWebhookDeliveries(userID, id uuid.UUID) (*GetWebhookDeliveriesResponseDTO, e.ApiError) {
+	webhook, errApi := uc.getOwnedWebhook(userID, id)
+	if errApi != nil {
+		return nil, errApi
+	}
+
+	deliveries, err := uc.repository.GetRecentWebhookDeliveries(webhook.ID, recentDeliveriesLimit)
+	if err != nil {
+		return nil, e.NewApiError(500, err.Error())
+	}
+
+	data := make([]GetWebhookDelivery, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		data = append(data, toGetWebhookDelivery(delivery))
+	}
+
+	return &GetWebhookDeliveriesResponseDTO{Deliveries: data}, nil
+}
+
+// RetryWebhookDelivery queues a delivered or failed delivery to be sent again
+// with the same payload and delivery ID, and a fresh budget of attempts.
+func (uc *useCase) RetryWebhookDelivery(userID, id, deliveryID uuid.UUID) (*GetWebhookDelivery, e.ApiError) {
+	webhook, errApi := uc.getOwnedWebhook(userID, id)
+	if errApi != nil {
+		return nil, errApi
+	}
+
+	if !webhook.IsActive {
+		return nil, e.NewApiError(400, "Webhook is not active")
+	}
+
+	delivery, err := uc.repository.GetWebhookDeliveryByID(deliveryID)
+	if err != nil || delivery.WebhookID != webhook.ID {
+		return nil, e.NewApiError(404, "Webhook delivery not found")
+	}
+
+	if delivery.Status == DeliveryStatusPending {
+		return nil, e.NewApiError(400, "Webhook delivery is already pending")
+	}
+
+	delivery.Requeue(time.Now())
+	if err := uc.repository.UpdateWebhookDelivery(delivery); err != nil {
+		return nil, e.NewApiError(500, err.Error())
+	}
+
+	res := toGetWebhookDelivery(delivery)
+	return &res, nil
+}
+
+func (uc *useCase) refreshSubscriptions() {
+	if uc.subscriptions != nil {
+		uc.subscriptions.Refresh()
+	}
+}
+
+func (uc *useCase) checkURL(rawURL string) (string, e.ApiError) {
+	url, err := uc.urlChecker.Check(rawURL)
+	if err != nil {
+		return "", e.NewApiError(400, err.Error())
+	}
+	return url, nil
+}
+
+// normalizeEvents rejects unknown events and drops duplicates.
+func normalizeEvents(events []string) ([]string, e.ApiError) {
+	normalized := make([]string, 0, len(events))
+	for _, event := range events {
+		if !slices.Contains(shortlink.LinkEvents, event) {
+			return nil, e.NewApiError(400, fmt.Sprintf("Unknown event %q", event))
+		}
+		if !slices.Contains(normalized, event) {
+			normalized = append(normalized, event)
+		}
+	}
+	return normalized, nil
+}
+
+func (uc *useCase) getOwnedWebhook(userID, id uuid.UUID) (*WebhookModel, e.ApiError) {
+	webhook, err := uc.repository.GetWebhookByID(id)
+	if err != nil {
+		return nil, e.NewApiError(404, "Webhook not found")
+	}
+
+	if !webhook.IsOwnedBy(userID) {
+		return nil, e.NewApiError(403, "You do not have permission to access this webhook")
+	}
+
+	return webhook, nil
+}
+
+func toGetWebhook(webhook *WebhookModel) GetWebhook {
+	return GetWebhook{
+		ID:          webhook.ID.String(),
+		URL:         webhook.URL,
+		Description: webhook.Description,
+		Events:      webhook.Events,
+		IsActive:    webhook.IsActive,
+		CreatedAt:   webhook.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
+
+func toGetWebhookDelivery(delivery *WebhookDeliveryModel) GetWebhookDelivery {
+	return GetWebhookDelivery{
+		ID:             delivery.ID.String(),
+		Event:          delivery.Event,
+		Status:         delivery.Status,
+		Attempts:       delivery.Attempts,
+		NextAttemptAt:  formatTime(delivery.NextAttemptAt),
+		LastAttemptAt:  formatTime(delivery.LastAttemptAt),
+		ResponseStatus: delivery.ResponseStatus,
+		Error:          delivery.Error,
+		DurationMs:     delivery.DurationMs,
+		DeliveredAt:    formatTime(delivery.DeliveredAt),
+		Payload:        delivery.Payload,
+		CreatedAt:      delivery.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
+
+func formatTime(t *time.Time) *string {
+	if t == nil {
+		return nil
+	}
+	formatted := t.Format("2006-01-02 15:04:05")
+	return &formatted
+}
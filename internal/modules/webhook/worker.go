@@ -0,0 +1,267 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/xcurvnubaim/njajal-gin-golang/internal/pkg/safehttp"
+)
+
+type DeliveryWorkerOptions struct {
+	// Interval is how often the worker looks for deliveries that are due.
+	Interval time.Duration
+	// Timeout bounds each request.
+	Timeout time.Duration
+	// Lease is how long a claimed delivery stays hidden from other workers.
+	Lease       time.Duration
+	Concurrency int
+	// BatchSize is the maximum number of deliveries sent per interval.
+	BatchSize int
+	// MaxAttempts is the number of attempts after which a delivery fails.
+	MaxAttempts int
+	// RetryBaseDelay is the wait after the first failed attempt. It doubles
+	// with every attempt, up to RetryMaxDelay.
+	RetryBaseDelay time.Duration
+	RetryMaxDelay  time.Duration
+	// Client defaults to an http.Client that does not follow redirects and
+	// only connects to public addresses.
+	Client *http.Client
+}
+
+// DeliveryAttempt is the outcome of one request to a webhook.
+type DeliveryAttempt struct {
+	StatusCode  int
+	Error       string
+	Duration    time.Duration
+	AttemptedAt time.Time
+}
+
+// Succeeded reports whether the receiver acknowledged the delivery with a 2xx
+// status.
+func (a *DeliveryAttempt) Succeeded() bool {
+	return a.Error == "" && a.StatusCode >= 200 && a.StatusCode < 300
+}
+
+// deliveryWorker sends pending deliveries and retries failed ones with
+// exponential backoff until they succeed or run out of attempts.
+type deliveryWorker struct {
+	repository IRepository
+	options    DeliveryWorkerOptions
+	client     *http.Client
+	done       chan struct{}
+	wg         sync.WaitGroup
+}
+
+func NewDeliveryWorker(repository IRepository, options DeliveryWorkerOptions) *deliveryWorker {
+	if options.Interval <= 0 {
+		options.Interval = DefaultDeliveryInterval
+	}
+	if options.Timeout <= 0 {
+		options.Timeout = DefaultDeliveryTimeout
+	}
+	if options.Lease <= 0 {
+		options.Lease = DefaultDeliveryLease
+	}
+	if options.Concurrency <= 0 {
+		options.Concurrency = DefaultDeliveryConcurrency
+	}
+	if options.BatchSize <= 0 {
+		options.BatchSize = DefaultDeliveryBatchSize
+	}
+	if options.MaxAttempts <= 0 {
+		options.MaxAttempts = DefaultMaxAttempts
+	}
+	if options.RetryBaseDelay <= 0 {
+		options.RetryBaseDelay = DefaultRetryBaseDelay
+	}
+	if options.RetryMaxDelay <= 0 {
+		options.RetryMaxDelay = DefaultRetryMaxDelay
+	}
+
+	client := options.Client
+	if client == nil {
+		// The URL check only sees the hostname, so the transport checks
+		// the resolved address too: a name pointing at an internal
+		// service is refused before the request is sent. Redirects are
+		// not followed for the same reason.
+		client = &http.Client{
+			Transport: safehttp.NewTransport(),
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		}
+	}
+
+	return &deliveryWorker{
+		repository: repository,
+		options:    options,
+		client:     client,
+		done:       make(chan struct{}),
+	}
+}
+
+func (w *deliveryWorker) Start() {
+	w.wg.Add(1)
+	go w.run()
+}
+
+// Stop waits for the deliveries in flight to finish.
+func (w *deliveryWorker) Stop() {
+	close(w.done)
+	w.wg.Wait()
+}
+
+func (w *deliveryWorker) run() {
+	defer w.wg.Done()
+
+	ticker := time.NewTicker(w.options.Interval)
+	defer ticker.Stop()
+
+	w.deliverDue()
+	for {
+		select {
+		case <-ticker.C:
+			w.deliverDue()
+		case <-w.done:
+			return
+		}
+	}
+}
+
+// deliverDue sends one batch of due deliveries, at most Concurrency at a
+// time.
+func (w *deliveryWorker) deliverDue() {
+	now := time.Now()
+	deliveries, err := w.repository.ClaimDueWebhookDeliveries(now, now.Add(w.options.Lease), w.options.BatchSize)
+	if err != nil {
+		log.Println("Failed to claim due webhook deliveries:", err)
+		return
+	}
+
+	slots := make(chan struct{}, w.options.Concurrency)
+	var wg sync.WaitGroup
+	for _, delivery := range deliveries {
+		select {
+		case <-w.done:
+			// Unsent deliveries are picked up again once their lease
+			// runs out.
+			wg.Wait()
+			return
+		case slots <- struct{}{}:
+		}
+
+		wg.Add(1)
+		go func(delivery *WebhookDeliveryModel) {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			w.recordAttempt(delivery, w.Send(delivery))
+			if err := w.repository.UpdateWebhookDelivery(delivery); err != nil {
+				log.Println("Failed to store webhook delivery", delivery.ID, err)
+			}
+		}(delivery)
+	}
+	wg.Wait()
+}
+
+// Send posts the delivery payload to its webhook, signed with the webhook
+// secret.
+func (w *deliveryWorker) Send(delivery *WebhookDeliveryModel) DeliveryAttempt {
+	attempt := DeliveryAttempt{AttemptedAt: time.Now()}
+
+	ctx, cancel := context.WithTimeout(context.Background(), w.options.Timeout)
+	defer cancel()
+
+	body := []byte(delivery.Payload)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.Webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		attempt.Error = truncate(err.Error(), maxDeliveryErrorLength)
+		return attempt
+	}
+
+	timestamp := attempt.AttemptedAt.Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", DeliveryUserAgent)
+	req.Header.Set(HeaderEvent, delivery.Event)
+	req.Header.Set(HeaderDelivery, delivery.ID.String())
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(delivery.Webhook.Secret, timestamp, body))
+
+	res, err := w.client.Do(req)
+	attempt.Duration = time.Since(attempt.AttemptedAt)
+	if err != nil {
+		var urlErr interface{ Timeout() bool }
+		if errors.As(err, &urlErr) && urlErr.Timeout() {
+			attempt.Error = "timed out"
+		} else {
+			attempt.Error = truncate(err.Error(), maxDeliveryErrorLength)
+		}
+		return attempt
+	}
+	defer res.Body.Close()
+
+	// Only the status is kept; the body is drained so the connection can be
+	// reused.
+	_, _ = io.CopyN(io.Discard, res.Body, 4<<10)
+
+	attempt.StatusCode = res.StatusCode
+	return attempt
+}
+
+// recordAttempt stores the attempt on the delivery and decides what comes
+// next: done, failed for good, or another attempt after a backoff.
+func (w *deliveryWorker) recordAttempt(delivery *WebhookDeliveryModel, attempt DeliveryAttempt) {
+	durationMs := int(attempt.Duration.Milliseconds())
+
+	delivery.Attempts++
+	delivery.LastAttemptAt = &attempt.AttemptedAt
+	delivery.ResponseStatus = nil
+	if attempt.StatusCode != 0 {
+		delivery.ResponseStatus = &attempt.StatusCode
+	}
+	delivery.Error = attempt.Error
+	delivery.DurationMs = &durationMs
+
+	switch {
+	case attempt.Succeeded():
+		delivery.Status = DeliveryStatusDelivered
+		delivery.DeliveredAt = &attempt.AttemptedAt
+		delivery.NextAttemptAt = nil
+	case delivery.Attempts >= w.options.MaxAttempts:
+		delivery.Status = DeliveryStatusFailed
+		delivery.NextAttemptAt = nil
+	default:
+		next := attempt.AttemptedAt.Add(retryDelay(delivery.Attempts, w.options.RetryBaseDelay, w.options.RetryMaxDelay))
+		delivery.NextAttemptAt = &next
+	}
+}
+
+// retryDelay is the wait after the given number of failed attempts: base,
+// then doubling each time, capped at max.
+func retryDelay(attempts int, base, max time.Duration) time.Duration {
+	delay := base
+	for i := 1; i < attempts; i++ {
+		if delay >= max/2 {
+			return max
+		}
+		delay *= 2
+	}
+	if delay > max {
+		return max
+	}
+	return delay
+}
+
+func truncate(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	return s[:max]
+}
@@ -0,0 +1,151 @@
+package webhook
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type deliveryRepositoryStub struct {
+	IRepository
+	deliveries []*WebhookDeliveryModel
+	mu         sync.Mutex
+	updated    map[uuid.UUID]WebhookDeliveryModel
+}
+
+func (s *deliveryRepositoryStub) ClaimDueWebhookDeliveries(now, leaseUntil time.Time, limit int) ([]*WebhookDeliveryModel, error) {
+	return s.deliveries, nil
+}
+
+func (s *deliveryRepositoryStub) UpdateWebhookDelivery(data *WebhookDeliveryModel) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.updated == nil {
+		s.updated = make(map[uuid.UUID]WebhookDeliveryModel)
+	}
+	s.updated[data.ID] = *data
+	return nil
+}
+
+func newTestDelivery(url string) *WebhookDeliveryModel {
+	webhook := NewWebhook(uuid.New(), url, "", "whsec_test", []string{"link.created"})
+	delivery := NewWebhookDelivery(webhook.ID, "link.created", time.Now())
+	delivery.Payload = `{"id":"` + delivery.ID.String() + `","event":"link.created"}`
+	delivery.Webhook = webhook
+	return delivery
+}
+
+// newTestDeliveryWorker lets the worker reach httptest servers, which listen
+// on loopback addresses the default transport refuses.
+func newTestDeliveryWorker(repository IRepository, options DeliveryWorkerOptions) *deliveryWorker {
+	worker := NewDeliveryWorker(repository, options)
+	worker.client.Transport = http.DefaultTransport
+	return worker
+}
+
+func TestRetryDelay(t *testing.T) {
+	base, max := 30*time.Second, time.Hour
+
+	assert.Equal(t, 30*time.Second, retryDelay(1, base, max))
+	assert.Equal(t, time.Minute, retryDelay(2, base, max))
+	assert.Equal(t, 4*time.Minute, retryDelay(4, base, max))
+	assert.Equal(t, 32*time.Minute, retryDelay(7, base, max))
+	assert.Equal(t, time.Hour, retryDelay(8, base, max))
+	assert.Equal(t, time.Hour, retryDelay(100, base, max))
+}
+
+func TestDeliveryWorker_SendSignsPayload(t *testing.T) {
+	var received *http.Request
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		body, _ = io.ReadAll(r.Body)
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	delivery := newTestDelivery(server.URL)
+	attempt := newTestDeliveryWorker(nil, DeliveryWorkerOptions{}).Send(delivery)
+
+	assert.True(t, attempt.Succeeded())
+	assert.Equal(t, delivery.Payload, string(body))
+	assert.Equal(t, "link.created", received.Header.Get(HeaderEvent))
+	assert.Equal(t, delivery.ID.String(), received.Header.Get(HeaderDelivery))
+	assert.Equal(t, strconv.FormatInt(attempt.AttemptedAt.Unix(), 10), received.Header.Get(HeaderTimestamp))
+	assert.NoError(t, Verify("whsec_test", received.Header.Get(HeaderSignature), received.Header.Get(HeaderTimestamp), body, time.Minute, time.Now()))
+}
+
+func TestDeliveryWorker_DoesNotFollowRedirects(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "http://169.254.169.254/", http.StatusFound)
+	}))
+	defer server.Close()
+
+	attempt := newTestDeliveryWorker(nil, DeliveryWorkerOptions{}).Send(newTestDelivery(server.URL))
+
+	assert.Equal(t, http.StatusFound, attempt.StatusCode)
+	assert.False(t, attempt.Succeeded())
+}
+
+func TestDeliveryWorker_RefusesInternalAddresses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("secret"))
+	}))
+	defer server.Close()
+
+	attempt := NewDeliveryWorker(nil, DeliveryWorkerOptions{}).Send(newTestDelivery(server.URL))
+
+	assert.False(t, attempt.Succeeded())
+	assert.Zero(t, attempt.StatusCode)
+	assert.Contains(t, attempt.Error, "not public")
+}
+
+func TestDeliveryWorker_DeliverDue(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ok", func(w http.ResponseWriter, r *http.Request) {})
+	mux.HandleFunc("/error", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "boom", http.StatusInternalServerError)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	ok := newTestDelivery(server.URL + "/ok")
+	retried := newTestDelivery(server.URL + "/error")
+	exhausted := newTestDelivery(server.URL + "/error")
+	exhausted.Attempts = 2
+	unreachable := newTestDelivery("http://127.0.0.1:1/")
+
+	repo := &deliveryRepositoryStub{deliveries: []*WebhookDeliveryModel{ok, retried, exhausted, unreachable}}
+	worker := newTestDeliveryWorker(repo, DeliveryWorkerOptions{MaxAttempts: 3, RetryBaseDelay: time.Minute, Timeout: time.Second})
+	start := time.Now()
+	worker.deliverDue()
+
+	result := repo.updated[ok.ID]
+	assert.Equal(t, DeliveryStatusDelivered, result.Status)
+	assert.Equal(t, 1, result.Attempts)
+	assert.Equal(t, http.StatusOK, *result.ResponseStatus)
+	assert.NotNil(t, result.DeliveredAt)
+	assert.Nil(t, result.NextAttemptAt)
+
+	result = repo.updated[retried.ID]
+	assert.Equal(t, DeliveryStatusPending, result.Status)
+	assert.Equal(t, http.StatusInternalServerError, *result.ResponseStatus)
+	assert.WithinDuration(t, start.Add(time.Minute), *result.NextAttemptAt, 5*time.Second)
+
+	result = repo.updated[exhausted.ID]
+	assert.Equal(t, DeliveryStatusFailed, result.Status)
+	assert.Equal(t, 3, result.Attempts)
+	assert.Nil(t, result.NextAttemptAt)
+
+	result = repo.updated[unreachable.ID]
+	assert.Equal(t, DeliveryStatusPending, result.Status)
+	assert.Nil(t, result.ResponseStatus)
+	assert.NotEmpty(t, result.Error)
+}
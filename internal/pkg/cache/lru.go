@@ -0,0 +1,91 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+type lruEntry[K comparable, V any] struct {
+	key       K
+	value     V
+	expiresAt time.Time
+}
+
+// LRU is a size-bounded, concurrency-safe cache that evicts the least
+// recently used entry when full and treats entries past their TTL as missing.
+type LRU[K comparable, V any] struct {
+	mu       sync.Mutex
+	capacity int
+	items    map[K]*list.Element
+	order    *list.List
+	now      func() time.Time
+}
+
+func NewLRU[K comparable, V any](capacity int) *LRU[K, V] {
+	return &LRU[K, V]{
+		capacity: capacity,
+		items:    make(map[K]*list.Element, capacity),
+		order:    list.New(),
+		now:      time.Now,
+	}
+}
+
+func (c *LRU[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var zero V
+	element, ok := c.items[key]
+	if !ok {
+		return zero, false
+	}
+
+	entry := element.Value.(*lruEntry[K, V])
+	if c.now().After(entry.expiresAt) {
+		c.removeElement(element)
+		return zero, false
+	}
+
+	c.order.MoveToFront(element)
+	return entry.value, true
+}
+
+func (c *LRU[K, V]) Set(key K, value V, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	expiresAt := c.now().Add(ttl)
+	if element, ok := c.items[key]; ok {
+		entry := element.Value.(*lruEntry[K, V])
+		entry.value = value
+		entry.expiresAt = expiresAt
+		c.order.MoveToFront(element)
+		return
+	}
+
+	c.items[key] = c.order.PushFront(&lruEntry[K, V]{key: key, value: value, expiresAt: expiresAt})
+	if c.order.Len() > c.capacity {
+		c.removeElement(c.order.Back())
+	}
+}
+
+func (c *LRU[K, V]) Delete(key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.items[key]; ok {
+		c.removeElement(element)
+	}
+}
+
+func (c *LRU[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+func (c *LRU[K, V]) removeElement(element *list.Element) {
+	c.order.Remove(element)
+	delete(c.items, element.Value.(*lruEntry[K, V]).key)
+}
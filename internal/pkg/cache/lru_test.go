@@ -0,0 +1,67 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLRU_GetSet(t *testing.T) {
+	c := NewLRU[string, int](2)
+	c.Set("a", 1, time.Minute)
+
+	value, ok := c.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 1, value)
+
+	_, ok = c.Get("missing")
+	assert.False(t, ok)
+}
+
+func TestLRU_EvictsLeastRecentlyUsed(t *testing.T) {
+	c := NewLRU[string, int](2)
+	c.Set("a", 1, time.Minute)
+	c.Set("b", 2, time.Minute)
+
+	// Touch "a" so "b" becomes the eviction candidate.
+	c.Get("a")
+	c.Set("c", 3, time.Minute)
+
+	_, ok := c.Get("b")
+	assert.False(t, ok)
+	_, ok = c.Get("a")
+	assert.True(t, ok)
+	_, ok = c.Get("c")
+	assert.True(t, ok)
+	assert.Equal(t, 2, c.Len())
+}
+
+func TestLRU_Expiry(t *testing.T) {
+	now := time.Now()
+	c := NewLRU[string, int](2)
+	c.now = func() time.Time { return now }
+
+	c.Set("a", 1, time.Second)
+	_, ok := c.Get("a")
+	assert.True(t, ok)
+
+	now = now.Add(2 * time.Second)
+	_, ok = c.Get("a")
+	assert.False(t, ok)
+	assert.Equal(t, 0, c.Len())
+}
+
+func TestLRU_UpdateAndDelete(t *testing.T) {
+	c := NewLRU[string, int](2)
+	c.Set("a", 1, time.Minute)
+	c.Set("a", 2, time.Minute)
+
+	value, _ := c.Get("a")
+	assert.Equal(t, 2, value)
+	assert.Equal(t, 1, c.Len())
+
+	c.Delete("a")
+	_, ok := c.Get("a")
+	assert.False(t, ok)
+}
@@ -17,6 +17,10 @@ const (
 	ERROR_USER_NOT_FOUND = 10012
 	ERROR_USER_NOT_AUTHORIZED = 10013
 	ERROR_UPDATE_USER_REPOSITORY_FAILED = 10014
+	ERROR_CREATE_REFRESH_TOKEN_REPOSITORY_FAILED = 10015
+	ERROR_GET_REFRESH_TOKEN_REPOSITORY_FAILED = 10016
+	ERROR_ROTATE_REFRESH_TOKEN_REPOSITORY_FAILED = 10017
+	ERROR_REVOKE_REFRESH_TOKEN_REPOSITORY_FAILED = 10018
 
 	ERROR_REGISTER_REPOSITORY_FAILED = 10011
 	ERROR_GET_USER_BY_EMAIL_REPOSITORY_FAILED = 10012
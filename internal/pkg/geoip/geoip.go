@@ -0,0 +1,44 @@
+package geoip
+
+import (
+	"net"
+
+	"github.com/oschwald/geoip2-golang"
+)
+
+// CountryResolver maps a client IP to an ISO 3166-1 alpha-2 country code,
+// returning an empty string when the country is unknown.
+type CountryResolver interface {
+	Country(ip string) string
+}
+
+// Reader resolves countries from a local MaxMind-format database such as
+// GeoLite2-Country.mmdb or GeoIP2-City.mmdb.
+type Reader struct {
+	db *geoip2.Reader
+}
+
+func Open(path string) (*Reader, error) {
+	db, err := geoip2.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	return &Reader{db: db}, nil
+}
+
+func (r *Reader) Country(ip string) string {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return ""
+	}
+
+	record, err := r.db.Country(parsed)
+	if err != nil {
+		return ""
+	}
+	return record.Country.IsoCode
+}
+
+func (r *Reader) Close() error {
+	return r.db.Close()
+}
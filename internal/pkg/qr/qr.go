@@ -0,0 +1,304 @@
+package qr
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/jpeg"
+	"image/png"
+	"os"
+	"strconv"
+	"strings"
+
+	qrcode "github.com/skip2/go-qrcode"
+)
+
+const (
+	FormatPNG = "png"
+	FormatSVG = "svg"
+
+	LevelLow      = "L"
+	LevelMedium   = "M"
+	LevelQuartile = "Q"
+	LevelHigh     = "H"
+
+	DefaultSize   = 256
+	DefaultMargin = 4
+	MinSize       = 64
+	MaxSize       = 2048
+	MaxMargin     = 16
+
+	// logoRatio is the share of the code width covered by a logo. Level H
+	// restores up to 30% of the symbol, which leaves room for finder patterns.
+	logoRatio = 0.2
+)
+
+var (
+	ErrSizeTooSmall = errors.New("size is too small for the QR code content")
+	ErrInvalidColor = errors.New("color must be a hex value such as 000000 or #fff")
+)
+
+var levels = map[string]qrcode.RecoveryLevel{
+	LevelLow:      qrcode.Low,
+	LevelMedium:   qrcode.Medium,
+	LevelQuartile: qrcode.High,
+	LevelHigh:     qrcode.Highest,
+}
+
+type Options struct {
+	Size       int
+	Level      string
+	Margin     int
+	Foreground color.Color
+	Background color.Color
+	// Logo is drawn over the centre of the code. The error correction level
+	// is raised to H whenever a logo is set so the code stays readable.
+	Logo image.Image
+}
+
+// Encode renders content as a QR code in the given format. The output only
+// depends on its inputs, so callers can derive cache validators from them.
+func Encode(content, format string, opts Options) ([]byte, error) {
+	opts = withDefaults(opts)
+
+	level, ok := levels[opts.Level]
+	if !ok {
+		return nil, fmt.Errorf("unknown error correction level: %s", opts.Level)
+	}
+	if opts.Logo != nil {
+		level = qrcode.Highest
+	}
+
+	code, err := qrcode.New(content, level)
+	if err != nil {
+		return nil, err
+	}
+	code.DisableBorder = true
+	modules := code.Bitmap()
+
+	layout, err := newLayout(len(modules), opts)
+	if err != nil {
+		return nil, err
+	}
+
+	switch format {
+	case "", FormatPNG:
+		return encodePNG(modules, layout, opts)
+	case FormatSVG:
+		return encodeSVG(modules, layout, opts)
+	default:
+		return nil, fmt.Errorf("unknown QR code format: %s", format)
+	}
+}
+
+// Fingerprint identifies the output Encode produces for the same inputs, so
+// callers can answer conditional requests without rendering. The logo only
+// counts as present or not; callers must change the key themselves when the
+// logo image changes.
+func Fingerprint(content, format string, opts Options) string {
+	opts = withDefaults(opts)
+	if format == "" {
+		format = FormatPNG
+	}
+	if opts.Logo != nil {
+		opts.Level = LevelHigh
+	}
+	return fmt.Sprintf("%s|%s|%d|%s|%d|%s%s|%s%s|%t",
+		content, format, opts.Size, opts.Level, opts.Margin,
+		hexColor(opts.Foreground), opacity(opts.Foreground),
+		hexColor(opts.Background), opacity(opts.Background), opts.Logo != nil)
+}
+
+// ParseColor parses RGB, RRGGBB or RRGGBBAA hex colors with an optional '#'.
+func ParseColor(s string) (color.Color, error) {
+	s = strings.TrimPrefix(s, "#")
+	if len(s) == 3 {
+		s = string([]byte{s[0], s[0], s[1], s[1], s[2], s[2]})
+	}
+	if len(s) == 6 {
+		s += "ff"
+	}
+	if len(s) != 8 {
+		return nil, ErrInvalidColor
+	}
+
+	v, err := strconv.ParseUint(s, 16, 32)
+	if err != nil {
+		return nil, ErrInvalidColor
+	}
+	return color.NRGBA{R: uint8(v >> 24), G: uint8(v >> 16), B: uint8(v >> 8), A: uint8(v)}, nil
+}
+
+// LoadLogo reads a PNG or JPEG image to be used as Options.Logo.
+func LoadLogo(path string) (image.Image, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	logo, _, err := image.Decode(file)
+	return logo, err
+}
+
+func withDefaults(opts Options) Options {
+	if opts.Size == 0 {
+		opts.Size = DefaultSize
+	}
+	if opts.Level == "" {
+		opts.Level = LevelMedium
+	}
+	if opts.Foreground == nil {
+		opts.Foreground = color.Black
+	}
+	if opts.Background == nil {
+		opts.Background = color.White
+	}
+	return opts
+}
+
+// layout places the module grid inside a square image of opts.Size pixels.
+// Modules are scaled by a whole number of pixels and the grid is centred, so
+// edges stay sharp at any requested size.
+type layout struct {
+	size   int
+	scale  int
+	offset int
+}
+
+func newLayout(modules int, opts Options) (layout, error) {
+	total := modules + 2*opts.Margin
+	scale := opts.Size / total
+	if scale < 1 {
+		return layout{}, ErrSizeTooSmall
+	}
+	return layout{
+		size:   opts.Size,
+		scale:  scale,
+		offset: (opts.Size-scale*total)/2 + opts.Margin*scale,
+	}, nil
+}
+
+// logoRect returns the square covered by the logo, aligned to whole modules.
+func (l layout) logoRect(modules int) image.Rectangle {
+	logoModules := int(float64(modules) * logoRatio)
+	if logoModules%2 != modules%2 {
+		logoModules++
+	}
+	start := (modules - logoModules) / 2
+	min := image.Pt(l.offset+start*l.scale, l.offset+start*l.scale)
+	return image.Rectangle{Min: min, Max: min.Add(image.Pt(logoModules*l.scale, logoModules*l.scale))}
+}
+
+func encodePNG(modules [][]bool, l layout, opts Options) ([]byte, error) {
+	img := image.NewRGBA(image.Rect(0, 0, l.size, l.size))
+	draw.Draw(img, img.Bounds(), image.NewUniform(opts.Background), image.Point{}, draw.Src)
+
+	fg := image.NewUniform(opts.Foreground)
+	for y, row := range modules {
+		for x, dark := range row {
+			if !dark {
+				continue
+			}
+			px, py := l.offset+x*l.scale, l.offset+y*l.scale
+			draw.Draw(img, image.Rect(px, py, px+l.scale, py+l.scale), fg, image.Point{}, draw.Src)
+		}
+	}
+
+	if opts.Logo != nil {
+		rect := l.logoRect(len(modules))
+		draw.Draw(img, rect, image.NewUniform(opts.Background), image.Point{}, draw.Src)
+		pad := l.scale / 2
+		drawScaled(img, rect.Inset(pad), opts.Logo)
+	}
+
+	var buf bytes.Buffer
+	encoder := png.Encoder{CompressionLevel: png.BestCompression}
+	if err := encoder.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func encodeSVG(modules [][]bool, l layout, opts Options) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(`<?xml version="1.0" encoding="UTF-8"?>` + "\n")
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">`, l.size, l.size, l.size, l.size)
+	fmt.Fprintf(&buf, `<rect width="100%%" height="100%%" fill="%s"%s/>`, hexColor(opts.Background), opacity(opts.Background))
+
+	// Consecutive dark modules of a row are merged into one path segment.
+	fmt.Fprintf(&buf, `<path fill="%s"%s d="`, hexColor(opts.Foreground), opacity(opts.Foreground))
+	for y, row := range modules {
+		for x := 0; x < len(row); x++ {
+			if !row[x] {
+				continue
+			}
+			run := 1
+			for x+run < len(row) && row[x+run] {
+				run++
+			}
+			fmt.Fprintf(&buf, "M%d %dh%dv%dh-%dz", l.offset+x*l.scale, l.offset+y*l.scale, run*l.scale, l.scale, run*l.scale)
+			x += run - 1
+		}
+	}
+	buf.WriteString(`"/>`)
+
+	if opts.Logo != nil {
+		rect := l.logoRect(len(modules))
+		fmt.Fprintf(&buf, `<rect x="%d" y="%d" width="%d" height="%d" fill="%s"%s/>`,
+			rect.Min.X, rect.Min.Y, rect.Dx(), rect.Dy(), hexColor(opts.Background), opacity(opts.Background))
+
+		var logo bytes.Buffer
+		if err := png.Encode(&logo, opts.Logo); err != nil {
+			return nil, err
+		}
+		inner := rect.Inset(l.scale / 2)
+		fmt.Fprintf(&buf, `<image x="%d" y="%d" width="%d" height="%d" href="data:image/png;base64,%s"/>`,
+			inner.Min.X, inner.Min.Y, inner.Dx(), inner.Dy(), base64.StdEncoding.EncodeToString(logo.Bytes()))
+	}
+
+	buf.WriteString("</svg>\n")
+	return buf.Bytes(), nil
+}
+
+// drawScaled draws src into dst's rect with nearest-neighbour sampling,
+// keeping the aspect ratio of src.
+func drawScaled(dst draw.Image, rect image.Rectangle, src image.Image) {
+	sb := src.Bounds()
+	if sb.Empty() || rect.Empty() {
+		return
+	}
+
+	w, h := rect.Dx(), rect.Dy()
+	if sb.Dx()*h > sb.Dy()*w {
+		h = sb.Dy() * w / sb.Dx()
+	} else {
+		w = sb.Dx() * h / sb.Dy()
+	}
+	target := image.Rect(0, 0, w, h).Add(rect.Min).Add(image.Pt((rect.Dx()-w)/2, (rect.Dy()-h)/2))
+
+	scaled := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			scaled.Set(x, y, src.At(sb.Min.X+x*sb.Dx()/w, sb.Min.Y+y*sb.Dy()/h))
+		}
+	}
+	draw.Draw(dst, target, scaled, image.Point{}, draw.Over)
+}
+
+func hexColor(c color.Color) string {
+	n := color.NRGBAModel.Convert(c).(color.NRGBA)
+	return fmt.Sprintf("#%02x%02x%02x", n.R, n.G, n.B)
+}
+
+func opacity(c color.Color) string {
+	n := color.NRGBAModel.Convert(c).(color.NRGBA)
+	if n.A == 0xff {
+		return ""
+	}
+	return fmt.Sprintf(` fill-opacity="%.3f"`, float64(n.A)/0xff)
+}
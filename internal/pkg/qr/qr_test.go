@@ -0,0 +1,114 @@
+package qr
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncode_PNG(t *testing.T) {
+	fg, _ := ParseColor("#112233")
+	data, err := Encode("https://sho.rt/abc", FormatPNG, Options{Size: 300, Margin: 2, Foreground: fg})
+	assert.NoError(t, err)
+
+	img, err := png.Decode(bytes.NewReader(data))
+	assert.NoError(t, err)
+	assert.Equal(t, image.Rect(0, 0, 300, 300), img.Bounds())
+
+	// The corner is quiet zone and the top-left finder pattern of the 25x25
+	// symbol starts right after the margin.
+	assert.Equal(t, color.RGBA{255, 255, 255, 255}, color.RGBAModel.Convert(img.At(0, 0)))
+	l, err := newLayout(25, withDefaults(Options{Size: 300, Margin: 2}))
+	assert.NoError(t, err)
+	assert.Equal(t, color.RGBA{0x11, 0x22, 0x33, 0xff}, color.RGBAModel.Convert(img.At(l.offset, l.offset)))
+
+	again, err := Encode("https://sho.rt/abc", FormatPNG, Options{Size: 300, Margin: 2, Foreground: fg})
+	assert.NoError(t, err)
+	assert.Equal(t, data, again)
+}
+
+func TestEncode_SVG(t *testing.T) {
+	bg, _ := ParseColor("ffffff00")
+	data, err := Encode("https://sho.rt/abc", FormatSVG, Options{Size: 128, Background: bg})
+	assert.NoError(t, err)
+
+	svg := string(data)
+	assert.Contains(t, svg, `width="128" height="128"`)
+	assert.Contains(t, svg, `fill="#ffffff" fill-opacity="0.000"`)
+	assert.Contains(t, svg, `<path fill="#000000" d="M`)
+	assert.True(t, strings.HasSuffix(svg, "</svg>\n"))
+}
+
+func TestEncode_Logo(t *testing.T) {
+	logo := image.NewRGBA(image.Rect(0, 0, 10, 10))
+	for i := range logo.Pix {
+		logo.Pix[i] = 0xff
+	}
+
+	data, err := Encode("https://sho.rt/abc", FormatPNG, Options{Size: 256, Logo: logo})
+	assert.NoError(t, err)
+	img, err := png.Decode(bytes.NewReader(data))
+	assert.NoError(t, err)
+	assert.Equal(t, color.RGBA{255, 255, 255, 255}, color.RGBAModel.Convert(img.At(128, 128)))
+
+	svg, err := Encode("https://sho.rt/abc", FormatSVG, Options{Logo: logo})
+	assert.NoError(t, err)
+	assert.Contains(t, string(svg), `href="data:image/png;base64,`)
+}
+
+func TestEncode_Errors(t *testing.T) {
+	_, err := Encode("https://sho.rt/abc", FormatPNG, Options{Size: 20})
+	assert.Equal(t, ErrSizeTooSmall, err)
+
+	_, err = Encode("https://sho.rt/abc", FormatPNG, Options{Level: "X"})
+	assert.Error(t, err)
+
+	_, err = Encode("https://sho.rt/abc", "gif", Options{})
+	assert.Error(t, err)
+}
+
+func TestFingerprint(t *testing.T) {
+	content := "https://sho.rt/abc"
+	black, _ := ParseColor("#000")
+
+	base := Fingerprint(content, "", Options{})
+	assert.Equal(t, base, Fingerprint(content, FormatPNG, Options{Size: DefaultSize, Level: LevelMedium, Foreground: black}))
+
+	assert.NotEqual(t, base, Fingerprint(content, FormatSVG, Options{}))
+	assert.NotEqual(t, base, Fingerprint(content, "", Options{Size: 512}))
+	assert.NotEqual(t, base, Fingerprint(content, "", Options{Margin: 2}))
+	assert.NotEqual(t, base, Fingerprint(content, "", Options{Background: color.NRGBA{255, 0, 0, 255}}))
+	assert.NotEqual(t, base, Fingerprint("https://sho.rt/abd", "", Options{}))
+
+	// A logo forces level H, whatever level was asked for.
+	logo := image.NewRGBA(image.Rect(0, 0, 10, 10))
+	assert.Equal(t, Fingerprint(content, "", Options{Logo: logo}), Fingerprint(content, "", Options{Logo: logo, Level: LevelLow}))
+	assert.NotEqual(t, base, Fingerprint(content, "", Options{Logo: logo}))
+}
+
+func TestParseColor(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected color.Color
+		err      error
+	}{
+		{input: "#000", expected: color.NRGBA{0, 0, 0, 255}},
+		{input: "ff8000", expected: color.NRGBA{255, 128, 0, 255}},
+		{input: "#ff800080", expected: color.NRGBA{255, 128, 0, 128}},
+		{input: "red", err: ErrInvalidColor},
+		{input: "#gggggg", err: ErrInvalidColor},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			c, err := ParseColor(tt.input)
+			assert.Equal(t, tt.err, err)
+			assert.Equal(t, tt.expected, c)
+		})
+	}
+}
@@ -2,6 +2,7 @@ package query
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"gorm.io/gorm"
@@ -21,11 +22,7 @@ func (qp *QueryParams) ApplyQuery(db *gorm.DB) *gorm.DB {
 	}
 
 	// Apply filters if provided
-	if qp.Filters != nil {
-		for key, value := range *qp.Filters {
-			db = db.Where(fmt.Sprintf("%s = ?", key), value)
-		}
-	}
+	db = qp.applyFilters(db)
 
 	// Apply sorting
 	if qp.OrderBy != "" {
@@ -64,11 +61,36 @@ func (qp *QueryParams) ApplyCountQuery(db *gorm.DB) *gorm.DB {
 	}
 
 	// Apply filters if provided
-	if qp.Filters != nil {
-		for key, value := range *qp.Filters {
-			db = db.Where(fmt.Sprintf("%s = ?", key), value)
-		}
+	db = qp.applyFilters(db)
+
+	return db
+}
+
+func (qp *QueryParams) applyFilters(db *gorm.DB) *gorm.DB {
+	if qp.Filters == nil {
+		return db
 	}
 
+	for _, key := range qp.filterKeys() {
+		if scope, ok := qp.FilterScopes[key]; ok {
+			values := qp.FilterValues[key]
+			if len(values) == 0 {
+				values = []string{(*qp.Filters)[key]}
+			}
+			db = scope(db, values, qp.Match == MatchAll)
+			continue
+		}
+		db = db.Where(fmt.Sprintf("%s = ?", key), (*qp.Filters)[key])
+	}
 	return db
 }
+
+// filterKeys returns the filter keys sorted so the generated SQL is stable.
+func (qp *QueryParams) filterKeys() []string {
+	keys := make([]string, 0, len(*qp.Filters))
+	for key := range *qp.Filters {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -69,6 +69,34 @@ func TestApplyQuery_Filters(t *testing.T) {
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestApplyQuery_FilterScopes(t *testing.T) {
+	db, mock := mockDB(t)
+
+	var gotValues []string
+	var gotMatchAll bool
+	qp := QueryParams{
+		Filters:      &map[string]string{"category": "electronics", "tag": "a"},
+		FilterValues: map[string][]string{"category": {"electronics"}, "tag": {"a", "b"}},
+		FilterScopes: map[string]FilterScope{
+			"tag": func(db *gorm.DB, values []string, matchAll bool) *gorm.DB {
+				gotValues, gotMatchAll = values, matchAll
+				return db.Where("tags @> ?", "{a,b}")
+			},
+		},
+		Match: MatchAll,
+	}
+
+	expectedSQL := `SELECT \* FROM "products" WHERE category = \$1 AND tags @> \$2 AND "products"."deleted_at" IS NULL`
+	mock.ExpectQuery(expectedSQL).
+		WithArgs("electronics", "{a,b}").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}))
+
+	db.Model(&Product{}).Scopes(qp.ApplyQuery).Find(&Product{})
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.Equal(t, []string{"a", "b"}, gotValues)
+	assert.True(t, gotMatchAll)
+}
+
 func TestApplyQuery_Ordering(t *testing.T) {
 	t.Run("ASC order", func(t *testing.T) {
 		db, mock := mockDB(t)
@@ -1,20 +1,39 @@
 package query
 
 import (
+	"fmt"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
 	CustomValidator "github.com/xcurvnubaim/njajal-gin-golang/internal/pkg/validator"
+	"gorm.io/gorm"
 )
 
+// Filter matching modes for repeated filter values, set with match=.
+const (
+	MatchAny = "any"
+	MatchAll = "all"
+)
+
+// FilterScope applies a filter that is not a plain column comparison, such as
+// one backed by a join table. values holds every value given for the key and
+// matchAll asks for rows matching all of them instead of any.
+type FilterScope func(db *gorm.DB, values []string, matchAll bool) *gorm.DB
+
 type QueryParams struct {
 	Search        *string
 	SearchColumns []string
 	Filters       *map[string]string
-	OrderBy       string
-	OrderDir      string
-	Page          int
-	PageSize      int
+	// FilterValues keeps every value of a repeated filter such as
+	// ?tag=a&tag=b; Filters only holds the first one.
+	FilterValues map[string][]string
+	// FilterScopes replaces the column comparison for the given keys.
+	FilterScopes map[string]FilterScope
+	Match        string
+	OrderBy      string
+	OrderDir     string
+	Page         int
+	PageSize     int
 }
 
 func NewQueryParams(searchColumns []string) *QueryParams {
@@ -28,16 +47,21 @@ func (qp *QueryParams) Parse(c *gin.Context, defaultPageSize string) {
 	}
 
 	filters := make(map[string]string)
+	filterValues := make(map[string][]string)
 	for key, value := range c.Request.URL.Query() {
-		if key != "search" && key != "order_by" && key != "order_dir" && key != "page" && key != "page_size" {
+		if key != "search" && key != "match" && key != "order_by" && key != "order_dir" && key != "page" && key != "page_size" {
 			filters[key] = value[0]
+			filterValues[key] = value
 		}
 	}
 
 	if len(filters) > 0 {
 		qp.Filters = &filters
+		qp.FilterValues = filterValues
 	}
 
+	qp.Match = c.DefaultQuery("match", MatchAny)
+
 	qp.OrderBy = c.DefaultQuery("order_by", "created_at")
 	qp.OrderDir = c.DefaultQuery("order_dir", "asc")
 
@@ -63,6 +87,14 @@ func (qp *QueryParams) Validate(validator CustomValidator.ParamValidator) error
 		return err
 	}
 
+	if err := CustomValidator.ValidateFilterValues(qp.FilterValues, validator.MaxFilterValues, validator.MaxFilterValueLength); err != nil {
+		return err
+	}
+
+	if qp.Match != "" && qp.Match != MatchAny && qp.Match != MatchAll {
+		return fmt.Errorf("invalid match: must be '%s' or '%s'", MatchAny, MatchAll)
+	}
+
 	if err := CustomValidator.ValidateOrderBy(qp.OrderBy, validator.AllowedOrderByColumns); err != nil {
 		return err
 	}
@@ -32,6 +32,32 @@ func TestQueryParams_Parse(t *testing.T) {
 	assert.Equal(t, 20, qp.PageSize)
 }
 
+func TestQueryParams_ParseRepeatedFilters(t *testing.T) {
+	c := createTestContext("tag=a&tag=b&status=active&match=all")
+
+	qp := &QueryParams{}
+	qp.Parse(c, "10")
+
+	assert.Equal(t, map[string]string{"tag": "a", "status": "active"}, *qp.Filters)
+	assert.Equal(t, []string{"a", "b"}, qp.FilterValues["tag"])
+	assert.Equal(t, MatchAll, qp.Match)
+
+	validator := CustomValidator.ParamValidator{
+		AllowedOrderByColumns: []string{"created_at"},
+		AllowedFilterKeys:     []string{"tag", "status"},
+		MaxFilterValueLength:  10,
+		MaxFilterValues:       1,
+		MaxPageSize:           100,
+	}
+	assert.ErrorContains(t, qp.Validate(validator), "filter 'tag' accepts at most 1 values")
+
+	validator.MaxFilterValues = 2
+	assert.NoError(t, qp.Validate(validator))
+
+	qp.Match = "some"
+	assert.ErrorContains(t, qp.Validate(validator), "invalid match")
+}
+
 func TestQueryParams_ParseAndValidate(t *testing.T) {
 	validator := CustomValidator.NewParamValidator(
 		[]string{"name", "created_at"},
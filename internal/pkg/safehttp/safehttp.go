@@ -0,0 +1,73 @@
+package safehttp
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+// ErrNonPublicAddress is returned when a connection would reach a loopback,
+// private, link-local or otherwise non-public address.
+var ErrNonPublicAddress = errors.New("destination address is not public")
+
+var nonPublicNetworks = []*net.IPNet{
+	mustParseCIDR("0.0.0.0/8"),
+	// Carrier-grade NAT, often used for internal networks as well.
+	mustParseCIDR("100.64.0.0/10"),
+}
+
+// IsPublicIP reports whether ip is a unicast address reachable on the public
+// internet.
+func IsPublicIP(ip net.IP) bool {
+	if ip == nil || ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return false
+	}
+	for _, network := range nonPublicNetworks {
+		if network.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// Control is a net.Dialer hook that refuses to connect to non-public
+// addresses. It runs after DNS resolution, so a public hostname resolving to
+// an internal address is refused as well.
+func Control(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if !IsPublicIP(net.ParseIP(host)) {
+		return ErrNonPublicAddress
+	}
+	return nil
+}
+
+// NewTransport returns a transport that only connects to public addresses.
+// Environment proxies are ignored, since the proxy would make the request
+// on our behalf without the address check.
+func NewTransport() *http.Transport {
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control:   Control,
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return transport
+}
+
+func mustParseCIDR(s string) *net.IPNet {
+	_, network, err := net.ParseCIDR(s)
+	if err != nil {
+		panic(err)
+	}
+	return network
+}
@@ -0,0 +1,52 @@
+package safehttp
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsPublicIP(t *testing.T) {
+	tests := []struct {
+		ip     string
+		public bool
+	}{
+		{"93.184.216.34", true},
+		{"2606:2800:220:1:248:1893:25c8:1946", true},
+		{"127.0.0.1", false},
+		{"10.1.2.3", false},
+		{"172.16.0.1", false},
+		{"192.168.1.1", false},
+		{"169.254.169.254", false},
+		{"100.64.0.1", false},
+		{"0.0.0.0", false},
+		{"0.1.2.3", false},
+		{"::1", false},
+		{"::", false},
+		{"fe80::1", false},
+		{"fd00::1", false},
+		{"::ffff:127.0.0.1", false},
+		{"224.0.0.1", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ip, func(t *testing.T) {
+			assert.Equal(t, tt.public, IsPublicIP(net.ParseIP(tt.ip)))
+		})
+	}
+}
+
+func TestNewTransport_RefusesLoopback(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("secret"))
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: NewTransport()}
+	_, err := client.Get(server.URL)
+
+	assert.True(t, errors.Is(err, ErrNonPublicAddress))
+}
@@ -0,0 +1,39 @@
+package shortcode
+
+import (
+	"crypto/rand"
+)
+
+type randomGenerator struct {
+	length int
+}
+
+// NewRandomGenerator returns crypto-random base62 codes of a fixed length.
+func NewRandomGenerator(length int) *randomGenerator {
+	return &randomGenerator{length: length}
+}
+
+func (g *randomGenerator) Generate() (string, error) {
+	// 248 is the largest multiple of 62 below 256; rejecting bytes above it
+	// keeps every character equally likely.
+	const limit = 256 - 256%len(base62Alphabet)
+
+	code := make([]byte, 0, g.length)
+	buf := make([]byte, g.length*2)
+	for len(code) < g.length {
+		if _, err := rand.Read(buf); err != nil {
+			return "", err
+		}
+		for _, b := range buf {
+			if int(b) >= limit {
+				continue
+			}
+			code = append(code, base62Alphabet[int(b)%len(base62Alphabet)])
+			if len(code) == g.length {
+				break
+			}
+		}
+	}
+
+	return string(code), nil
+}
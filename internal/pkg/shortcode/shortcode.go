@@ -0,0 +1,48 @@
+package shortcode
+
+import (
+	"fmt"
+)
+
+const (
+	StrategyRandom    = "random"
+	StrategySnowflake = "snowflake"
+	StrategyWords     = "words"
+
+	DefaultLength = 7
+
+	base62Alphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+)
+
+// CodeGenerator produces candidate short codes. Uniqueness is enforced by the
+// database, so callers retry on conflict rather than checking beforehand.
+type CodeGenerator interface {
+	Generate() (string, error)
+}
+
+type Options struct {
+	Strategy string
+	Length   int
+	Salt     string
+	NodeID   int64
+}
+
+// New builds the generator for the configured strategy, defaulting to random
+// base62 codes.
+func New(opts Options) (CodeGenerator, error) {
+	length := opts.Length
+	if length <= 0 {
+		length = DefaultLength
+	}
+
+	switch opts.Strategy {
+	case "", StrategyRandom:
+		return NewRandomGenerator(length), nil
+	case StrategySnowflake:
+		return NewSnowflakeGenerator(opts.NodeID, opts.Salt)
+	case StrategyWords:
+		return NewWordGenerator(), nil
+	default:
+		return nil, fmt.Errorf("unknown short code strategy: %s", opts.Strategy)
+	}
+}
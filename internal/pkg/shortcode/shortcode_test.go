@@ -0,0 +1,82 @@
+package shortcode
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRandomGenerator(t *testing.T) {
+	g := NewRandomGenerator(8)
+	pattern := regexp.MustCompile(`^[0-9A-Za-z]{8}$`)
+
+	seen := make(map[string]bool)
+	for i := 0; i < 1000; i++ {
+		code, err := g.Generate()
+		assert.NoError(t, err)
+		assert.Regexp(t, pattern, code)
+		seen[code] = true
+	}
+	assert.Len(t, seen, 1000)
+}
+
+func TestSnowflakeGenerator_Unique(t *testing.T) {
+	g, err := NewSnowflakeGenerator(1, "salt")
+	assert.NoError(t, err)
+
+	seen := make(map[string]bool)
+	for i := 0; i < 10000; i++ {
+		code, err := g.Generate()
+		assert.NoError(t, err)
+		assert.False(t, seen[code], "duplicate code %s", code)
+		seen[code] = true
+	}
+}
+
+func TestSnowflakeGenerator_SaltChangesEncoding(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	first, _ := NewSnowflakeGenerator(1, "first")
+	second, _ := NewSnowflakeGenerator(1, "second")
+	first.now = func() time.Time { return now }
+	second.now = func() time.Time { return now }
+
+	a, _ := first.Generate()
+	b, _ := second.Generate()
+	assert.NotEqual(t, a, b)
+}
+
+func TestSnowflakeGenerator_InvalidNode(t *testing.T) {
+	_, err := NewSnowflakeGenerator(snowflakeMaxNode+1, "")
+	assert.Error(t, err)
+}
+
+func TestShuffleAlphabet(t *testing.T) {
+	shuffled := shuffleAlphabet(base62Alphabet, "salt")
+	assert.Equal(t, shuffled, shuffleAlphabet(base62Alphabet, "salt"))
+	assert.NotEqual(t, base62Alphabet, shuffled)
+	assert.ElementsMatch(t, []byte(base62Alphabet), []byte(shuffled))
+}
+
+func TestWordGenerator(t *testing.T) {
+	code, err := NewWordGenerator().Generate()
+	assert.NoError(t, err)
+	assert.Regexp(t, regexp.MustCompile(`^[a-z]+-[a-z]+-\d{4}$`), code)
+}
+
+func TestWordGenerator_CodeSpace(t *testing.T) {
+	assert.GreaterOrEqual(t, len(adjectives)*len(nouns)*wordNumbers, 40_000_000)
+}
+
+func TestNew(t *testing.T) {
+	for _, strategy := range []string{"", StrategyRandom, StrategySnowflake, StrategyWords} {
+		g, err := New(Options{Strategy: strategy})
+		assert.NoError(t, err)
+		_, err = g.Generate()
+		assert.NoError(t, err)
+	}
+
+	_, err := New(Options{Strategy: "unknown"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,107 @@
+package shortcode
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	snowflakeNodeBits     = 10
+	snowflakeSequenceBits = 12
+	snowflakeMaxNode      = -1 ^ (-1 << snowflakeNodeBits)
+	snowflakeMaxSequence  = -1 ^ (-1 << snowflakeSequenceBits)
+)
+
+// snowflakeEpoch keeps the timestamp part small so codes stay short.
+var snowflakeEpoch = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// snowflakeGenerator issues time-ordered 63-bit IDs (41 bits of milliseconds,
+// 10 bits of node ID, 12 bits of sequence) and encodes them with a base62
+// alphabet shuffled by a secret salt, so codes are unique across nodes
+// without revealing the sequence.
+type snowflakeGenerator struct {
+	mu       sync.Mutex
+	nodeID   int64
+	lastMs   int64
+	sequence int64
+	alphabet string
+	now      func() time.Time
+}
+
+func NewSnowflakeGenerator(nodeID int64, salt string) (*snowflakeGenerator, error) {
+	if nodeID < 0 || nodeID > snowflakeMaxNode {
+		return nil, fmt.Errorf("snowflake node ID must be between 0 and %d", snowflakeMaxNode)
+	}
+
+	return &snowflakeGenerator{
+		nodeID:   nodeID,
+		alphabet: shuffleAlphabet(base62Alphabet, salt),
+		now:      time.Now,
+	}, nil
+}
+
+func (g *snowflakeGenerator) Generate() (string, error) {
+	return encodeBase62(g.nextID(), g.alphabet), nil
+}
+
+func (g *snowflakeGenerator) nextID() uint64 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	ms := g.now().Sub(snowflakeEpoch).Milliseconds()
+	if ms < g.lastMs {
+		// Clock moved backwards; keep issuing from the last timestamp.
+		ms = g.lastMs
+	}
+
+	if ms == g.lastMs {
+		g.sequence = (g.sequence + 1) & snowflakeMaxSequence
+		if g.sequence == 0 {
+			for ms <= g.lastMs {
+				time.Sleep(time.Millisecond)
+				ms = g.now().Sub(snowflakeEpoch).Milliseconds()
+			}
+		}
+	} else {
+		g.sequence = 0
+	}
+	g.lastMs = ms
+
+	return uint64(ms)<<(snowflakeNodeBits+snowflakeSequenceBits) |
+		uint64(g.nodeID)<<snowflakeSequenceBits |
+		uint64(g.sequence)
+}
+
+func encodeBase62(n uint64, alphabet string) string {
+	if n == 0 {
+		return string(alphabet[0])
+	}
+
+	base := uint64(len(alphabet))
+	var buf [11]byte
+	i := len(buf)
+	for n > 0 {
+		i--
+		buf[i] = alphabet[n%base]
+		n /= base
+	}
+	return string(buf[i:])
+}
+
+// shuffleAlphabet is the hashids "consistent shuffle": the same salt always
+// yields the same permutation.
+func shuffleAlphabet(alphabet, salt string) string {
+	result := []byte(alphabet)
+	if salt == "" {
+		return alphabet
+	}
+
+	for i, v, p := len(result)-1, 0, 0; i > 0; i-- {
+		p += int(salt[v])
+		j := (int(salt[v]) + v + p) % i
+		result[i], result[j] = result[j], result[i]
+		v = (v + 1) % len(salt)
+	}
+	return string(result)
+}
@@ -0,0 +1,66 @@
+package shortcode
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+)
+
+var adjectives = []string{
+	"amber", "bold", "brave", "bright", "calm", "clever", "cool", "cosmic",
+	"crisp", "curious", "daring", "eager", "early", "fancy", "fast", "fresh",
+	"gentle", "giant", "glad", "golden", "grand", "green", "happy", "humble",
+	"jolly", "keen", "kind", "lively", "lucky", "mellow", "merry", "mighty",
+	"modest", "neat", "noble", "polite", "proud", "quick", "quiet", "rapid",
+	"ready", "royal", "rustic", "shiny", "silent", "silver", "simple", "sleek",
+	"smart", "snowy", "solid", "sunny", "swift", "tidy", "tiny", "vivid",
+	"warm", "wild", "wise", "witty", "young", "zesty", "azure", "breezy",
+}
+
+var nouns = []string{
+	"badger", "beacon", "bear", "breeze", "brook", "canyon", "cedar", "cloud",
+	"comet", "coral", "crane", "delta", "dolphin", "eagle", "ember", "falcon",
+	"fern", "finch", "forest", "fox", "galaxy", "garden", "glacier", "harbor",
+	"hawk", "heron", "island", "jaguar", "koala", "lagoon", "lantern", "lemur",
+	"lion", "lotus", "maple", "meadow", "meteor", "moon", "otter", "owl",
+	"panda", "pebble", "pine", "planet", "prairie", "raven", "reef", "river",
+	"robin", "rocket", "sparrow", "spruce", "star", "summit", "tiger", "tulip",
+	"valley", "voyager", "walrus", "willow", "wolf", "yak", "zebra", "zephyr",
+}
+
+// wordNumbers is the range of the number suffix. With 64 adjectives and 64
+// nouns it gives about 41 million codes, so the retries on conflict keep
+// succeeding long after the first few hundred thousand links.
+const wordNumbers = 10000
+
+type wordGenerator struct{}
+
+// NewWordGenerator returns readable codes such as "brave-otter-4207".
+func NewWordGenerator() *wordGenerator {
+	return &wordGenerator{}
+}
+
+func (g *wordGenerator) Generate() (string, error) {
+	adjective, err := randomInt(len(adjectives))
+	if err != nil {
+		return "", err
+	}
+	noun, err := randomInt(len(nouns))
+	if err != nil {
+		return "", err
+	}
+	number, err := randomInt(wordNumbers)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%s-%s-%04d", adjectives[adjective], nouns[noun], number), nil
+}
+
+func randomInt(max int) (int, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(int64(max)))
+	if err != nil {
+		return 0, err
+	}
+	return int(n.Int64()), nil
+}
@@ -0,0 +1,120 @@
+package useragent
+
+import "strings"
+
+const (
+	DeviceDesktop = "desktop"
+	DeviceMobile  = "mobile"
+	DeviceTablet  = "tablet"
+	DeviceBot     = "bot"
+
+	Unknown = "unknown"
+)
+
+type UserAgent struct {
+	Device  string
+	Browser string
+	OS      string
+}
+
+// rule maps a case-insensitive substring of the User-Agent header to a name.
+// Rules are checked in order, so more specific tokens must come first
+// (e.g. Edge and Opera also advertise Chrome, and Chrome advertises Safari).
+type rule struct {
+	token string
+	name  string
+}
+
+var botTokens = []string{"bot", "crawler", "spider", "slurp", "facebookexternalhit", "curl", "wget", "python-requests", "go-http-client"}
+
+// previewerTokens identify clients that fetch a link to render a preview card
+// in a chat or social app rather than to visit it.
+var previewerTokens = []string{
+	"facebookexternalhit", "facebookcatalog", "twitterbot", "slackbot", "slack-imgproxy",
+	"discordbot", "telegrambot", "whatsapp", "linkedinbot", "skypeuripreview",
+	"pinterest", "redditbot", "embedly", "mastodon", "vkshare", "iframely", "applebot",
+}
+
+var browserRules = []rule{
+	{"edg/", "Edge"},
+	{"opr/", "Opera"},
+	{"opera", "Opera"},
+	{"samsungbrowser", "Samsung Internet"},
+	{"firefox", "Firefox"},
+	{"fxios", "Firefox"},
+	{"crios", "Chrome"},
+	{"chrome", "Chrome"},
+	{"safari", "Safari"},
+	{"msie", "Internet Explorer"},
+	{"trident", "Internet Explorer"},
+}
+
+var osRules = []rule{
+	{"windows", "Windows"},
+	{"iphone", "iOS"},
+	{"ipad", "iOS"},
+	{"ipod", "iOS"},
+	{"android", "Android"},
+	{"cros", "Chrome OS"},
+	{"mac os x", "macOS"},
+	{"macintosh", "macOS"},
+	{"linux", "Linux"},
+}
+
+// Parse extracts the device type, browser and operating system from a
+// User-Agent header using simple substring matching.
+func Parse(ua string) UserAgent {
+	lower := strings.ToLower(ua)
+	return UserAgent{
+		Device:  parseDevice(lower),
+		Browser: match(lower, browserRules),
+		OS:      match(lower, osRules),
+	}
+}
+
+func IsBot(ua string) bool {
+	lower := strings.ToLower(ua)
+	for _, token := range botTokens {
+		if strings.Contains(lower, token) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsLinkPreviewer reports whether ua belongs to a link unfurler, such as the
+// crawlers of messaging apps and social networks.
+func IsLinkPreviewer(ua string) bool {
+	lower := strings.ToLower(ua)
+	for _, token := range previewerTokens {
+		if strings.Contains(lower, token) {
+			return true
+		}
+	}
+	return false
+}
+
+func parseDevice(lower string) string {
+	switch {
+	case lower == "":
+		return Unknown
+	case IsBot(lower):
+		return DeviceBot
+	case strings.Contains(lower, "ipad") || strings.Contains(lower, "tablet") ||
+		(strings.Contains(lower, "android") && !strings.Contains(lower, "mobile")):
+		return DeviceTablet
+	case strings.Contains(lower, "mobi") || strings.Contains(lower, "iphone") || strings.Contains(lower, "ipod"):
+		return DeviceMobile
+	default:
+		return DeviceDesktop
+	}
+}
+
+func match(lower string, rules []rule) string {
+	for _, r := range rules {
+		if strings.Contains(lower, r.token) {
+			return r.name
+		}
+	}
+	return Unknown
+}
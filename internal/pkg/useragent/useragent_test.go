@@ -0,0 +1,84 @@
+package useragent
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		ua       string
+		expected UserAgent
+	}{
+		{
+			name:     "Chrome on Windows",
+			ua:       "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+			expected: UserAgent{Device: DeviceDesktop, Browser: "Chrome", OS: "Windows"},
+		},
+		{
+			name:     "Edge on Windows",
+			ua:       "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 Edg/120.0.0.0",
+			expected: UserAgent{Device: DeviceDesktop, Browser: "Edge", OS: "Windows"},
+		},
+		{
+			name:     "Safari on iPhone",
+			ua:       "Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.0 Mobile/15E148 Safari/604.1",
+			expected: UserAgent{Device: DeviceMobile, Browser: "Safari", OS: "iOS"},
+		},
+		{
+			name:     "Chrome on Android phone",
+			ua:       "Mozilla/5.0 (Linux; Android 14; Pixel 8) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Mobile Safari/537.36",
+			expected: UserAgent{Device: DeviceMobile, Browser: "Chrome", OS: "Android"},
+		},
+		{
+			name:     "Android tablet",
+			ua:       "Mozilla/5.0 (Linux; Android 13; SM-X700) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+			expected: UserAgent{Device: DeviceTablet, Browser: "Chrome", OS: "Android"},
+		},
+		{
+			name:     "Firefox on macOS",
+			ua:       "Mozilla/5.0 (Macintosh; Intel Mac OS X 14.0; rv:121.0) Gecko/20100101 Firefox/121.0",
+			expected: UserAgent{Device: DeviceDesktop, Browser: "Firefox", OS: "macOS"},
+		},
+		{
+			name:     "Crawler",
+			ua:       "Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)",
+			expected: UserAgent{Device: DeviceBot, Browser: Unknown, OS: Unknown},
+		},
+		{
+			name:     "Empty",
+			ua:       "",
+			expected: UserAgent{Device: Unknown, Browser: Unknown, OS: Unknown},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Parse(tt.ua))
+		})
+	}
+}
+
+func TestIsLinkPreviewer(t *testing.T) {
+	tests := []struct {
+		ua       string
+		expected bool
+	}{
+		{"facebookexternalhit/1.1 (+http://www.facebook.com/externalhit_uatext.php)", true},
+		{"Slackbot-LinkExpanding 1.0 (+https://api.slack.com/robots)", true},
+		{"WhatsApp/2.23.20.0 A", true},
+		{"TelegramBot (like TwitterBot)", true},
+		{"Mozilla/5.0 (compatible; Discordbot/2.0; +https://discordapp.com)", true},
+		{"Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)", false},
+		{"curl/8.4.0", false},
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ua, func(t *testing.T) {
+			assert.Equal(t, tt.expected, IsLinkPreviewer(tt.ua))
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package CustomValidator
+
+import (
+	"bufio"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+const (
+	AliasMinLength = 3
+	AliasMaxLength = 32
+)
+
+// DefaultReservedAliases collide with paths served from the root of the
+// short domain or are likely to be needed for them later.
+var DefaultReservedAliases = []string{
+	"api", "admin", "ping", "login", "logout", "register", "auth", "verify",
+	"static", "assets", "public", "health", "metrics", "stats", "preview",
+	"unlock", "qr", "docs", "help", "about", "settings", "dashboard",
+}
+
+var aliasPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// Blocklist decides whether an alias contains a forbidden word.
+type Blocklist interface {
+	Contains(alias string) bool
+}
+
+type AliasPolicy struct {
+	minLength int
+	maxLength int
+	reserved  map[string]struct{}
+	blocklist Blocklist
+}
+
+func NewAliasPolicy(minLength, maxLength int, reserved []string, blocklist Blocklist) *AliasPolicy {
+	reservedSet := make(map[string]struct{}, len(reserved))
+	for _, word := range reserved {
+		if word = strings.ToLower(strings.TrimSpace(word)); word != "" {
+			reservedSet[word] = struct{}{}
+		}
+	}
+
+	return &AliasPolicy{
+		minLength: minLength,
+		maxLength: maxLength,
+		reserved:  reservedSet,
+		blocklist: blocklist,
+	}
+}
+
+func (p *AliasPolicy) ValidFormat(alias string) bool {
+	return len(alias) >= p.minLength && len(alias) <= p.maxLength && aliasPattern.MatchString(alias)
+}
+
+func (p *AliasPolicy) IsReserved(alias string) bool {
+	_, ok := p.reserved[strings.ToLower(alias)]
+	return ok
+}
+
+func (p *AliasPolicy) IsBlocked(alias string) bool {
+	return p.blocklist != nil && p.blocklist.Contains(alias)
+}
+
+// RegisterAliasValidation adds the alias_format, alias_reserved and
+// alias_blocked binding tags so alias errors flow through FormatValidationErrors.
+func RegisterAliasValidation(v *validator.Validate, policy *AliasPolicy) error {
+	rules := map[string]func(string) bool{
+		"alias_format":   policy.ValidFormat,
+		"alias_reserved": func(alias string) bool { return !policy.IsReserved(alias) },
+		"alias_blocked":  func(alias string) bool { return !policy.IsBlocked(alias) },
+	}
+
+	for tag, rule := range rules {
+		rule := rule
+		err := v.RegisterValidation(tag, func(fl validator.FieldLevel) bool {
+			return rule(fl.Field().String())
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WordBlocklist matches words anywhere in an alias after lowercasing it,
+// dropping separators and undoing common character substitutions, so that
+// "B4d_W0rd" is caught by "badword".
+type WordBlocklist struct {
+	words []string
+}
+
+var aliasNormalizer = strings.NewReplacer(
+	"-", "", "_", "",
+	"0", "o", "1", "i", "3", "e", "4", "a", "5", "s", "7", "t", "8", "b",
+)
+
+func NewWordBlocklist(words []string) *WordBlocklist {
+	blocklist := &WordBlocklist{}
+	for _, word := range words {
+		if word = normalizeAlias(strings.TrimSpace(word)); word != "" {
+			blocklist.words = append(blocklist.words, word)
+		}
+	}
+	return blocklist
+}
+
+// LoadWordBlocklist reads one word per line, ignoring blank lines and lines
+// starting with "#".
+func LoadWordBlocklist(path string) (*WordBlocklist, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var words []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		words = append(words, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return NewWordBlocklist(words), nil
+}
+
+func (b *WordBlocklist) Contains(alias string) bool {
+	normalized := normalizeAlias(alias)
+	for _, word := range b.words {
+		if strings.Contains(normalized, word) {
+			return true
+		}
+	}
+	return false
+}
+
+func normalizeAlias(alias string) string {
+	return aliasNormalizer.Replace(strings.ToLower(alias))
+}
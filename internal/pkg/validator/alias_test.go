@@ -0,0 +1,65 @@
+package CustomValidator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAliasPolicy(t *testing.T) {
+	policy := NewAliasPolicy(AliasMinLength, AliasMaxLength, DefaultReservedAliases, NewWordBlocklist([]string{"badword"}))
+
+	tests := []struct {
+		alias    string
+		format   bool
+		reserved bool
+		blocked  bool
+	}{
+		{alias: "my-link_1", format: true},
+		{alias: "ab", format: false},
+		{alias: "has/slash", format: false},
+		{alias: "has space", format: false},
+		{alias: "ünïcode", format: false},
+		{alias: "API", format: true, reserved: true},
+		{alias: "Login", format: true, reserved: true},
+		{alias: "my-BadWord", format: true, blocked: true},
+		{alias: "b4d_w0rd", format: true, blocked: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.alias, func(t *testing.T) {
+			assert.Equal(t, tt.format, policy.ValidFormat(tt.alias))
+			assert.Equal(t, tt.reserved, policy.IsReserved(tt.alias))
+			assert.Equal(t, tt.blocked, policy.IsBlocked(tt.alias))
+		})
+	}
+}
+
+func TestLoadWordBlocklist(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "blocklist.txt")
+	assert.NoError(t, os.WriteFile(path, []byte("# comment\n\nfoo\n  Bar  \n"), 0o644))
+
+	blocklist, err := LoadWordBlocklist(path)
+	assert.NoError(t, err)
+	assert.True(t, blocklist.Contains("xfoox"))
+	assert.True(t, blocklist.Contains("BAR"))
+	assert.False(t, blocklist.Contains("comment"))
+}
+
+func TestRegisterAliasValidation(t *testing.T) {
+	v := validator.New()
+	policy := NewAliasPolicy(AliasMinLength, AliasMaxLength, []string{"admin"}, nil)
+	assert.NoError(t, RegisterAliasValidation(v, policy))
+
+	type request struct {
+		ShortenerURL string `validate:"omitempty,alias_format,alias_reserved,alias_blocked"`
+	}
+
+	assert.NoError(t, v.Struct(request{ShortenerURL: ""}))
+	assert.NoError(t, v.Struct(request{ShortenerURL: "promo-2026"}))
+	assert.Equal(t, "ShortenerURL: This alias is reserved", FormatValidationErrors(v.Struct(request{ShortenerURL: "Admin"})))
+	assert.Contains(t, FormatValidationErrors(v.Struct(request{ShortenerURL: "a/b"})), "ShortenerURL: Must be")
+}
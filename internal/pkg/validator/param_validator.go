@@ -12,7 +12,10 @@ type ParamValidator struct {
 	AllowedFilterKeys     []string
 	MaxSearchLength       int
 	MaxFilterValueLength  int
-	MaxPageSize           int
+	// MaxFilterValues limits how often a filter key may be repeated; zero
+	// means no limit.
+	MaxFilterValues int
+	MaxPageSize     int
 }
 
 func NewParamValidator(
@@ -64,6 +67,21 @@ func ValidateFilters(filters *map[string]string, allowedKeys []string, maxLength
 	return nil
 }
 
+// ValidateFilterValues ensures repeated filters stay within limits
+func ValidateFilterValues(filterValues map[string][]string, maxValues, maxLength int) error {
+	for key, values := range filterValues {
+		if maxValues > 0 && len(values) > maxValues {
+			return fmt.Errorf("filter '%s' accepts at most %d values", key, maxValues)
+		}
+		for _, value := range values {
+			if len(value) > maxLength {
+				return fmt.Errorf("filter value for '%s' exceeds maximum length of %d", key, maxLength)
+			}
+		}
+	}
+	return nil
+}
+
 // ValidateOrderBy ensures the column name is allowed
 func ValidateOrderBy(orderBy string, allowedColumns []string) error {
 	if orderBy != "" && !contains(allowedColumns, orderBy) {
@@ -25,6 +25,16 @@ func MsgForTag(fe validator.FieldError) string {
 		return fmt.Sprintf("Maximum length is %s", fe.Param())
 	case "oneof":
 		return fmt.Sprintf("Value must be one of %s", fe.Param())
+	case "url":
+		return "Invalid URL"
+	case "fqdn":
+		return "Invalid domain name"
+	case "alias_format":
+		return fmt.Sprintf("Must be %d-%d characters of letters, numbers, '-' or '_'", AliasMinLength, AliasMaxLength)
+	case "alias_reserved":
+		return "This alias is reserved"
+	case "alias_blocked":
+		return "This alias is not allowed"
 	}
 	return fe.Error() // default error
 }